@@ -0,0 +1,391 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var agendaDays int
+var calendarMonth string
+
+type agendaItem struct {
+	TaskID   string `json:"task_id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+	Date     string `json:"date"`
+	Kind     string `json:"kind"` // due, scheduled
+}
+
+type agendaReport struct {
+	Overdue  []agendaItem `json:"overdue"`
+	Today    []agendaItem `json:"today"`
+	Upcoming []agendaItem `json:"upcoming"`
+}
+
+// loadActiveTaskRows - 削除・アーカイブされていない未完了タスクを取得
+func loadActiveTaskRows(config model.Config) ([]taskRow, error) {
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	noteMap := make(map[string]model.Note)
+	for _, note := range notes {
+		noteMap[note.ID] = note
+	}
+
+	var rows []taskRow
+	for _, task := range tasks {
+		note, exists := noteMap[task.NoteID]
+		if !exists || note.Deleted || note.Archived || task.Status == model.TaskStatusDone {
+			continue
+		}
+		rows = append(rows, taskRow{Task: task, Note: note})
+	}
+	return rows, nil
+}
+
+// buildAgenda - 期限（なければ予定日）で overdue / today / upcoming に振り分け
+func buildAgenda(rows []taskRow, now time.Time, days int) agendaReport {
+	today := now.Format("2006-01-02")
+	horizon := now.AddDate(0, 0, days).Format("2006-01-02")
+
+	report := agendaReport{Overdue: []agendaItem{}, Today: []agendaItem{}, Upcoming: []agendaItem{}}
+	for _, row := range rows {
+		item := agendaItem{
+			TaskID:   row.Task.ID,
+			Title:    row.Note.Title,
+			Status:   row.Task.Status,
+			Priority: row.Task.Priority,
+			Date:     row.Task.DueDate,
+			Kind:     "due",
+		}
+		if item.Date == "" {
+			item.Date = row.Task.ScheduledDate
+			item.Kind = "scheduled"
+		}
+		if item.Date == "" {
+			continue
+		}
+
+		switch {
+		case item.Date < today:
+			// 予定日を過ぎただけのタスクは今日に表示
+			if item.Kind == "scheduled" {
+				report.Today = append(report.Today, item)
+			} else {
+				report.Overdue = append(report.Overdue, item)
+			}
+		case item.Date == today:
+			report.Today = append(report.Today, item)
+		case item.Date <= horizon:
+			report.Upcoming = append(report.Upcoming, item)
+		}
+	}
+
+	for _, items := range [][]agendaItem{report.Overdue, report.Today, report.Upcoming} {
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].Date != items[j].Date {
+				return items[i].Date < items[j].Date
+			}
+			return model.PriorityRank(items[i].Priority) < model.PriorityRank(items[j].Priority)
+		})
+	}
+	return report
+}
+
+func printAgendaSection(title string, items []agendaItem, style func(a ...interface{}) string, groupByDate bool) {
+	fmt.Printf("%s (%d)\n", style(title), len(items))
+	if len(items) == 0 {
+		fmt.Println("  —")
+	}
+
+	lastDate := ""
+	for _, item := range items {
+		if groupByDate && item.Date != lastDate {
+			date, err := time.Parse("2006-01-02", item.Date)
+			label := item.Date
+			if err == nil {
+				label = date.Format("Mon 2006-01-02")
+			}
+			fmt.Printf("  %s\n", color.New(color.Bold).Sprint(label))
+			lastDate = item.Date
+		}
+
+		var meta []string
+		if !groupByDate {
+			meta = append(meta, item.Date)
+		}
+		if item.Kind == "scheduled" {
+			meta = append(meta, "scheduled")
+		}
+		if item.Priority != "" {
+			meta = append(meta, "!"+item.Priority)
+		}
+		meta = append(meta, item.Status)
+		fmt.Printf("    %-9s %s  %s\n", item.TaskID, item.Title, text.FgHiBlack.Sprintf("(%s)", strings.Join(meta, ", ")))
+	}
+	fmt.Println()
+}
+
+var agendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "Show overdue, today's and upcoming tasks",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateOutputFormat(); err != nil {
+			fatal(err)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		rows, err := loadActiveTaskRows(*config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		report := buildAgenda(rows, time.Now(), agendaDays)
+
+		switch outputFormat {
+		case "json":
+			jsonBytes, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				log.Fatalf("❌ Failed to convert to JSON: %v", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return
+		case "csv":
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"section", "task_id", "title", "status", "priority", "date", "kind"})
+			for _, section := range []struct {
+				name  string
+				items []agendaItem
+			}{{"overdue", report.Overdue}, {"today", report.Today}, {"upcoming", report.Upcoming}} {
+				for _, item := range section.items {
+					w.Write([]string{section.name, item.TaskID, item.Title, item.Status, item.Priority, item.Date, item.Kind})
+				}
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				fatal(err)
+			}
+			return
+		}
+
+		fmt.Println(strings.Repeat("=", 30))
+		fmt.Printf("Agenda: %s (+%d days)\n", time.Now().Format("Mon 2006-01-02"), agendaDays)
+		fmt.Println(strings.Repeat("=", 30))
+
+		printAgendaSection("⚠️  Overdue", report.Overdue, color.New(color.FgHiRed, color.Bold).SprintFunc(), false)
+		printAgendaSection("📌 Today", report.Today, color.New(color.FgHiYellow, color.Bold).SprintFunc(), false)
+		printAgendaSection("📅 Upcoming", report.Upcoming, color.New(color.FgCyan, color.Bold).SprintFunc(), true)
+	},
+}
+
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Show a month grid of notes created and tasks due",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateOutputFormat(); err != nil {
+			fatal(err)
+		}
+
+		month := time.Now()
+		if calendarMonth != "" {
+			parsed, err := time.ParseInLocation("2006-01", calendarMonth, time.Local)
+			if err != nil {
+				log.Fatalf("❌ Invalid month %q (use YYYY-MM)", calendarMonth)
+			}
+			month = parsed
+		}
+		first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+		prefix := first.Format("2006-01")
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		notes, _, err := store.LoadNotes(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load notes.json: %v", err)
+		}
+		tasks, _, err := store.LoadTasks(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load tasks.json: %v", err)
+		}
+
+		// 日付ごとの作成ノート数・期限タスク
+		created := make(map[int]int)
+		noteMap := make(map[string]model.Note)
+		for _, note := range notes {
+			noteMap[note.ID] = note
+			if note.Deleted || !strings.HasPrefix(note.CreatedAt, prefix) {
+				continue
+			}
+			if day, err := time.Parse("2006-01-02", strings.Split(note.CreatedAt, " ")[0]); err == nil {
+				created[day.Day()]++
+			}
+		}
+
+		due := make(map[int][]taskRow)
+		for _, task := range tasks {
+			note, exists := noteMap[task.NoteID]
+			if !exists || note.Deleted || !strings.HasPrefix(task.DueDate, prefix) {
+				continue
+			}
+			if day, err := time.Parse("2006-01-02", task.DueDate); err == nil {
+				due[day.Day()] = append(due[day.Day()], taskRow{Task: task, Note: note})
+			}
+		}
+
+		if outputFormat == "json" || outputFormat == "csv" {
+			type calendarDay struct {
+				Date         string   `json:"date"`
+				NotesCreated int      `json:"notes_created"`
+				TasksDue     []string `json:"tasks_due"`
+			}
+			var days []calendarDay
+			for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+				day := calendarDay{Date: d.Format("2006-01-02"), NotesCreated: created[d.Day()], TasksDue: []string{}}
+				for _, row := range due[d.Day()] {
+					day.TasksDue = append(day.TasksDue, row.Task.ID)
+				}
+				days = append(days, day)
+			}
+
+			if outputFormat == "csv" {
+				w := csv.NewWriter(os.Stdout)
+				w.Write([]string{"date", "notes_created", "tasks_due"})
+				for _, day := range days {
+					w.Write([]string{day.Date, fmt.Sprint(day.NotesCreated), strings.Join(day.TasksDue, ",")})
+				}
+				w.Flush()
+				if err := w.Error(); err != nil {
+					fatal(err)
+				}
+				return
+			}
+
+			jsonBytes, err := json.MarshalIndent(days, "", "  ")
+			if err != nil {
+				log.Fatalf("❌ Failed to convert to JSON: %v", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return
+		}
+
+		today := time.Now().Format("2006-01-02")
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleLight)
+		t.Style().Options.SeparateRows = true
+		t.SetTitle(first.Format("January 2006"))
+
+		header := table.Row{}
+		for _, wd := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+			header = append(header, text.FgGreen.Sprint(wd))
+		}
+		t.AppendHeader(header)
+
+		var columnConfigs []table.ColumnConfig
+		for i := 1; i <= 7; i++ {
+			columnConfigs = append(columnConfigs, table.ColumnConfig{Number: i, WidthMin: 6})
+		}
+		t.SetColumnConfigs(columnConfigs)
+
+		// 月曜始まりのグリッド
+		offset := (int(first.Weekday()) + 6) % 7
+		row := table.Row{}
+		for i := 0; i < offset; i++ {
+			row = append(row, "")
+		}
+		for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+			label := fmt.Sprintf("%2d", d.Day())
+			if d.Format("2006-01-02") == today {
+				label = text.Bold.Sprint(text.FgHiYellow.Sprint(label))
+			}
+			cell := label + "\n"
+			if n := created[d.Day()]; n > 0 {
+				cell += fmt.Sprintf("📝 %d", n)
+			}
+			cell += "\n"
+			if n := len(due[d.Day()]); n > 0 {
+				open := 0
+				for _, r := range due[d.Day()] {
+					if r.Task.Status != model.TaskStatusDone {
+						open++
+					}
+				}
+				mark := fmt.Sprintf("✅ %d", n)
+				if open > 0 {
+					mark = fmt.Sprintf("⏰ %d", open)
+					if d.Format("2006-01-02") < today {
+						mark = text.FgHiRed.Sprint(mark)
+					}
+				}
+				cell += mark
+			}
+			row = append(row, cell)
+			if len(row) == 7 {
+				t.AppendRow(row)
+				row = table.Row{}
+			}
+		}
+		if len(row) > 0 {
+			for len(row) < 7 {
+				row = append(row, "")
+			}
+			t.AppendRow(row)
+		}
+		t.Render()
+		fmt.Println("📝 notes created  ⏰ open tasks due  ✅ all tasks due done")
+
+		// 期限のあるタスクの一覧
+		var dueDays []int
+		for day := range due {
+			dueDays = append(dueDays, day)
+		}
+		sort.Ints(dueDays)
+		if len(dueDays) > 0 {
+			fmt.Println()
+		}
+		for _, day := range dueDays {
+			for _, r := range due[day] {
+				fmt.Printf("  %s  %-9s %s (%s)\n", r.Task.DueDate, r.Task.ID, r.Note.Title, r.Task.Status)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(agendaCmd)
+	rootCmd.AddCommand(calendarCmd)
+	agendaCmd.Flags().IntVar(&agendaDays, "days", 7, "Number of upcoming days to show")
+	calendarCmd.Flags().StringVar(&calendarMonth, "month", "", "Month to show (YYYY-MM, default: current month)")
+}
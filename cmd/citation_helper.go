@@ -0,0 +1,118 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+)
+
+// peopleNames - Person の一覧を "Family, Given" の文字列に変換
+func peopleNames(people []model.Person) []string {
+	var names []string
+	for _, p := range people {
+		names = append(names, p.String())
+	}
+	return names
+}
+
+// parsePeople - "Family, Given" の文字列を Person の一覧に変換
+func parsePeople(names []string) []model.Person {
+	var people []model.Person
+	for _, name := range names {
+		if strings.TrimSpace(name) != "" {
+			people = append(people, model.ParsePerson(name))
+		}
+	}
+	return people
+}
+
+// sourceToReference - Source を書き出し・引用用の Reference に変換
+func sourceToReference(source model.Source) util.Reference {
+	key := source.CitationKey
+	if key == "" {
+		key = source.SourceID
+	}
+	return util.Reference{
+		Type:        source.SourceType,
+		CitationKey: key,
+		Title:       source.Title,
+		Authors:     peopleNames(source.Authors),
+		Editors:     peopleNames(source.Editors),
+		Year:        source.Year,
+		Publisher:   source.Publisher,
+		Journal:     source.Journal,
+		Volume:      source.Volume,
+		Issue:       source.Issue,
+		Pages:       source.Pages,
+		DOI:         source.DOI,
+		ArXiv:       source.ArXiv,
+		ISBN:        source.ISBN,
+		URL:         source.URL,
+	}
+}
+
+// findSourceByRef - ソース ID または引用キー（先頭の @ は省略可）でソースを探す
+func findSourceByRef(sources []model.Source, ref string) (model.Source, bool) {
+	ref = strings.TrimPrefix(ref, "@")
+	for _, source := range sources {
+		if source.SourceID == ref {
+			return source, true
+		}
+	}
+	for _, source := range sources {
+		if source.CitationKey != "" && source.CitationKey == ref {
+			return source, true
+		}
+	}
+	return model.Source{}, false
+}
+
+// citationStyle - フラグ → config.citation_style → apa の順で引用スタイルを決める
+func citationStyle(flagValue string, config model.Config) (string, error) {
+	value := flagValue
+	if value == "" {
+		value = config.CitationStyle
+	}
+	if value == "" {
+		return "apa", nil
+	}
+	style := util.NormalizeCitationStyle(value)
+	if style == "" {
+		return "", fmt.Errorf("❌ Invalid citation style: %s. Must be one of %s", value, strings.Join(util.CitationStyles, ", "))
+	}
+	return style, nil
+}
+
+// resolveNoteCitations - ノート本文の [@citekey] を引用に置き換え、参考文献リストを付ける
+func resolveNoteCitations(body string, config model.Config) string {
+	if !strings.Contains(body, "@") {
+		return body
+	}
+
+	style, err := citationStyle("", config)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return body
+	}
+	sources, _, err := store.LoadSources(config)
+	if err != nil {
+		log.Printf("⚠️ Failed to load sources.json: %v", err)
+		return body
+	}
+
+	resolved, missing := util.ResolveCitations(body, func(key string) (util.Reference, bool) {
+		source, ok := findSourceByRef(sources, key)
+		return sourceToReference(source), ok
+	}, style)
+	for _, key := range missing {
+		log.Printf("⚠️ Unknown citation key: @%s", key)
+	}
+	return resolved
+}
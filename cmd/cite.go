@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var citeStyle string
+var citeInText bool
+
+var citeCmd = &cobra.Command{
+	Use:   "cite [source...]",
+	Short: "Render a formatted citation for sources (APA, Chicago, IEEE)",
+	Long: `Render a formatted citation for one or more sources.
+
+Sources can be given by source ID (s001) or citation key (@smith2020deep).
+The style defaults to citation_style in config.yaml, or APA.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Fatalf("❌ Error loading config: %v", err)
+		}
+
+		style, err := citationStyle(citeStyle, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		sources, _, err := store.LoadSources(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load sources.json: %v", err)
+		}
+
+		for i, arg := range args {
+			source, found := findSourceByRef(sources, arg)
+			if !found {
+				log.Fatalf("❌ Source %s not found", arg)
+			}
+			ref := sourceToReference(source)
+
+			if !citeInText {
+				fmt.Println(util.FormatReference(ref, style))
+				continue
+			}
+			inText := util.FormatInText(ref, util.Citation{Key: ref.CitationKey}, style, i+1)
+			if style == "ieee" {
+				fmt.Printf("[%s]\n", inText)
+			} else {
+				fmt.Printf("(%s)\n", inText)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(citeCmd)
+	citeCmd.Flags().StringVar(&citeStyle, "style", "", "Citation style (apa, chicago, ieee)")
+	citeCmd.Flags().BoolVar(&citeInText, "in-text", false, "Render the in-text citation instead of the reference entry")
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// truncateDisplay - 表示幅（全角文字は 2）が width に収まるよう切り詰める
+func truncateDisplay(s string, width int) string {
+	if text.StringWidthWithoutEscSequences(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	w := 0
+	for _, r := range s {
+		if w+text.RuneWidth(r) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		w += text.RuneWidth(r)
+	}
+	return b.String() + "…"
+}
+
+// padDisplay - 表示幅が width になるよう空白で埋める
+func padDisplay(s string, width int) string {
+	return text.Pad(s, width, ' ')
+}
@@ -0,0 +1,285 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var inboxStatsOnly bool
+
+// loadInboxNotes - 未処理の fleeting ノートを古い順に取得
+func loadInboxNotes(config model.Config) ([]model.Note, error) {
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	var inbox []model.Note
+	for _, note := range notes {
+		if note.NoteType != "fleeting" || note.Processed || note.Archived || note.Deleted {
+			continue
+		}
+		inbox = append(inbox, note)
+	}
+
+	sort.SliceStable(inbox, func(i, j int) bool {
+		return inbox[i].CreatedAt < inbox[j].CreatedAt
+	})
+
+	return inbox, nil
+}
+
+func noteAge(note model.Note, now time.Time) time.Duration {
+	createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", note.CreatedAt, time.Local)
+	if err != nil {
+		return 0
+	}
+	return now.Sub(createdAt)
+}
+
+func formatAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days > 0 {
+		return fmt.Sprintf("%dd", days)
+	}
+	hours := int(d.Hours())
+	if hours > 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+func printInboxStats(inbox []model.Note) {
+	fmt.Println(strings.Repeat("=", 30))
+	fmt.Printf("Inbox: %v unprocessed fleeting notes\n", len(inbox))
+	fmt.Println(strings.Repeat("=", 30))
+
+	if len(inbox) == 0 {
+		return
+	}
+
+	now := time.Now()
+	var total time.Duration
+	buckets := []struct {
+		label string
+		limit time.Duration
+		count int
+	}{
+		{"< 1 day", 24 * time.Hour, 0},
+		{"1-7 days", 7 * 24 * time.Hour, 0},
+		{"7-30 days", 30 * 24 * time.Hour, 0},
+		{"> 30 days", 1<<63 - 1, 0},
+	}
+
+	for _, note := range inbox {
+		age := noteAge(note, now)
+		total += age
+		for i := range buckets {
+			if age < buckets[i].limit {
+				buckets[i].count++
+				break
+			}
+		}
+	}
+
+	fmt.Printf("Oldest:  %s (%s)\n", formatAge(noteAge(inbox[0], now)), inbox[0].Title)
+	fmt.Printf("Average: %s\n", formatAge(total/time.Duration(len(inbox))))
+	for _, b := range buckets {
+		fmt.Printf("  %-10s %3d %s\n", b.label, b.count, strings.Repeat("█", b.count))
+	}
+	fmt.Println()
+}
+
+func prompt(reader *bufio.Reader, message string) string {
+	fmt.Print(message)
+	input, _ := reader.ReadString('\n')
+	return strings.TrimSpace(input)
+}
+
+// linkNoteTo - ノートのフロントマター `links:` にリンク先を追加
+func linkNoteTo(note model.Note, targetSeqID string, config model.Config) error {
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	targetID := ""
+	for _, n := range notes {
+		if n.SeqID == targetSeqID {
+			targetID = n.ID
+			break
+		}
+	}
+	if targetID == "" {
+		return fmt.Errorf("❌ Note with ID %s not found", targetSeqID)
+	}
+
+	notePath := filepath.Join(config.ZettelDir, note.ID+".md")
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+	frontMatter, body, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content))
+	if err != nil {
+		return fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	if slices.Contains(frontMatter.Links, targetID) {
+		return nil
+	}
+	frontMatter.Links = append(frontMatter.Links, targetID)
+
+	updatedContent := store.UpdateFrontMatter(&frontMatter, body)
+	if err := os.WriteFile(notePath, []byte(updatedContent), 0644); err != nil {
+		return fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+
+	return syncNoteRelations(note.ID, string(content), updatedContent, config)
+}
+
+// processInboxNote - 1件の fleeting ノートを対話的に処理する。false を返した場合は inbox を終了
+func processInboxNote(reader *bufio.Reader, note model.Note, index, total int, config model.Config) bool {
+	titleStyle := color.New(color.FgCyan, color.Bold).SprintFunc()
+	subStyle := color.New(color.FgHiGreen).SprintFunc()
+
+	content, err := os.ReadFile(filepath.Join(config.ZettelDir, note.ID+".md"))
+	if err != nil {
+		log.Printf("⚠️ Failed to read note file: %v", err)
+		return true
+	}
+	_, body, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content))
+	if err != nil {
+		body = string(content)
+	}
+
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("(%d/%d) [%v] %v\n", index, total, titleStyle(note.SeqID), titleStyle(note.Title))
+	fmt.Printf("Created: %v (%s ago)\n", subStyle(note.CreatedAt), formatAge(noteAge(note, time.Now())))
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Println(body)
+	fmt.Println()
+
+	for {
+		action := prompt(reader, "[p]romote [m]erge [l]ink [t]ag [a]rchive [d]elete [x] done [s]kip [q]uit: ")
+
+		switch action {
+		case "p":
+			noteType := prompt(reader, "Promote to (permanent/literature) [permanent]: ")
+			if noteType == "" {
+				noteType = "permanent"
+			}
+			sourceID := prompt(reader, "Source ID (optional): ")
+			_, promoted, err := promoteNote(note.SeqID, noteType, sourceID, config)
+			if err != nil {
+				log.Print(errorMessage(err))
+				continue
+			}
+			fmt.Printf("✅ Promoted to %s note %s\n", noteType, promoted.SeqID)
+		case "m":
+			targetID := prompt(reader, "Merge into note ID: ")
+			if _, err := mergeNotes(targetID, note.SeqID, config); err != nil {
+				log.Print(errorMessage(err))
+				continue
+			}
+			fmt.Printf("✅ Merged into %s\n", targetID)
+		case "l":
+			targetID := prompt(reader, "Link to note ID: ")
+			if err := linkNoteTo(note, targetID, config); err != nil {
+				log.Print(errorMessage(err))
+			} else {
+				fmt.Printf("✅ Linked to %s\n", targetID)
+			}
+			continue
+		case "t":
+			tagName := prompt(reader, "Tag: ")
+			if tagName == "" {
+				continue
+			}
+			if err := AddTagToNote(note.SeqID, tagName, config); err != nil {
+				log.Print(errorMessage(err))
+			} else {
+				fmt.Printf("✅ Tagged with '%s'\n", tagName)
+			}
+			continue
+		case "a":
+			if err := store.ArchiveNote(note.SeqID, config); err != nil {
+				log.Print(errorMessage(err))
+				continue
+			}
+		case "d":
+			if err := store.MoveNoteToTrash(note.SeqID, config); err != nil {
+				log.Print(errorMessage(err))
+				continue
+			}
+		case "x":
+		case "s", "":
+			return true
+		case "q":
+			return false
+		default:
+			fmt.Println("Unknown action.")
+			continue
+		}
+
+		if err := store.MarkNoteProcessed(note.ID, config); err != nil {
+			log.Printf("⚠️ Failed to mark note as processed: %v", err)
+		}
+		return true
+	}
+}
+
+// inboxCmd represents the inbox command
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Process unprocessed fleeting notes, oldest first",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		inbox, err := loadInboxNotes(*config)
+		if err != nil {
+			fatal(err)
+		}
+
+		printInboxStats(inbox)
+
+		if inboxStatsOnly || len(inbox) == 0 {
+			return
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+		for i, note := range inbox {
+			if !processInboxNote(reader, note, i+1, len(inbox), *config) {
+				break
+			}
+		}
+
+		remaining, err := loadInboxNotes(*config)
+		if err == nil {
+			fmt.Printf("📥 %d fleeting notes left in inbox\n", len(remaining))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inboxCmd)
+	inboxCmd.Flags().BoolVar(&inboxStatsOnly, "stats", false, "Show inbox statistics only")
+}
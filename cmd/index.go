@@ -248,6 +248,11 @@ var newIndexCmd = &cobra.Command{
 		log.Printf("Opening %q (Title: %q)...", newIndexStr, indexTitle)
 		time.Sleep(2 * time.Second)
 
+		initialContent, err := os.ReadFile(newIndexStr)
+		if err != nil {
+			log.Printf("❌ Failed to read Markdown file: %v", err)
+		}
+
 		err = util.OpenEditor(newIndexStr, *config)
 		if err != nil {
 			log.Printf("❌ Failed to open editor: %v\n", err)
@@ -266,6 +271,11 @@ var newIndexCmd = &cobra.Command{
 			body = string(mdContent) // フロントマターの解析に失敗した場合、全文をセット
 		}
 
+		// フロントマターの tags / links を JSON に反映
+		if err := syncNoteRelations(noteID, string(initialContent), string(mdContent), *config); err != nil {
+			log.Printf("⚠️ Failed to sync tags and links: %v", err)
+		}
+
 		notes, noteJsonPath, err := store.LoadNotes(*config)
 		if err != nil {
 			log.Printf("❌ Error loading notes from JSON: %v", err)
@@ -580,7 +590,7 @@ var showIndexCmd = &cobra.Command{
 
 		// Render Markdown content unless --meta flag is used
 		if !taskMeta {
-			renderedContent, err := glamour.Render(body, "dark")
+			renderedContent, err := glamour.Render(resolveNoteCitations(body, *config), "dark")
 			if err != nil {
 				log.Printf("⚠️ Failed to render markdown content: %v", err)
 			} else {
@@ -633,6 +643,12 @@ var editIndexCmd = &cobra.Command{
 					log.Printf("⚠️ Backup failed: %v", err)
 				}
 
+				originalContent, err := os.ReadFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"))
+				if err != nil {
+					log.Printf("❌ Failed to read note file: %v", err)
+					os.Exit(1)
+				}
+
 				fmt.Printf("Found %v, opening...\n", filepath.Join(config.ZettelDir, notes[i].ID+".md"))
 				time.Sleep(2 * time.Second)
 
@@ -669,6 +685,11 @@ var editIndexCmd = &cobra.Command{
 					log.Printf("❌ Error writing updated note file: %v", err)
 				}
 
+				// フロントマターの tags / links を JSON に反映
+				if err := syncNoteRelations(notes[i].ID, string(originalContent), updatedContent, *config); err != nil {
+					log.Printf("⚠️ Failed to sync tags and links: %v", err)
+				}
+
 				notes[i].Title = frontMatter.Title
 				// notes[i].Links = frontMatter.Links
 				notes[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
@@ -26,6 +26,7 @@ var searchQuery string
 var trash bool
 var archive bool
 var pageSize int
+var listProjects []string
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
@@ -80,10 +81,21 @@ var listCmd = &cobra.Command{
 			tagMap[tag.ID] = tag.Name
 		}
 
+		inProjects, err := projectFilterIDs(listProjects, notes, *config)
+		if err != nil {
+			log.Printf("%v", err)
+			os.Exit(1)
+		}
+
 		filteredNotes := []model.Note{}
 		noteTagDisplay := make(map[string][]string)
 
 		for _, note := range notes {
+			// `--project` のフィルタ（いずれかのプロジェクトに属していれば表示）
+			if inProjects != nil && !inProjects[note.ID] {
+				continue
+			}
+
 			// Apply filters
 			if literatureTrash {
 				if !note.Deleted {
@@ -236,6 +248,7 @@ var listCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().StringSliceVar(&searchTags, "tag", []string{}, "Specify tags")
+	listCmd.Flags().StringSliceVar(&listProjects, "project", []string{}, "Filter by project ID or name (repeatable, matches any)")
 	listCmd.Flags().StringVar(&from, "from", "", "Filter by start date (YYYY-MM-DD)")
 	listCmd.Flags().StringVar(&to, "to", "", "Filter by end date (YYYY-MM-DD)")
 	listCmd.Flags().StringVarP(&searchQuery, "search", "q", "", "Search by title or content")
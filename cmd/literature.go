@@ -35,13 +35,16 @@ var literatureArchive bool
 var literatureForceDelete bool
 var literatureRestoreTrash bool
 var literatureRestoreArchive bool
+var literatureSource string
 
 func createNewLiteratureNote(literatureTitle string, config model.Config) (string, model.Note, error) {
-	t := time.Now()
-	noteId := fmt.Sprintf("%d%02d%02d%02d%02d%02d",
-		t.Year(), t.Month(), t.Day(),
-		t.Hour(), t.Minute(), t.Second())
-	createdAt := t.Format("2006-01-02 15:04:05")
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	// 同じ秒に作成したノートと ID が重複しないようにする
+	noteId := nextFreeNoteID(notes, config)
+	createdAt := time.Now().Format("2006-01-02 15:04:05")
 
 	// Create front matter
 	frontMatter := model.NoteFrontMatter{
@@ -122,6 +125,27 @@ var newLiteratureCmd = &cobra.Command{
 			log.Printf("⚠️ Trash cleanup failed: %v", err)
 		}
 
+		// --source / --page などはノート作成前に検証する
+		var sourceID, locator string
+		if literatureSource != "" {
+			sources, _, err := store.LoadSources(*config)
+			if err != nil {
+				log.Fatalf("❌ Failed to load sources.json: %v", err)
+			}
+			source, found := findSourceByRef(sources, literatureSource)
+			if !found {
+				log.Fatalf("❌ Source %s not found", literatureSource)
+			}
+			sourceID = source.SourceID
+		}
+		locator, err = locatorFromFlags()
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if locator != "" && sourceID == "" {
+			log.Fatalf("❌ --page, --chapter and --timestamp require --source")
+		}
+
 		if len(literatureTags) > 0 {
 			if err := store.CreateNewTag(literatureTags, *config); err != nil {
 				log.Printf("❌ Failed to create tag: %v\n", err)
@@ -135,6 +159,12 @@ var newLiteratureCmd = &cobra.Command{
 			return
 		}
 
+		if sourceID != "" {
+			if _, err := store.LinkSourceNote(model.SourceNote{SourceID: sourceID, NoteID: note.ID, Locator: locator}, *config); err != nil {
+				log.Printf("❌ Failed to link note to source: %v\n", err)
+			}
+		}
+
 		for _, tagID := range literatureTags {
 			if err := store.InsertNoteTag(note.ID, tagID, *config); err != nil {
 				log.Printf("❌ Failed to insert note-tag relation: %v\n", err)
@@ -144,6 +174,11 @@ var newLiteratureCmd = &cobra.Command{
 		log.Printf("Opening %q (Title: %q)...", newLiteratureStr, literatureTitle)
 		time.Sleep(2 * time.Second)
 
+		initialContent, err := os.ReadFile(newLiteratureStr)
+		if err != nil {
+			log.Printf("❌ Failed to read Markdown file: %v", err)
+		}
+
 		err = util.OpenEditor(newLiteratureStr, *config)
 		if err != nil {
 			log.Printf("❌ Failed to open editor: %v\n", err)
@@ -162,6 +197,11 @@ var newLiteratureCmd = &cobra.Command{
 			body = string(mdContent) // フロントマターの解析に失敗した場合、全文をセット
 		}
 
+		// フロントマターの tags / links を JSON に反映
+		if err := syncNoteRelations(noteID, string(initialContent), string(mdContent), *config); err != nil {
+			log.Printf("⚠️ Failed to sync tags and links: %v", err)
+		}
+
 		notes, noteJsonPath, err := store.LoadNotes(*config)
 		if err != nil {
 			log.Printf("❌ Error loading notes from JSON: %v", err)
@@ -476,7 +516,7 @@ var showLiteratureCmd = &cobra.Command{
 
 		// Render Markdown content unless --meta flag is used
 		if !taskMeta {
-			renderedContent, err := glamour.Render(body, "dark")
+			renderedContent, err := glamour.Render(resolveNoteCitations(body, *config), "dark")
 			if err != nil {
 				log.Printf("⚠️ Failed to render markdown content: %v", err)
 			} else {
@@ -529,6 +569,12 @@ var editLiteratureCmd = &cobra.Command{
 					log.Printf("⚠️ Backup failed: %v", err)
 				}
 
+				originalContent, err := os.ReadFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"))
+				if err != nil {
+					log.Printf("❌ Failed to read note file: %v", err)
+					os.Exit(1)
+				}
+
 				fmt.Printf("Found %v, opening...\n", filepath.Join(config.ZettelDir, notes[i].ID+".md"))
 				time.Sleep(2 * time.Second)
 
@@ -565,6 +611,11 @@ var editLiteratureCmd = &cobra.Command{
 					log.Printf("❌ Error writing updated note file: %v", err)
 				}
 
+				// フロントマターの tags / links を JSON に反映
+				if err := syncNoteRelations(notes[i].ID, string(originalContent), updatedContent, *config); err != nil {
+					log.Printf("⚠️ Failed to sync tags and links: %v", err)
+				}
+
 				notes[i].Title = frontMatter.Title
 				// notes[i].Links = frontMatter.Links
 				notes[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
@@ -758,6 +809,8 @@ func init() {
 	literatureCmd.AddCommand(restoreLiteratureCmd)
 	rootCmd.AddCommand(literatureCmd)
 	newLiteratureCmd.Flags().StringSliceVarP(&literatureTags, "tag", "t", []string{}, "Specify tags")
+	newLiteratureCmd.Flags().StringVar(&literatureSource, "source", "", "Link the note to a source (source ID or citation key)")
+	addLocatorFlags(newLiteratureCmd)
 	literatureListCmd.Flags().StringSliceVarP(&literatureTags, "tag", "t", []string{}, "Filter by tags")
 	literatureListCmd.Flags().StringVar(&literatureFrom, "from", "", "Filter by start date (YYYY-MM-DD)")
 	literatureListCmd.Flags().StringVar(&literatureTo, "to", "", "Filter by end date (YYYY-MM-DD)")
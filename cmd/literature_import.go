@@ -0,0 +1,611 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	literatureImportFormat string
+	literatureImportPer    string
+	literatureImportSource string
+	literatureImportBook   string
+	literatureImportTitle  string
+	literatureImportAuthor []string
+	literatureImportType   string
+	literatureImportTags   []string
+	literatureImportDryRun bool
+)
+
+// ノート本文のうち取り込みで書き換える範囲。範囲外に書いたメモは再取り込みでも残る
+const (
+	highlightBlockStart = "<!-- ztl:highlights -->"
+	highlightBlockEnd   = "<!-- /ztl:highlights -->"
+)
+
+// detectHighlightFormat - 拡張子（判定できなければ内容）からハイライトの形式を判定
+func detectHighlightFormat(path string, data []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt":
+		return "kindle", nil
+	case ".csv":
+		return "csv", nil
+	case ".json":
+		return "pdf-json", nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.Contains(data, []byte("==========")):
+		return "kindle", nil
+	case bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("{")):
+		return "pdf-json", nil
+	}
+	return "", fmt.Errorf("❌ Cannot detect highlight format of %s. Use --format (%s)", path, strings.Join(util.HighlightFormats, ", "))
+}
+
+// parseHighlights - 形式に応じてハイライトを書籍ごとに読み込む
+func parseHighlights(format, path string, data []byte) ([]util.HighlightBook, error) {
+	switch format {
+	case "kindle":
+		return util.ParseKindleClippings(bytes.NewReader(data))
+	case "csv":
+		return util.ParseHighlightCSV(bytes.NewReader(data))
+	case "pdf-json":
+		book, err := util.ParsePDFAnnotationsJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		if book.Title == "" {
+			book.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		return []util.HighlightBook{book}, nil
+	}
+	return nil, fmt.Errorf("unknown format: %s. Must be one of %s", format, strings.Join(util.HighlightFormats, ", "))
+}
+
+// highlightLocator - ページ → 位置番号 → 章 の順でロケーターを決める
+func highlightLocator(h util.Highlight) string {
+	switch {
+	case h.Page != "":
+		if locator, err := model.PageLocator(h.Page); err == nil {
+			return locator
+		}
+		return "p. " + h.Page // ローマ数字のページなど
+	case h.Location != "":
+		return model.LocationLocator(h.Location)
+	case h.Chapter != "":
+		return model.ChapterLocator(h.Chapter)
+	}
+	return ""
+}
+
+// highlightHash - 空白の揺れを無視して安定したハッシュを作る
+func highlightHash(parts ...string) string {
+	h := sha1.New()
+	for _, part := range parts {
+		h.Write([]byte(strings.Join(strings.Fields(part), " ")))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// highlightGroup - 1 つのノートにまとめるハイライト
+type highlightGroup struct {
+	Key        string
+	Title      string
+	Locator    string
+	Highlights []util.Highlight
+}
+
+// groupHighlights - ハイライトごと、または章ごとにノートの単位へまとめる
+func groupHighlights(book util.HighlightBook, source model.Source, per string) []highlightGroup {
+	var groups []highlightGroup
+	if per == "highlight" {
+		for _, h := range book.Highlights {
+			title := noteExcerpt(h.Text, 60)
+			if title == "" {
+				title = noteExcerpt(h.Note, 60)
+			}
+			// メモを書き換えても別のノートにならないよう、本文があればメモはキーに含めない
+			identity := h.Text
+			if identity == "" {
+				identity = h.Note
+			}
+			groups = append(groups, highlightGroup{
+				Key:        highlightHash(source.SourceID, "highlight", h.Page, h.Location, identity),
+				Title:      title,
+				Locator:    highlightLocator(h),
+				Highlights: []util.Highlight{h},
+			})
+		}
+		return groups
+	}
+
+	index := make(map[string]int)
+	for _, h := range book.Highlights {
+		i, ok := index[h.Chapter]
+		if !ok {
+			title := source.Title + " — Highlights"
+			if h.Chapter != "" {
+				title = source.Title + " — " + h.Chapter
+			}
+			groups = append(groups, highlightGroup{
+				Key:   highlightHash(source.SourceID, "chapter", h.Chapter),
+				Title: title,
+			})
+			i = len(groups) - 1
+			index[h.Chapter] = i
+		}
+		groups[i].Highlights = append(groups[i].Highlights, h)
+	}
+	for i, g := range groups {
+		// 章名は並べ替えに使えないため、最初のハイライトの位置をロケーターにする
+		first := g.Highlights[0]
+		first.Chapter = ""
+		if groups[i].Locator = highlightLocator(first); groups[i].Locator == "" && g.Highlights[0].Chapter != "" {
+			groups[i].Locator = model.ChapterLocator(g.Highlights[0].Chapter)
+		}
+	}
+	return groups
+}
+
+// renderHighlightBlock - ハイライトを引用ブロックとメモの Markdown にする
+func renderHighlightBlock(highlights []util.Highlight) string {
+	var b strings.Builder
+	b.WriteString(highlightBlockStart + "\n")
+	for _, h := range highlights {
+		b.WriteString("\n")
+		if h.Text != "" {
+			for _, line := range strings.Split(h.Text, "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+			b.WriteString("\n")
+		}
+		h.Chapter = ""
+		if locator := highlightLocator(h); locator != "" {
+			b.WriteString("*" + locator + "*\n\n")
+		}
+		if h.Note != "" {
+			b.WriteString(h.Note + "\n\n")
+		}
+	}
+	b.WriteString(highlightBlockEnd + "\n")
+	return b.String()
+}
+
+// replaceHighlightBlock - 本文のハイライト部分だけを差し替える。範囲が見つからなければ false
+func replaceHighlightBlock(body, block string) (string, bool) {
+	start := strings.Index(body, highlightBlockStart)
+	end := strings.Index(body, highlightBlockEnd)
+	if start < 0 || end < start {
+		return body, false
+	}
+	end += len(highlightBlockEnd)
+	if end < len(body) && body[end] == '\n' {
+		end++
+	}
+	return body[:start] + block + body[end:], true
+}
+
+// findHighlightSource - 取り込み元の書名・著者に対応する既存ソースを探す（副題の有無は問わない）
+func findHighlightSource(sources []model.Source, candidate model.Source) int {
+	if i := findDuplicateSource(sources, candidate); i >= 0 {
+		return i
+	}
+	mainTitle := func(title string) string {
+		if before, _, ok := strings.Cut(title, ":"); ok {
+			title = before
+		}
+		if before, _, ok := strings.Cut(title, " ("); ok {
+			title = before
+		}
+		return normalizeTitle(title)
+	}
+	for i, s := range sources {
+		if mainTitle(s.Title) != "" && mainTitle(s.Title) == mainTitle(candidate.Title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// earliestHighlightDate - 最初にハイライトした日（yyyy-mm-dd）。不明なら空
+func earliestHighlightDate(highlights []util.Highlight) string {
+	earliest := ""
+	for _, h := range highlights {
+		if h.AddedAt != "" && (earliest == "" || h.AddedAt < earliest) {
+			earliest = h.AddedAt
+		}
+	}
+	if len(earliest) >= 10 {
+		return earliest[:10]
+	}
+	return ""
+}
+
+// resolveHighlightSource - 書籍に対応するソースを探し、なければ作成する。既存ソースは空の著者と読書ステータスだけ補う
+func resolveHighlightSource(book util.HighlightBook, sourceType string, dryRun bool, config model.Config) (model.Source, string, error) {
+	sources, sourcesJsonPath, err := store.LoadSources(config)
+	if err != nil {
+		return model.Source{}, "", fmt.Errorf("❌ Failed to load sources.json: %w", err)
+	}
+
+	candidate := model.Source{Title: book.Title, Authors: model.ParsePeople(book.Author)}
+	i := -1
+	if literatureImportSource != "" {
+		source, found := findSourceByRef(sources, literatureImportSource)
+		if !found {
+			return model.Source{}, "", fmt.Errorf("❌ Source %s not found", literatureImportSource)
+		}
+		for j := range sources {
+			if sources[j].SourceID == source.SourceID {
+				i = j
+			}
+		}
+	} else {
+		i = findHighlightSource(sources, candidate)
+	}
+
+	started := earliestHighlightDate(book.Highlights)
+	action := "unchanged"
+	if i < 0 {
+		candidate.SourceType = sourceType
+		candidate.CitationKey = generateCitationKey(candidate, sources)
+		candidate.SourceID = store.GetNextSourceID(sources)
+		candidate.Status = model.SourceStatusReading
+		candidate.StartedAt = started
+		candidate.CreatedAt = time.Now().Format("2006-01-02 15:04:05")
+		sources = append(sources, candidate)
+		i, action = len(sources)-1, "added"
+	} else {
+		source := &sources[i]
+		if len(source.Authors) == 0 && len(candidate.Authors) > 0 {
+			source.Authors = candidate.Authors
+			action = "updated"
+		}
+		// ハイライトがある = 読み始めている
+		if source.Status == model.SourceStatusToRead || source.Status == "" {
+			source.Status = model.SourceStatusReading
+			if source.StartedAt == "" {
+				source.StartedAt = started
+			}
+			action = "updated"
+		}
+	}
+	if !dryRun && action != "unchanged" {
+		if err := store.SaveUpdatedJson(sources, sourcesJsonPath); err != nil {
+			return model.Source{}, "", fmt.Errorf("❌ Failed to update sources.json: %w", err)
+		}
+	}
+	return sources[i], action, nil
+}
+
+// writeHighlightNote - ハイライトから文献ノートのファイルを作成し、notes に追加する
+func writeHighlightNote(group highlightGroup, block string, notes *[]model.Note, config model.Config) (model.Note, error) {
+	noteID := nextFreeNoteID(*notes, config)
+	createdAt := time.Now().Format("2006-01-02 15:04:05")
+
+	frontMatter := model.NoteFrontMatter{
+		ID:        noteID,
+		Title:     group.Title,
+		NoteType:  "literature",
+		Tags:      literatureImportTags,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	frontMatterBytes, err := yaml.Marshal(frontMatter)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("failed to convert to YAML: %w", err)
+	}
+	body := fmt.Sprintf("## %s\n\n%s", group.Title, block)
+	content := fmt.Sprintf("---\n%s---\n\n%s", string(frontMatterBytes), body)
+
+	filePath := filepath.Join(config.ZettelDir, noteID+".md")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return model.Note{}, fmt.Errorf("failed to create note file (%s): %w", filePath, err)
+	}
+
+	note := model.Note{
+		ID:        noteID,
+		SeqID:     store.GetNextNoteID(*notes),
+		Title:     group.Title,
+		NoteType:  "literature",
+		Content:   body,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	*notes = append(*notes, note)
+
+	if err := syncNoteRelations(noteID, "", content, config); err != nil {
+		return model.Note{}, err
+	}
+	return note, nil
+}
+
+// updateHighlightNote - 既存ノートのハイライト部分だけを書き換える。範囲が消されていれば false
+func updateHighlightNote(note *model.Note, block string, config model.Config) (bool, error) {
+	path := noteFilePath(*note, config)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("❌ Error reading note file: %w", err)
+	}
+	frontMatter, body, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content))
+	if err != nil {
+		return false, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	body, ok := replaceHighlightBlock(body, block)
+	if !ok {
+		return false, nil
+	}
+	frontMatter.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	if err := os.WriteFile(path, []byte(store.UpdateFrontMatter(&frontMatter, body)), 0644); err != nil {
+		return false, fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+	note.Content = body
+	note.UpdatedAt = frontMatter.UpdatedAt
+	return true, nil
+}
+
+type highlightImportResult struct {
+	Action  string
+	Note    model.Note
+	Locator string
+	Count   int
+}
+
+// importHighlights - 1 冊分のハイライトを文献ノートとして取り込み、ソースに紐づける。
+// 取り込み済みのハイライトは highlight_imports.json で判定し、変わっていなければ何もしない
+func importHighlights(source model.Source, groups []highlightGroup, dryRun bool, config model.Config) ([]highlightImportResult, error) {
+	notes, notesJsonPath, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	imports, importsJsonPath, err := store.LoadHighlightImports(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load highlight_imports.json: %w", err)
+	}
+
+	importIndex := make(map[string]int)
+	for i, imp := range imports {
+		importIndex[imp.Key] = i
+	}
+	noteIndex := func(id string) int {
+		for i, note := range notes {
+			if note.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	var results []highlightImportResult
+	for _, group := range groups {
+		block := renderHighlightBlock(group.Highlights)
+		hash := highlightHash(block)
+		result := highlightImportResult{Locator: group.Locator, Count: len(group.Highlights)}
+
+		i, imported := importIndex[group.Key]
+		n := -1
+		if imported {
+			n = noteIndex(imports[i].NoteID)
+		}
+
+		switch {
+		case imported && n >= 0 && notes[n].Deleted:
+			// 削除したノートは取り込み直さない
+			result.Action, result.Note = "skipped", notes[n]
+		case imported && n >= 0 && imports[i].Hash == hash:
+			result.Action, result.Note = "unchanged", notes[n]
+		case imported && n >= 0:
+			result.Action, result.Note = "updated", notes[n]
+			if !dryRun {
+				ok, err := updateHighlightNote(&notes[n], block, config)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					log.Printf("⚠️ Highlight block was removed from note %s. Skipping", notes[n].ID)
+					result.Action = "skipped"
+					break
+				}
+				result.Note = notes[n]
+				imports[i].Hash = hash
+				imports[i].ImportedAt = now
+			}
+		default:
+			// 未取り込み、またはノートが完全に削除されている
+			result.Action, result.Note = "added", model.Note{Title: group.Title}
+			if !dryRun {
+				note, err := writeHighlightNote(group, block, &notes, config)
+				if err != nil {
+					return nil, err
+				}
+				result.Note = note
+				record := model.HighlightImport{Key: group.Key, SourceID: source.SourceID, NoteID: note.ID, Hash: hash, ImportedAt: now}
+				if imported {
+					imports[i] = record
+				} else {
+					imports = append(imports, record)
+					importIndex[group.Key] = len(imports) - 1
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	changed := false
+	for _, r := range results {
+		changed = changed || r.Action == "added" || r.Action == "updated"
+	}
+	if dryRun || !changed {
+		return results, nil
+	}
+	if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+		return nil, fmt.Errorf("❌ Failed to update notes.json: %w", err)
+	}
+	if err := store.SaveUpdatedJson(imports, importsJsonPath); err != nil {
+		return nil, fmt.Errorf("❌ Failed to update highlight_imports.json: %w", err)
+	}
+	for _, r := range results {
+		if r.Action == "skipped" || r.Note.ID == "" {
+			continue
+		}
+		if _, err := store.LinkSourceNote(model.SourceNote{SourceID: source.SourceID, NoteID: r.Note.ID, Locator: r.Locator}, config); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+var literatureImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import highlights from Kindle, Kobo/Readwise CSV or PDF annotations",
+	Long: `Import highlights as literature notes linked to their source.
+
+Supported formats:
+  kindle    Kindle "My Clippings.txt"
+  csv       Readwise / Kobo style CSV exports (Highlight, Book Title, Note, ...)
+  pdf-json  Annotated PDF dumps (e.g. pdfannots --format json)
+
+A source is found by title (or created) for each book. One literature note is
+created per highlight, or per chapter with --per chapter. Importing the same file
+again only adds new highlights and refreshes the highlight block of existing
+notes; text written outside the block is kept.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if literatureImportPer != "highlight" && literatureImportPer != "chapter" {
+			log.Fatalf("❌ Invalid --per: %s. Must be highlight or chapter", literatureImportPer)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatalf("❌ Failed to read %s: %v", args[0], err)
+		}
+		format := literatureImportFormat
+		if format == "" {
+			if format, err = detectHighlightFormat(args[0], data); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+		books, err := parseHighlights(format, args[0], data)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		if literatureImportBook != "" {
+			var filtered []util.HighlightBook
+			for _, book := range books {
+				if strings.Contains(strings.ToLower(book.Title), strings.ToLower(literatureImportBook)) {
+					filtered = append(filtered, book)
+				}
+			}
+			books = filtered
+		}
+		if len(books) == 0 {
+			log.Fatalf("❌ No highlights found in %s", args[0])
+		}
+		if len(books) > 1 && (literatureImportSource != "" || literatureImportTitle != "" || len(literatureImportAuthor) > 0) {
+			log.Fatalf("❌ --source, --title and --author need a single book. Narrow it down with --book")
+		}
+		if literatureImportTitle != "" {
+			books[0].Title = literatureImportTitle
+		}
+		if len(literatureImportAuthor) > 0 {
+			books[0].Author = model.JoinPeople(parseAuthorFlags(literatureImportAuthor))
+		}
+
+		sourceType := literatureImportType
+		if sourceType == "" {
+			sourceType = "book"
+			if format == "pdf-json" {
+				sourceType = "paper"
+			}
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Fatalf("❌ Error loading config: %v", err)
+		}
+
+		prefix := "✅"
+		if literatureImportDryRun {
+			prefix = "🔍 (dry run)"
+		}
+		for _, book := range books {
+			if book.Title == "" {
+				log.Printf("⚠️ Skipping %d highlights without a book title", len(book.Highlights))
+				continue
+			}
+
+			source, sourceAction, err := resolveHighlightSource(book, sourceType, literatureImportDryRun, *config)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			results, err := importHighlights(source, groupHighlights(book, source, literatureImportPer), literatureImportDryRun, *config)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+
+			fmt.Printf("\n📚 %s %s (%s)\n", source.SourceID, source.Title, sourceAction)
+			counts := make(map[string]int)
+			t := table.NewWriter()
+			t.SetOutputMirror(os.Stdout)
+			t.SetStyle(table.StyleDouble)
+			t.AppendHeader(table.Row{
+				text.FgGreen.Sprintf("Action"), text.FgGreen.Sprintf("Note ID"), text.FgGreen.Sprintf("Locator"),
+				text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Title")), text.FgGreen.Sprintf("Highlights"),
+			})
+			for _, r := range results {
+				counts[r.Action]++
+				action := r.Action
+				switch r.Action {
+				case "added":
+					action = text.FgHiGreen.Sprint(action)
+				case "updated":
+					action = text.FgHiYellow.Sprint(action)
+				case "skipped":
+					action = text.FgHiRed.Sprint(action)
+				}
+				t.AppendRow(table.Row{action, r.Note.SeqID, r.Locator, truncateDisplay(r.Note.Title, 50), r.Count})
+			}
+			t.Render()
+
+			fmt.Printf("%s %d highlights (%s) → %d notes: %d added, %d updated, %d unchanged, %d skipped\n",
+				prefix, len(book.Highlights), format, len(results), counts["added"], counts["updated"], counts["unchanged"], counts["skipped"])
+		}
+	},
+}
+
+func init() {
+	literatureCmd.AddCommand(literatureImportCmd)
+	literatureImportCmd.Flags().StringVar(&literatureImportFormat, "format", "", "Input format (kindle, csv, pdf-json). Detected from the extension by default")
+	literatureImportCmd.Flags().StringVar(&literatureImportPer, "per", "highlight", "Create one note per highlight or per chapter (highlight, chapter)")
+	literatureImportCmd.Flags().StringVar(&literatureImportSource, "source", "", "Attach highlights to this source (ID or citation key) instead of matching by title")
+	literatureImportCmd.Flags().StringVar(&literatureImportBook, "book", "", "Only import books whose title contains this text")
+	literatureImportCmd.Flags().StringVar(&literatureImportTitle, "title", "", "Override the book title (e.g. for PDF dumps without one)")
+	literatureImportCmd.Flags().StringArrayVar(&literatureImportAuthor, "author", []string{}, "Override the author (\"Family, Given\", repeatable)")
+	literatureImportCmd.Flags().StringVar(&literatureImportType, "type", "", "Type of a newly created source (default book, paper for pdf-json)")
+	literatureImportCmd.Flags().StringSliceVarP(&literatureImportTags, "tag", "t", []string{}, "Tags for the imported notes")
+	literatureImportCmd.Flags().BoolVar(&literatureImportDryRun, "dry-run", false, "Show what would be imported without writing anything")
+}
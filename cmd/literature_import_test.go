@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+)
+
+func TestHighlightGroupKey(t *testing.T) {
+	source := model.Source{SourceID: "s001", Title: "The Pragmatic Programmer"}
+	key := func(per string, h util.Highlight) string {
+		t.Helper()
+		groups := groupHighlights(util.HighlightBook{Highlights: []util.Highlight{h}}, source, per)
+		if len(groups) != 1 {
+			t.Fatalf("groupHighlights() = %d groups, want 1", len(groups))
+		}
+		return groups[0].Key
+	}
+
+	base := util.Highlight{Text: "Care about your craft.", Note: "Why else?", Chapter: "Preface", Page: "12", Location: "180-182"}
+	same := map[string]util.Highlight{
+		"edited note":       {Text: base.Text, Note: "Rewritten note", Chapter: base.Chapter, Page: base.Page, Location: base.Location},
+		"whitespace change": {Text: "Care  about\nyour craft. ", Note: base.Note, Chapter: base.Chapter, Page: base.Page, Location: base.Location},
+		"new date":          {Text: base.Text, Note: base.Note, Chapter: base.Chapter, Page: base.Page, Location: base.Location, AddedAt: "2025-01-06 09:15:02"},
+	}
+	for name, h := range same {
+		if key("highlight", h) != key("highlight", base) {
+			t.Errorf("%s changed the highlight key", name)
+		}
+	}
+
+	different := map[string]util.Highlight{
+		"other page":     {Text: base.Text, Page: "13", Location: base.Location},
+		"other location": {Text: base.Text, Page: base.Page, Location: "190-192"},
+		"other text":     {Text: "Care about your code.", Page: base.Page, Location: base.Location},
+	}
+	for name, h := range different {
+		if key("highlight", h) == key("highlight", base) {
+			t.Errorf("%s kept the highlight key", name)
+		}
+	}
+
+	// メモだけのハイライトはメモで区別する
+	if key("highlight", util.Highlight{Note: "a", Location: "2000"}) == key("highlight", util.Highlight{Note: "b", Location: "2000"}) {
+		t.Error("note-only highlights share a key")
+	}
+
+	// 章単位は章名だけで決まる
+	if key("chapter", base) != key("chapter", util.Highlight{Text: "Other", Chapter: "Preface", Page: "99"}) {
+		t.Error("chapter key depends on more than the chapter")
+	}
+	if key("chapter", base) == key("highlight", base) {
+		t.Error("chapter and highlight keys collide")
+	}
+
+	other := source
+	other.SourceID = "s002"
+	if groupHighlights(util.HighlightBook{Highlights: []util.Highlight{base}}, other, "highlight")[0].Key == key("highlight", base) {
+		t.Error("highlights of different sources share a key")
+	}
+}
+
+// TestHighlightKeyIgnoresLineEndings - 同じ書き出しを CRLF と LF で読み込んでも同じノートを更新する
+func TestHighlightKeyIgnoresLineEndings(t *testing.T) {
+	lf := "The Pragmatic Programmer (Hunt, Andrew)\n" +
+		"- Your Highlight on page 12 | Location 180-182 | Added on Monday, January 6, 2025 9:15:02 AM\n\n" +
+		"Care about\nyour craft.\n==========\n"
+	crlf := "\uFEFF" + strings.ReplaceAll(lf, "\n", "\r\n")
+
+	source := model.Source{SourceID: "s001"}
+	var keys []string
+	for _, src := range []string{lf, crlf} {
+		books, err := util.ParseKindleClippings(strings.NewReader(src))
+		if err != nil {
+			t.Fatalf("ParseKindleClippings() error: %v", err)
+		}
+		if len(books) != 1 || len(books[0].Highlights) != 1 {
+			t.Fatalf("ParseKindleClippings() = %#v", books)
+		}
+		keys = append(keys, groupHighlights(books[0], source, "highlight")[0].Key)
+	}
+	if keys[0] != keys[1] {
+		t.Errorf("keys differ between LF and CRLF input: %v", keys)
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// rewriteIncomingLinks - 他ノートのフロントマター `links:` と本文中の `(fromID.md)` を toID に書き換える
+func rewriteIncomingLinks(fromID, toID string, config model.Config) error {
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	for _, note := range notes {
+		if note.ID == fromID || note.ID == toID || note.Deleted {
+			continue
+		}
+
+		notePath := filepath.Join(config.ZettelDir, note.ID+".md")
+		if note.Archived {
+			notePath = filepath.Join(config.ArchiveDir, note.ID+".md")
+		}
+		content, err := os.ReadFile(notePath)
+		if err != nil {
+			continue
+		}
+		updatedContent, changed, err := rewriteNoteContent(string(content), note.NoteType, func(fm noteFrontMatterFields, body *string) bool {
+			changed := false
+			var links []string
+			for _, link := range *fm.Links {
+				if link == fromID {
+					link = toID
+					changed = true
+				}
+				if !slices.Contains(links, link) {
+					links = append(links, link)
+				}
+			}
+			if strings.Contains(*body, "("+fromID+".md)") {
+				*body = strings.ReplaceAll(*body, "("+fromID+".md)", "("+toID+".md)")
+				changed = true
+			}
+			*fm.Links = links
+			return changed
+		})
+		if err != nil || !changed {
+			continue
+		}
+
+		if err := os.WriteFile(notePath, []byte(updatedContent), 0644); err != nil {
+			return fmt.Errorf("❌ Error writing updated note file: %w", err)
+		}
+		log.Printf("🔗 Rewrote links in %s: %s → %s", note.ID, fromID, toID)
+	}
+
+	return nil
+}
+
+// writeTaskBlockers - tasks.json の blocked_by とずれたタスクノートの front matter を書き戻す
+func writeTaskBlockers(config model.Config) error {
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	for _, task := range tasks {
+		note, ok := findNoteByID(notes, task.NoteID)
+		if !ok {
+			continue
+		}
+		notePath := noteFilePath(note, config)
+		content, err := os.ReadFile(notePath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("❌ Error reading note file: %w", err)
+		}
+		frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content))
+		if err != nil {
+			return fmt.Errorf("❌ Error parsing front matter of %s: %w", notePath, err)
+		}
+		if slices.Equal(frontMatter.BlockedBy, task.BlockedBy) {
+			continue
+		}
+		frontMatter.BlockedBy = task.BlockedBy
+		if err := os.WriteFile(notePath, []byte(store.UpdateFrontMatter(&frontMatter, body)), 0644); err != nil {
+			return fmt.Errorf("❌ Error writing updated note file: %w", err)
+		}
+	}
+	return nil
+}
+
+// mergeNotes - ノート b を a に統合する。本文・タグ・リンク・ソース・プロジェクトを a に集約し、b はゴミ箱へ移動
+func mergeNotes(survivorSeqID, mergedSeqID string, config model.Config) (model.Note, error) {
+	if survivorSeqID == mergedSeqID {
+		return model.Note{}, fmt.Errorf("❌ Cannot merge a note into itself")
+	}
+
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	var survivor, merged model.Note
+	foundSurvivor, foundMerged := false, false
+	for _, note := range notes {
+		if note.SeqID == survivorSeqID {
+			survivor = note
+			foundSurvivor = true
+		}
+		if note.SeqID == mergedSeqID {
+			merged = note
+			foundMerged = true
+		}
+	}
+	if !foundSurvivor {
+		return model.Note{}, fmt.Errorf("❌ Note with ID %s not found", survivorSeqID)
+	}
+	if !foundMerged {
+		return model.Note{}, fmt.Errorf("❌ Note with ID %s not found", mergedSeqID)
+	}
+
+	// タスクを非タスクのノートへ統合すると tasks.json の行が宙に浮くため拒否する
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	survivorIsTask, mergedIsTask := false, false
+	for _, task := range tasks {
+		survivorIsTask = survivorIsTask || task.NoteID == survivor.ID
+		mergedIsTask = mergedIsTask || task.NoteID == merged.ID
+	}
+	if mergedIsTask && !survivorIsTask {
+		return model.Note{}, fmt.Errorf("❌ Cannot merge task note %s into %s, which is not a task", mergedSeqID, survivorSeqID)
+	}
+
+	survivorPath := filepath.Join(config.ZettelDir, survivor.ID+".md")
+	survivorContent, err := os.ReadFile(survivorPath)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+	mergedContent, err := os.ReadFile(filepath.Join(config.ZettelDir, merged.ID+".md"))
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+	mergedFrontMatter, mergedBody, err := store.ParseFrontMatter[model.NoteFrontMatter](string(mergedContent))
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	if err := store.BackupNote(survivorPath, config.Backup.BackupDir); err != nil {
+		log.Printf("⚠️ Backup failed: %v", err)
+	}
+
+	// タグ・リンクの和集合（自身へのリンクは除外）。タスクの項目を失わないよう種別に応じた front matter で書き換える
+	var body, updatedAt string
+	var projects []string
+	updatedContent, _, err := rewriteNoteContent(string(survivorContent), survivor.NoteType, func(fm noteFrontMatterFields, b *string) bool {
+		for _, tag := range mergedFrontMatter.Tags {
+			if !slices.Contains(*fm.Tags, tag) {
+				*fm.Tags = append(*fm.Tags, tag)
+			}
+		}
+		var links []string
+		for _, link := range append(append([]string{}, *fm.Links...), mergedFrontMatter.Links...) {
+			if link == survivor.ID || link == merged.ID || slices.Contains(links, link) {
+				continue
+			}
+			links = append(links, link)
+		}
+		*fm.Links = links
+		for _, project := range mergedFrontMatter.Projects {
+			if !slices.Contains(*fm.Projects, project) {
+				*fm.Projects = append(*fm.Projects, project)
+			}
+		}
+
+		*b = strings.TrimSpace(*b + "\n\n" + strings.ReplaceAll(mergedBody, "("+survivor.ID+".md)", ""))
+		*b = strings.ReplaceAll(*b, "("+merged.ID+".md)", "("+survivor.ID+".md)")
+		*fm.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+		body, updatedAt, projects = *b, *fm.UpdatedAt, *fm.Projects
+		return true
+	})
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	if err := os.WriteFile(survivorPath, []byte(updatedContent), 0644); err != nil {
+		return model.Note{}, fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+
+	if err := syncNoteRelations(survivor.ID, string(survivorContent), updatedContent, config); err != nil {
+		return model.Note{}, err
+	}
+
+	// note_tags / links / project_notes / source_notes / highlight_imports / tasks を a に付け替える
+	if err := store.ReassignNote(merged.ID, survivor.ID, config); err != nil {
+		return model.Note{}, err
+	}
+	if mergedIsTask {
+		if err := writeTaskBlockers(config); err != nil {
+			return model.Note{}, err
+		}
+	}
+
+	if err := rewriteIncomingLinks(merged.ID, survivor.ID, config); err != nil {
+		return model.Note{}, err
+	}
+
+	notes, notesJsonPath, err := store.LoadNotes(config)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	for i := range notes {
+		if notes[i].ID == survivor.ID {
+			notes[i].Content = body
+			notes[i].UpdatedAt = updatedAt
+			notes[i].Projects = projects
+			survivor = notes[i]
+			break
+		}
+	}
+	if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to update notes.json: %w", err)
+	}
+
+	if err := store.MoveNoteToTrash(merged.SeqID, config); err != nil {
+		return model.Note{}, err
+	}
+
+	return survivor, nil
+}
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge [noteID] [noteID]",
+	Short: "Merge the second note into the first one",
+	Long: `Merge the second note into the first one.
+
+The body of the second note is appended to the first, tags, links,
+sources and projects are combined, links pointing to the second note
+are rewritten to the first, and the second note is moved to the trash.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		survivor, err := mergeNotes(args[0], args[1], *config)
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Printf("✅ Note %s merged into %s: %s\n", args[1], survivor.SeqID, survivor.Title)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+// writeTaskNote - テスト用のタスクノートを書き出す
+func writeTaskNote(t *testing.T, m testMachine, noteID, title string, blockedBy []string) {
+	t.Helper()
+	content := "---\nid: " + noteID + "\ntitle: " + title + "\nnote_type: task\ntags: []\nlinks: []\nprojects: []\nstatus: Not started\n"
+	if len(blockedBy) > 0 {
+		content += "blocked_by: [" + strings.Join(blockedBy, ", ") + "]\n"
+	} else {
+		content += "blocked_by: []\n"
+	}
+	content += "---\n\n## " + title + "\n"
+	m.write(t, noteID+".md", content)
+}
+
+func TestMergeTaskNotes(t *testing.T) {
+	m := newTestMachine(t)
+	t.Setenv("ZTL_CONFIG", m.configPath)
+
+	writeTaskNote(t, m, "20250101120000", "Task A", nil)
+	writeTaskNote(t, m, "20250101120001", "Task B", []string{"task-003"})
+	writeTaskNote(t, m, "20250101120002", "Task C", nil)
+	writeTaskNote(t, m, "20250101120003", "Task D", []string{"task-002"})
+	writeTable(t, m, "notes.json", []model.Note{
+		{ID: "20250101120000", SeqID: "n001", Title: "Task A", NoteType: "task"},
+		{ID: "20250101120001", SeqID: "n002", Title: "Task B", NoteType: "task"},
+		{ID: "20250101120002", SeqID: "n003", Title: "Task C", NoteType: "task"},
+		{ID: "20250101120003", SeqID: "n004", Title: "Task D", NoteType: "task"},
+	})
+	writeTable(t, m, "tasks.json", []model.Task{
+		{ID: "task-001", NoteID: "20250101120000", Status: model.TaskStatusNotStarted},
+		{ID: "task-002", NoteID: "20250101120001", Status: model.TaskStatusNotStarted, BlockedBy: []string{"task-003"}},
+		{ID: "task-003", NoteID: "20250101120002", Status: model.TaskStatusNotStarted},
+		{ID: "task-004", NoteID: "20250101120003", Status: model.TaskStatusNotStarted, BlockedBy: []string{"task-002"}},
+	})
+	writeTable(t, m, "time_entries.json", []model.TimeEntry{
+		{ID: "te-001", TaskID: "task-002", StartAt: "2025-01-01 09:00:00", EndAt: "2025-01-01 10:00:00"},
+	})
+
+	if _, err := mergeNotes("n001", "n002", m.config); err != nil {
+		t.Fatalf("mergeNotes() error: %v", err)
+	}
+
+	tasks := readTable[model.Task](t, m, "tasks.json")
+	var ids []string
+	blockedBy := make(map[string][]string)
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		blockedBy[task.ID] = task.BlockedBy
+		if task.NoteID == "20250101120001" {
+			t.Errorf("task %s still points at the merged note", task.ID)
+		}
+	}
+	if !slices.Equal(ids, []string{"task-001", "task-003", "task-004"}) {
+		t.Errorf("tasks = %v, want the merged task row removed", ids)
+	}
+	if got := blockedBy["task-001"]; !slices.Equal(got, []string{"task-003"}) {
+		t.Errorf("task-001 blocked_by = %v, want the blockers of the merged task", got)
+	}
+	if got := blockedBy["task-004"]; !slices.Equal(got, []string{"task-001"}) {
+		t.Errorf("task-004 blocked_by = %v, want task-001", got)
+	}
+	if got := m.read(t, "20250101120003.md"); !strings.Contains(got, "task-001") || strings.Contains(got, "task-002") {
+		t.Errorf("front matter of task-004 was not rewritten:\n%s", got)
+	}
+
+	entries := readTable[model.TimeEntry](t, m, "time_entries.json")
+	if entries[0].TaskID != "task-001" {
+		t.Errorf("time entry task_id = %s, want task-001", entries[0].TaskID)
+	}
+}
+
+func TestMergeTaskIntoNonTaskNote(t *testing.T) {
+	m := newTestMachine(t)
+	t.Setenv("ZTL_CONFIG", m.configPath)
+
+	m.write(t, "20250101120000.md", "---\nid: 20250101120000\ntitle: Idea\nnote_type: fleeting\n---\n\nidea\n")
+	writeTaskNote(t, m, "20250101120001", "Task B", nil)
+	writeTable(t, m, "notes.json", []model.Note{
+		{ID: "20250101120000", SeqID: "n001", Title: "Idea", NoteType: "fleeting"},
+		{ID: "20250101120001", SeqID: "n002", Title: "Task B", NoteType: "task"},
+	})
+	writeTable(t, m, "tasks.json", []model.Task{
+		{ID: "task-001", NoteID: "20250101120001", Status: model.TaskStatusNotStarted},
+	})
+
+	if _, err := mergeNotes("n001", "n002", m.config); err == nil {
+		t.Fatal("mergeNotes() merged a task note into a fleeting note")
+	}
+	if got := m.read(t, "20250101120000.md"); strings.Contains(got, "Task B") {
+		t.Errorf("survivor was modified by a refused merge:\n%s", got)
+	}
+}
+
+func TestMergeMovesHighlightImports(t *testing.T) {
+	m := newTestMachine(t)
+	t.Setenv("ZTL_CONFIG", m.configPath)
+
+	m.write(t, "20250101120000.md", "---\nid: 20250101120000\ntitle: Chapter 1\nnote_type: literature\n---\n\nfirst\n")
+	m.write(t, "20250101120001.md", "---\nid: 20250101120001\ntitle: Chapter 2\nnote_type: literature\n---\n\nsecond\n")
+	writeTable(t, m, "notes.json", []model.Note{
+		{ID: "20250101120000", SeqID: "n001", Title: "Chapter 1", NoteType: "literature"},
+		{ID: "20250101120001", SeqID: "n002", Title: "Chapter 2", NoteType: "literature"},
+	})
+	writeTable(t, m, "highlight_imports.json", []model.HighlightImport{
+		{Key: "a1b2c3", SourceID: "s001", NoteID: "20250101120000", Hash: "a"},
+		{Key: "d4e5f6", SourceID: "s001", NoteID: "20250101120001", Hash: "b"},
+	})
+
+	if _, err := mergeNotes("n001", "n002", m.config); err != nil {
+		t.Fatalf("mergeNotes() error: %v", err)
+	}
+
+	for _, imp := range readTable[model.HighlightImport](t, m, "highlight_imports.json") {
+		if imp.NoteID != "20250101120000" {
+			t.Errorf("highlight import %s points at %s, want the surviving note", imp.Key, imp.NoteID)
+		}
+	}
+}
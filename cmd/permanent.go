@@ -37,11 +37,13 @@ var permanentRestoreTrash bool
 var permanentRestoreArchive bool
 
 func createNewPermanentNote(permanentTitle string, config model.Config) (string, model.Note, error) {
-	t := time.Now()
-	noteId := fmt.Sprintf("%d%02d%02d%02d%02d%02d",
-		t.Year(), t.Month(), t.Day(),
-		t.Hour(), t.Minute(), t.Second())
-	createdAt := t.Format("2006-01-02 15:04:05")
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	// 同じ秒に作成したノートと ID が重複しないようにする
+	noteId := nextFreeNoteID(notes, config)
+	createdAt := time.Now().Format("2006-01-02 15:04:05")
 
 	// Create front matter
 	frontMatter := model.NoteFrontMatter{
@@ -144,6 +146,11 @@ var newPermanentCmd = &cobra.Command{
 		log.Printf("Opening %q (Title: %q)...", newPermanentStr, permanentTitle)
 		time.Sleep(2 * time.Second)
 
+		initialContent, err := os.ReadFile(newPermanentStr)
+		if err != nil {
+			log.Printf("❌ Failed to read Markdown file: %v", err)
+		}
+
 		err = util.OpenEditor(newPermanentStr, *config)
 		if err != nil {
 			log.Printf("❌ Failed to open editor: %v\n", err)
@@ -162,6 +169,11 @@ var newPermanentCmd = &cobra.Command{
 			body = string(mdContent) // フロントマターの解析に失敗した場合、全文をセット
 		}
 
+		// フロントマターの tags / links を JSON に反映
+		if err := syncNoteRelations(noteID, string(initialContent), string(mdContent), *config); err != nil {
+			log.Printf("⚠️ Failed to sync tags and links: %v", err)
+		}
+
 		notes, noteJsonPath, err := store.LoadNotes(*config)
 		if err != nil {
 			log.Printf("❌ Error loading notes from JSON: %v", err)
@@ -476,7 +488,7 @@ var showPermanentCmd = &cobra.Command{
 
 		// Render Markdown content unless --meta flag is used
 		if !taskMeta {
-			renderedContent, err := glamour.Render(body, "dark")
+			renderedContent, err := glamour.Render(resolveNoteCitations(body, *config), "dark")
 			if err != nil {
 				log.Printf("⚠️ Failed to render markdown content: %v", err)
 			} else {
@@ -529,6 +541,12 @@ var editPermanentCmd = &cobra.Command{
 					log.Printf("⚠️ Backup failed: %v", err)
 				}
 
+				originalContent, err := os.ReadFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"))
+				if err != nil {
+					log.Printf("❌ Failed to read note file: %v", err)
+					os.Exit(1)
+				}
+
 				fmt.Printf("Found %v, opening...\n", filepath.Join(config.ZettelDir, notes[i].ID+".md"))
 				time.Sleep(2 * time.Second)
 
@@ -565,6 +583,11 @@ var editPermanentCmd = &cobra.Command{
 					log.Printf("❌ Error writing updated note file: %v", err)
 				}
 
+				// フロントマターの tags / links を JSON に反映
+				if err := syncNoteRelations(notes[i].ID, string(originalContent), updatedContent, *config); err != nil {
+					log.Printf("⚠️ Failed to sync tags and links: %v", err)
+				}
+
 				notes[i].Title = frontMatter.Title
 				// notes[i].Links = frontMatter.Links
 				notes[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
@@ -4,11 +4,13 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/text"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -17,10 +19,22 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func createNewProject(projectName string, config model.Config) error {
+var projectListStatus string
+var projectListAll bool
+
+func createNewProject(projectName string, fields model.Project, config model.Config) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
 	project := model.Project{
-		ProjectID: "",
-		Name:      projectName,
+		ProjectID:   "",
+		Name:        projectName,
+		Status:      fields.Status,
+		Description: fields.Description,
+		DueDate:     fields.DueDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := validateProjectFields(&project); err != nil {
+		return err
 	}
 
 	err := store.InsertProjectToJson(project, config)
@@ -45,15 +59,12 @@ func addNoteToProject(noteID, projectID string, config model.Config) (model.Note
 
 	projectNote := model.ProjectNote{}
 
+	// プロジェクトID (p001) またはプロジェクト名で指定できる
 	var matchedProject model.Project
-	foundProject := false
-	for _, project := range projects {
-		if projectID == project.ProjectID {
-			projectNote.ProjectID = project.ProjectID
-			matchedProject = project // マッチしたノートを格納
-			foundProject = true
-			break // マッチしたらループを抜ける
-		}
+	i, foundProject := store.FindProject(projects, projectID)
+	if foundProject {
+		matchedProject = projects[i]
+		projectNote.ProjectID = matchedProject.ProjectID
 	}
 
 	var matchedNote model.Note
@@ -104,7 +115,9 @@ var newProjectCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if err = createNewProject(projectName, *config); err != nil {
+		var fields model.Project
+		applyProjectFieldFlags(cmd, &fields)
+		if err = createNewProject(projectName, fields, *config); err != nil {
 			log.Printf("❌ Failed to create note: %v\n", err)
 			return
 		}
@@ -141,29 +154,19 @@ var addProjectCmd = &cobra.Command{
 					return
 				}
 
-				content, err := os.ReadFile(filepath.Join(config.ZettelDir, note.ID+".md"))
-				if err != nil {
-					log.Printf("❌ Failed to read updated note file: %v", err)
-					os.Exit(1)
-				}
-
-				// var noteFM model.NoteFrontMatter
-				frontMatter, body, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content))
+				// 既存の所属は残したまま projects に追加
+				err = editNoteFrontMatter(noteFilePath(note, *config), note.NoteType, func(projects *[]string, _, _ *bool) {
+					if !slices.Contains(*projects, project.Name) {
+						*projects = append(*projects, project.Name)
+					}
+				})
 				if err != nil {
-					log.Printf("❌ Error parsing front matter: %v", err)
-					os.Exit(1)
-				}
-
-				notes[i].ProjectName = project.Name
-				frontMatter.ProjectName = project.Name
-
-				updatedContent := store.UpdateFrontMatter(&frontMatter, body)
-
-				err = os.WriteFile(filepath.Join(config.ZettelDir, note.ID+".md"), []byte(updatedContent), 0644)
-				if err != nil {
-					log.Printf("❌ Error writing updated note file: %v", err)
+					log.Printf("%v", err)
 					return
 				}
+				if !slices.Contains(notes[i].Projects, project.Name) {
+					notes[i].Projects = append(notes[i].Projects, project.Name)
+				}
 
 				err = store.SaveUpdatedJson(notes, noteJsonPath)
 				if err != nil {
@@ -208,8 +211,27 @@ var listProjectCmd = &cobra.Command{
 			projectNoteCount[pn.ProjectID]++
 		}
 
+		statusFilter := ""
+		if projectListStatus != "" {
+			if statusFilter = model.NormalizeProjectStatus(projectListStatus); statusFilter == "" {
+				log.Fatalf("❌ Invalid status: %s (must be one of %s)", projectListStatus, strings.Join(model.ProjectStatuses, ", "))
+			}
+		}
+
+		// アーカイブ済みのプロジェクトは --all か --status archived のときのみ表示
+		var shown []model.Project
+		for _, project := range projects {
+			if statusFilter != "" && project.Status != statusFilter {
+				continue
+			}
+			if statusFilter == "" && !projectListAll && project.Status == model.ProjectStatusArchived {
+				continue
+			}
+			shown = append(shown, project)
+		}
+
 		fmt.Println(strings.Repeat("=", 30))
-		fmt.Printf("Zettelkasten: %v projects shown\n", len(projectNotes))
+		fmt.Printf("Zettelkasten: %v projects shown\n", len(shown))
 		fmt.Println(strings.Repeat("=", 30))
 
 		t := table.NewWriter()
@@ -220,15 +242,26 @@ var listProjectCmd = &cobra.Command{
 		t.AppendHeader(table.Row{
 			text.FgGreen.Sprintf("Project ID"),
 			text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Project Name")),
+			text.FgGreen.Sprintf("Status"),
+			text.FgGreen.Sprintf("Due"),
 			text.FgGreen.Sprintf("Notes Count"),
+			text.FgGreen.Sprintf("Description"),
 		})
 
 		// プロジェクトをテーブルに追加
-		for _, project := range projects {
+		today := time.Now().Format("2006-01-02")
+		for _, project := range shown {
+			due := project.DueDate
+			if due != "" && due < today && project.Status != model.ProjectStatusDone && project.Status != model.ProjectStatusArchived {
+				due = text.FgHiRed.Sprint(due)
+			}
 			t.AppendRow(table.Row{
 				project.ProjectID,
 				project.Name,
+				project.Status,
+				due,
 				projectNoteCount[project.ProjectID],
+				truncateDisplay(project.Description, 40),
 			})
 		}
 
@@ -238,79 +271,39 @@ var listProjectCmd = &cobra.Command{
 }
 
 var showProjectCmd = &cobra.Command{
-	Use:     "show",
-	Short:   "Show project details",
+	Use:     "show [projectID]",
+	Short:   "Show project dashboard",
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"s"},
 	Run: func(cmd *cobra.Command, args []string) {
-		projectID := args[0]
-		config, err := store.LoadConfig()
-		if err != nil {
-			log.Printf("❌ Error loading config: %v\n", err)
-			os.Exit(1)
+		// ダッシュボードは入れ子の集計なので CSV には対応しない
+		if err := validateOutputFormat("table", "json"); err != nil {
+			fatal(err)
 		}
 
-		projects, _, err := store.LoadProjects(*config)
+		config, err := store.LoadConfig()
 		if err != nil {
-			log.Printf("❌ Error loading notes from JSON: %v", err)
+			log.Printf("❌ Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
-		// プロジェクトに紐づくノート数を取得
-		projectNotes, _, err := store.LoadProjectNotes(*config)
-		if err != nil {
-			log.Printf("❌ Error loading notes from JSON: %v", err)
-			os.Exit(1)
-		}
+		projects, _, i := loadProjectOrExit(args[0], *config)
 
-		notes, _, err := store.LoadNotes(*config)
+		dashboard, err := buildProjectDashboard(projects[i], time.Now(), *config)
 		if err != nil {
-			log.Printf("❌ Error loading notes from JSON: %v", err)
-			os.Exit(1)
+			log.Fatalf("%v", err)
 		}
 
-		var project model.Project
-		foundProject := false
-		for _, p := range projects {
-			if p.ProjectID == projectID {
-				project = p
-				foundProject = true
-				break
+		if outputFormat == "json" {
+			jsonBytes, err := json.MarshalIndent(dashboard, "", "  ")
+			if err != nil {
+				log.Fatalf("❌ Failed to convert to JSON: %v", err)
 			}
+			fmt.Println(string(jsonBytes))
+			return
 		}
 
-		if !foundProject {
-			log.Printf("❌ Error: Project with ID %s not found", projectID)
-			os.Exit(1)
-		}
-
-		// プロジェクトに紐づくノートを取得
-		var noteIDs []string
-		for _, pn := range projectNotes {
-			if pn.ProjectID == projectID {
-				noteIDs = append(noteIDs, pn.NoteID)
-			}
-		}
-
-		// ノートID に対応するノート名を取得
-		noteMap := make(map[string]string)
-		for _, note := range notes {
-			noteMap[note.ID] = note.Title
-		}
-
-		fmt.Printf("📖 Project: %s (%s)\n", project.Name, project.ProjectID)
-		fmt.Println("   🏷 Notes:")
-		if len(noteIDs) == 0 {
-			fmt.Println("   - No notes assigned to this project.")
-		} else {
-			for _, noteID := range noteIDs {
-				if title, exists := noteMap[noteID]; exists {
-					fmt.Printf("   - [%s] %s\n", noteID, title)
-				} else {
-					fmt.Printf("   - [%s] (Note not found)\n", noteID)
-				}
-			}
-		}
+		renderProjectDashboard(dashboard)
 	},
 }
 
@@ -320,4 +313,7 @@ func init() {
 	projectCmd.AddCommand(listProjectCmd)
 	projectCmd.AddCommand(showProjectCmd)
 	rootCmd.AddCommand(projectCmd)
+	addProjectFieldFlags(newProjectCmd)
+	listProjectCmd.Flags().StringVar(&projectListStatus, "status", "", "Filter by status (active, on-hold, done, archived)")
+	listProjectCmd.Flags().BoolVar(&projectListAll, "all", false, "Include archived projects")
 }
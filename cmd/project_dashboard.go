@@ -0,0 +1,270 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+)
+
+const dashboardRecentLimit = 5
+
+type dashboardNote struct {
+	SeqID     string `json:"seq_id"`
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	NoteType  string `json:"note_type"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type dashboardTask struct {
+	TaskID   string `json:"task_id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+	DueDate  string `json:"due_date"`
+}
+
+type dashboardTag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type projectDashboard struct {
+	Project         model.Project              `json:"project"`
+	NotesByType     map[string][]dashboardNote `json:"notes_by_type"`
+	TaskCounts      map[string]int             `json:"task_counts"`
+	TasksTotal      int                        `json:"tasks_total"`
+	TasksDone       int                        `json:"tasks_done"`
+	Overdue         []dashboardTask            `json:"overdue"`
+	RecentlyUpdated []dashboardNote            `json:"recently_updated"`
+	Sources         []model.Source             `json:"sources"`
+	Tags            []dashboardTag             `json:"tags"`
+}
+
+// buildProjectDashboard - プロジェクトに属するノート・タスク・ソース・タグを集計
+func buildProjectDashboard(project model.Project, now time.Time, config model.Config) (projectDashboard, error) {
+	dashboard := projectDashboard{
+		Project:         project,
+		NotesByType:     map[string][]dashboardNote{},
+		TaskCounts:      map[string]int{},
+		Overdue:         []dashboardTask{},
+		RecentlyUpdated: []dashboardNote{},
+		Sources:         []model.Source{},
+		Tags:            []dashboardTag{},
+	}
+
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return dashboard, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	noteIDs, err := projectMemberIDs(project, notes, config)
+	if err != nil {
+		return dashboard, err
+	}
+
+	var members []model.Note
+	for _, note := range notes {
+		if note.Deleted || !noteIDs[note.ID] {
+			delete(noteIDs, note.ID)
+			continue
+		}
+		members = append(members, note)
+	}
+
+	for _, note := range members {
+		dashboard.NotesByType[note.NoteType] = append(dashboard.NotesByType[note.NoteType], dashboardNote{
+			SeqID: note.SeqID, ID: note.ID, Title: note.Title, NoteType: note.NoteType, UpdatedAt: note.UpdatedAt,
+		})
+	}
+
+	// 最近更新されたノート
+	recent := append([]model.Note{}, members...)
+	sort.SliceStable(recent, func(i, j int) bool { return recent[i].UpdatedAt > recent[j].UpdatedAt })
+	for i, note := range recent {
+		if i >= dashboardRecentLimit {
+			break
+		}
+		dashboard.RecentlyUpdated = append(dashboard.RecentlyUpdated, dashboardNote{
+			SeqID: note.SeqID, ID: note.ID, Title: note.Title, NoteType: note.NoteType, UpdatedAt: note.UpdatedAt,
+		})
+	}
+
+	// タスクのステータス別件数と期限切れ
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return dashboard, fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	titles := make(map[string]string)
+	for _, note := range members {
+		titles[note.ID] = note.Title
+	}
+	today := now.Format("2006-01-02")
+	for _, task := range tasks {
+		if _, ok := titles[task.NoteID]; !ok {
+			continue
+		}
+		status := model.NormalizeTaskStatus(task.Status)
+		if status == "" {
+			status = model.TaskStatusNotStarted
+		}
+		dashboard.TaskCounts[status]++
+		dashboard.TasksTotal++
+		if status == model.TaskStatusDone {
+			dashboard.TasksDone++
+			continue
+		}
+		if task.DueDate != "" && task.DueDate < today {
+			dashboard.Overdue = append(dashboard.Overdue, dashboardTask{
+				TaskID: task.ID, Title: titles[task.NoteID], Status: status, Priority: task.Priority, DueDate: task.DueDate,
+			})
+		}
+	}
+	sort.SliceStable(dashboard.Overdue, func(i, j int) bool { return dashboard.Overdue[i].DueDate < dashboard.Overdue[j].DueDate })
+
+	// ノートに紐づくソース
+	sourceNotes, _, err := store.LoadSourceNotes(config)
+	if err != nil {
+		return dashboard, fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+	sources, _, err := store.LoadSources(config)
+	if err != nil {
+		return dashboard, fmt.Errorf("❌ Failed to load sources.json: %w", err)
+	}
+	linkedSources := make(map[string]bool)
+	for _, sn := range sourceNotes {
+		if noteIDs[sn.NoteID] {
+			linkedSources[sn.SourceID] = true
+		}
+	}
+	for _, source := range sources {
+		if linkedSources[source.SourceID] {
+			dashboard.Sources = append(dashboard.Sources, source)
+		}
+	}
+
+	// プロジェクト内で使われているタグ
+	noteTags, _, err := store.LoadNoteTags(config)
+	if err != nil {
+		return dashboard, fmt.Errorf("❌ Failed to load note_tags.json: %w", err)
+	}
+	tags, _, err := store.LoadTags(config)
+	if err != nil {
+		return dashboard, fmt.Errorf("❌ Failed to load tags.json: %w", err)
+	}
+	tagNames := make(map[string]string)
+	for _, tag := range tags {
+		tagNames[tag.ID] = tag.Name
+	}
+	tagCounts := make(map[string]int)
+	for _, nt := range noteTags {
+		if name, ok := tagNames[nt.TagID]; ok && noteIDs[nt.NoteID] {
+			tagCounts[name]++
+		}
+	}
+	for name, count := range tagCounts {
+		dashboard.Tags = append(dashboard.Tags, dashboardTag{Name: name, Count: count})
+	}
+	sort.Slice(dashboard.Tags, func(i, j int) bool {
+		if dashboard.Tags[i].Count != dashboard.Tags[j].Count {
+			return dashboard.Tags[i].Count > dashboard.Tags[j].Count
+		}
+		return dashboard.Tags[i].Name < dashboard.Tags[j].Name
+	})
+
+	return dashboard, nil
+}
+
+// progressBar - 完了率を [████░░░░] 50% の形式で表示
+func progressBar(done, total, width int) string {
+	if total == 0 {
+		return "[" + strings.Repeat("░", width) + "]   -"
+	}
+	filled := done * width / total
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), done*100/total)
+}
+
+func renderProjectDashboard(d projectDashboard) {
+	heading := color.New(color.FgCyan, color.Bold).SprintFunc()
+
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("📖 %s (%s)  [%s]\n", text.Bold.Sprint(d.Project.Name), d.Project.ProjectID, d.Project.Status)
+	if d.Project.DueDate != "" {
+		fmt.Printf("   Due: %s\n", d.Project.DueDate)
+	}
+	if d.Project.Description != "" {
+		fmt.Printf("   %s\n", d.Project.Description)
+	}
+	fmt.Println(strings.Repeat("=", 50))
+
+	// ノート（種類別）
+	noteCount := 0
+	var types []string
+	for noteType, notes := range d.NotesByType {
+		types = append(types, noteType)
+		noteCount += len(notes)
+	}
+	sort.Strings(types)
+	fmt.Printf("\n%s (%d)\n", heading("📝 Notes"), noteCount)
+	if noteCount == 0 {
+		fmt.Println("   - No notes assigned to this project.")
+	}
+	for _, noteType := range types {
+		fmt.Printf("   %s (%d)\n", noteType, len(d.NotesByType[noteType]))
+		for _, note := range d.NotesByType[noteType] {
+			fmt.Printf("     - [%s] %s\n", note.SeqID, note.Title)
+		}
+	}
+
+	// タスクの進捗
+	fmt.Printf("\n%s\n", heading("✅ Tasks"))
+	fmt.Printf("   %s  %d/%d done\n", progressBar(d.TasksDone, d.TasksTotal, 20), d.TasksDone, d.TasksTotal)
+	for _, status := range model.TaskStatuses {
+		if n := d.TaskCounts[status]; n > 0 {
+			fmt.Printf("   %-12s %d\n", status, n)
+		}
+	}
+
+	if len(d.Overdue) > 0 {
+		fmt.Printf("\n%s (%d)\n", color.New(color.FgHiRed, color.Bold).Sprint("⚠️  Overdue"), len(d.Overdue))
+		for _, task := range d.Overdue {
+			fmt.Printf("   %s  %-9s %s %s\n", text.FgHiRed.Sprint(task.DueDate), task.TaskID, task.Title, text.FgHiBlack.Sprintf("(%s)", task.Status))
+		}
+	}
+
+	if len(d.RecentlyUpdated) > 0 {
+		fmt.Printf("\n%s\n", heading("🕒 Recently updated"))
+		for _, note := range d.RecentlyUpdated {
+			fmt.Printf("   %s  [%s] %s\n", note.UpdatedAt, note.SeqID, note.Title)
+		}
+	}
+
+	fmt.Printf("\n%s (%d)\n", heading("📚 Sources"), len(d.Sources))
+	for _, source := range d.Sources {
+		meta := authorSummary(source.Authors)
+		if source.Year > 0 {
+			meta = strings.TrimSpace(fmt.Sprintf("%s %d", meta, source.Year))
+		}
+		if meta != "" {
+			meta = " " + text.FgHiBlack.Sprintf("(%s)", meta)
+		}
+		fmt.Printf("   - [%s] %s%s\n", source.SourceID, source.Title, meta)
+	}
+
+	fmt.Printf("\n%s (%d)\n", heading("🏷  Tags"), len(d.Tags))
+	if len(d.Tags) > 0 {
+		var labels []string
+		for _, tag := range d.Tags {
+			labels = append(labels, fmt.Sprintf("#%s (%d)", tag.Name, tag.Count))
+		}
+		fmt.Printf("   %s\n", strings.Join(labels, "  "))
+	}
+}
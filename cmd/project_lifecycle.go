@@ -0,0 +1,688 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var projectStatus string
+var projectDescription string
+var projectDue string
+var projectArchiveNotes bool
+var projectDeleteNotes string
+var projectDeleteYes bool
+
+func addProjectFieldFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&projectStatus, "status", "", "Project status (active, on-hold, done, archived)")
+	cmd.Flags().StringVarP(&projectDescription, "description", "d", "", "Project description")
+	cmd.Flags().StringVar(&projectDue, "due", "", "Due date (YYYY-MM-DD, today, +2w...)")
+}
+
+// applyProjectFieldFlags - 指定されたフラグだけをプロジェクトに反映
+func applyProjectFieldFlags(cmd *cobra.Command, project *model.Project) {
+	if cmd.Flags().Changed("status") {
+		project.Status = projectStatus
+	}
+	if cmd.Flags().Changed("description") {
+		project.Description = projectDescription
+	}
+	if cmd.Flags().Changed("due") {
+		project.DueDate = projectDue
+	}
+}
+
+// validateProjectFields - ステータスと期限を正規化して検証
+func validateProjectFields(project *model.Project) error {
+	if project.Status == "" {
+		project.Status = model.ProjectStatusActive
+	}
+	status := model.NormalizeProjectStatus(project.Status)
+	if status == "" {
+		return fmt.Errorf("❌ Invalid status: %s (must be one of %s)", project.Status, strings.Join(model.ProjectStatuses, ", "))
+	}
+	project.Status = status
+
+	if project.DueDate != "" {
+		due, err := util.ParseDate(project.DueDate)
+		if err != nil {
+			return fmt.Errorf("❌ Invalid due date: %w", err)
+		}
+		project.DueDate = due
+	}
+	return nil
+}
+
+// noteFilePath - ノートの状態（削除・アーカイブ）に応じたファイルパス
+func noteFilePath(note model.Note, config model.Config) string {
+	switch {
+	case note.Deleted:
+		return filepath.Join(config.Trash.TrashDir, note.ID+".md")
+	case note.Archived:
+		return filepath.Join(config.ArchiveDir, note.ID+".md")
+	default:
+		return filepath.Join(config.ZettelDir, note.ID+".md")
+	}
+}
+
+// noteFrontMatterFields - ノート種別によらず共通の front matter 項目への参照
+type noteFrontMatterFields struct {
+	Tags      *[]string
+	Links     *[]string
+	Projects  *[]string
+	UpdatedAt *string
+	Archived  *bool
+	Deleted   *bool
+}
+
+// rewriteNoteContent - ノート種別に応じた front matter で解析し、共通の項目と本文を mutate で更新した内容を返す。
+// タスクの front matter を NoteFrontMatter として書き戻すとタスク固有の項目が失われるため区別する。
+// mutate が false を返したら変更なしとして元の内容を返す
+func rewriteNoteContent(content, noteType string, mutate func(fm noteFrontMatterFields, body *string) bool) (string, bool, error) {
+	if noteType == "task" {
+		frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](content)
+		if err != nil {
+			return "", false, fmt.Errorf("❌ Error parsing front matter: %w", err)
+		}
+		fields := noteFrontMatterFields{&frontMatter.Tags, &frontMatter.Links, &frontMatter.Projects, &frontMatter.UpdatedAt, &frontMatter.Archived, &frontMatter.Deleted}
+		if !mutate(fields, &body) {
+			return content, false, nil
+		}
+		return store.UpdateFrontMatter(&frontMatter, body), true, nil
+	}
+
+	frontMatter, body, err := store.ParseFrontMatter[model.NoteFrontMatter](content)
+	if err != nil {
+		return "", false, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+	fields := noteFrontMatterFields{&frontMatter.Tags, &frontMatter.Links, &frontMatter.Projects, &frontMatter.UpdatedAt, &frontMatter.Archived, &frontMatter.Deleted}
+	if !mutate(fields, &body) {
+		return content, false, nil
+	}
+	return store.UpdateFrontMatter(&frontMatter, body), true, nil
+}
+
+// editNoteFrontMatter - ノート種別に応じた front matter を読み込み、mutate で更新して書き戻す
+func editNoteFrontMatter(path, noteType string, mutate func(projects *[]string, archived, deleted *bool)) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("❌ Error reading note file: %w", err)
+	}
+
+	updatedContent, _, err := rewriteNoteContent(string(content), noteType, func(fm noteFrontMatterFields, _ *string) bool {
+		mutate(fm.Projects, fm.Archived, fm.Deleted)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(updatedContent), 0644); err != nil {
+		return fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+	return nil
+}
+
+// moveNoteFile - front matter の archived / deleted を更新してノートを destDir に移動
+func moveNoteFile(note *model.Note, destDir string, archived, deleted bool, config model.Config) error {
+	originalPath := noteFilePath(*note, config)
+	err := editNoteFrontMatter(originalPath, note.NoteType, func(_ *[]string, a, d *bool) {
+		*a = archived
+		*d = deleted
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return fmt.Errorf("❌ Failed to create directory %s: %w", destDir, err)
+	}
+	destPath := filepath.Join(destDir, note.ID+".md")
+	if err := os.Rename(originalPath, destPath); err != nil {
+		return fmt.Errorf("❌ Error moving note: %w", err)
+	}
+
+	note.Archived = archived
+	note.Deleted = deleted
+	return nil
+}
+
+// projectMemberIDs - プロジェクトに属するノートIDの集合（project_notes と front matter の projects の和）
+func projectMemberIDs(project model.Project, notes []model.Note, config model.Config) (map[string]bool, error) {
+	projectNotes, _, err := store.LoadProjectNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+	ids := make(map[string]bool)
+	for _, pn := range projectNotes {
+		if pn.ProjectID == project.ProjectID {
+			ids[pn.NoteID] = true
+		}
+	}
+	for _, note := range notes {
+		if model.HasProject(note.Projects, project.Name) {
+			ids[note.ID] = true
+		}
+	}
+	return ids, nil
+}
+
+// projectFilterIDs - --project で指定された（複数可）プロジェクトのいずれかに属するノートIDの集合。
+// 指定がなければ nil を返す
+func projectFilterIDs(refs []string, notes []model.Note, config model.Config) (map[string]bool, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	projects, _, err := store.LoadProjects(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load projects.json: %w", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, ref := range refs {
+		i, found := store.FindProject(projects, ref)
+		if !found {
+			return nil, fmt.Errorf("❌ Project %s not found", ref)
+		}
+		members, err := projectMemberIDs(projects[i], notes, config)
+		if err != nil {
+			return nil, err
+		}
+		for id := range members {
+			ids[id] = true
+		}
+	}
+	return ids, nil
+}
+
+// replaceNoteProject - projects の oldName を newName に置き換える（newName が空なら外す）
+func replaceNoteProject(notes []model.Note, noteIDs map[string]bool, oldName, newName string, config model.Config) int {
+	replace := func(projects []string) []string {
+		updated := []string{}
+		for _, p := range projects {
+			if p == oldName {
+				p = newName
+			}
+			if p != "" && !slices.Contains(updated, p) {
+				updated = append(updated, p)
+			}
+		}
+		return updated
+	}
+
+	count := 0
+	for i := range notes {
+		if !model.HasProject(notes[i].Projects, oldName) || (noteIDs != nil && !noteIDs[notes[i].ID]) {
+			continue
+		}
+		err := editNoteFrontMatter(noteFilePath(notes[i], config), notes[i].NoteType, func(projects *[]string, _, _ *bool) {
+			*projects = replace(*projects)
+		})
+		if err != nil {
+			log.Printf("⚠️ %s: %v", notes[i].ID, err)
+			continue
+		}
+		notes[i].Projects = replace(notes[i].Projects)
+		count++
+	}
+	return count
+}
+
+func loadProjectOrExit(ref string, config model.Config) ([]model.Project, string, int) {
+	projects, projectsJsonPath, err := store.LoadProjects(config)
+	if err != nil {
+		log.Fatalf("❌ Failed to load projects.json: %v", err)
+	}
+	i, found := store.FindProject(projects, ref)
+	if !found {
+		log.Fatalf("❌ Project %s not found", ref)
+	}
+	return projects, projectsJsonPath, i
+}
+
+var updateProjectCmd = &cobra.Command{
+	Use:   "update [projectID]",
+	Short: "Update project status, description or due date",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		projects, projectsJsonPath, i := loadProjectOrExit(args[0], *config)
+		project := projects[i]
+		applyProjectFieldFlags(cmd, &project)
+		if err := validateProjectFields(&project); err != nil {
+			log.Fatalf("%v", err)
+		}
+		project.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+		projects[i] = project
+
+		if err := store.SaveUpdatedJson(projects, projectsJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update projects.json: %v", err)
+		}
+		fmt.Printf("✅ Project %s updated (status: %s)\n", project.ProjectID, project.Status)
+	},
+}
+
+var removeNoteProjectCmd = &cobra.Command{
+	Use:   "remove-note [noteID] [projectID]",
+	Short: "Remove note from project",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		noteRef, projectRef := args[0], args[1]
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		projects, _, i := loadProjectOrExit(projectRef, *config)
+		project := projects[i]
+
+		notes, notesJsonPath, err := store.LoadNotes(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load notes.json: %v", err)
+		}
+		note, found := findNoteByRef(notes, noteRef)
+		if !found {
+			log.Fatalf("❌ Note with ID %s not found", noteRef)
+		}
+
+		removed, err := store.RemoveProjectNotes(project.ProjectID, note.ID, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if removed == 0 && !model.HasProject(note.Projects, project.Name) {
+			log.Fatalf("❌ Note %s is not in project %s", noteRef, project.ProjectID)
+		}
+
+		// front matter の projects から外す
+		replaceNoteProject(notes, map[string]bool{note.ID: true}, project.Name, "", *config)
+		if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update notes.json: %v", err)
+		}
+
+		fmt.Printf("✅ Note %s removed from project %s\n", noteRef, project.Name)
+	},
+}
+
+var renameProjectCmd = &cobra.Command{
+	Use:   "rename [projectID] [newName]",
+	Short: "Rename project and update projects in every note",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		newName := strings.TrimSpace(args[1])
+		if newName == "" {
+			log.Fatalf("❌ Project name must not be empty")
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		projects, projectsJsonPath, i := loadProjectOrExit(args[0], *config)
+		oldName := projects[i].Name
+		if oldName == newName {
+			fmt.Println("⚠️ Name unchanged")
+			return
+		}
+		for _, p := range projects {
+			if p.Name == newName {
+				log.Fatalf("❌ Project %s already exists (%s)", newName, p.ProjectID)
+			}
+		}
+
+		notes, notesJsonPath, err := store.LoadNotes(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load notes.json: %v", err)
+		}
+		updated := replaceNoteProject(notes, nil, oldName, newName, *config)
+		if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update notes.json: %v", err)
+		}
+
+		projects[i].Name = newName
+		projects[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+		if err := store.SaveUpdatedJson(projects, projectsJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update projects.json: %v", err)
+		}
+
+		fmt.Printf("✅ Project %s renamed: %s → %s (%d notes updated)\n", projects[i].ProjectID, oldName, newName, updated)
+	},
+}
+
+var archiveProjectCmd = &cobra.Command{
+	Use:   "archive [projectID]",
+	Short: "Archive project",
+	Long: `Set the project status to archived.
+
+With --notes, every note in the project is moved to the archive directory as well.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		projects, projectsJsonPath, i := loadProjectOrExit(args[0], *config)
+		project := projects[i]
+
+		archived := 0
+		if projectArchiveNotes {
+			notes, notesJsonPath, err := store.LoadNotes(*config)
+			if err != nil {
+				log.Fatalf("❌ Failed to load notes.json: %v", err)
+			}
+			noteIDs, err := projectMemberIDs(project, notes, *config)
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			for j := range notes {
+				if !noteIDs[notes[j].ID] || notes[j].Archived || notes[j].Deleted {
+					continue
+				}
+				if err := moveNoteFile(&notes[j], config.ArchiveDir, true, false, *config); err != nil {
+					log.Printf("⚠️ %s: %v", notes[j].ID, err)
+					continue
+				}
+				archived++
+			}
+			if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+				log.Fatalf("❌ Failed to update notes.json: %v", err)
+			}
+		}
+
+		projects[i].Status = model.ProjectStatusArchived
+		projects[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+		if err := store.SaveUpdatedJson(projects, projectsJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update projects.json: %v", err)
+		}
+
+		fmt.Printf("✅ Project %s archived", project.ProjectID)
+		if projectArchiveNotes {
+			fmt.Printf(" (%d notes moved to archive)", archived)
+		}
+		fmt.Println()
+	},
+}
+
+var deleteProjectCmd = &cobra.Command{
+	Use:   "delete [projectID]",
+	Short: "Delete project",
+	Long: `Delete a project.
+
+--notes decides what happens to the notes in the project:
+  keep     detach the notes from the project (default)
+  archive  move the notes to the archive directory
+  trash    move the notes to the trash directory
+  delete   delete the notes permanently`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		switch projectDeleteNotes {
+		case "keep", "archive", "trash", "delete":
+		default:
+			log.Fatalf("❌ Invalid --notes value: %s (must be keep, archive, trash or delete)", projectDeleteNotes)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		projects, projectsJsonPath, i := loadProjectOrExit(args[0], *config)
+		project := projects[i]
+
+		notes, notesJsonPath, err := store.LoadNotes(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load notes.json: %v", err)
+		}
+		noteIDs, err := projectMemberIDs(project, notes, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		if projectDeleteNotes == "delete" && len(noteIDs) > 0 && !projectDeleteYes {
+			reader := bufio.NewReader(os.Stdin)
+			answer := prompt(reader, fmt.Sprintf("⚠️ Permanently delete project %s and its %d notes? [y/N]: ", project.Name, len(noteIDs)))
+			if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+				fmt.Println("Cancelled")
+				return
+			}
+		}
+
+		// projects から外したうえで、指定に応じてノートを移動・削除
+		replaceNoteProject(notes, noteIDs, project.Name, "", *config)
+
+		var purge []model.Note
+		affected := 0
+		for j := range notes {
+			if !noteIDs[notes[j].ID] {
+				continue
+			}
+			switch projectDeleteNotes {
+			case "archive":
+				if notes[j].Archived || notes[j].Deleted {
+					continue
+				}
+				if err := moveNoteFile(&notes[j], config.ArchiveDir, true, false, *config); err != nil {
+					log.Printf("⚠️ %s: %v", notes[j].ID, err)
+					continue
+				}
+			case "trash":
+				if notes[j].Deleted {
+					continue
+				}
+				if err := moveNoteFile(&notes[j], config.Trash.TrashDir, notes[j].Archived, true, *config); err != nil {
+					log.Printf("⚠️ %s: %v", notes[j].ID, err)
+					continue
+				}
+			case "delete":
+				purge = append(purge, notes[j])
+			}
+			affected++
+		}
+		if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update notes.json: %v", err)
+		}
+
+		for _, note := range purge {
+			if err := os.Remove(noteFilePath(note, *config)); err != nil && !os.IsNotExist(err) {
+				log.Printf("⚠️ Failed to delete note file %s: %v", note.ID, err)
+				continue
+			}
+			if err := store.PurgeNote(note.ID, *config); err != nil {
+				log.Printf("⚠️ %s: %v", note.ID, err)
+			}
+		}
+
+		if _, err := store.RemoveProjectNotes(project.ProjectID, "", *config); err != nil {
+			log.Fatalf("%v", err)
+		}
+		projects = append(projects[:i], projects[i+1:]...)
+		if err := store.SaveUpdatedJson(projects, projectsJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update projects.json: %v", err)
+		}
+
+		switch projectDeleteNotes {
+		case "keep":
+			fmt.Printf("✅ Project %s deleted (%d notes detached)\n", project.Name, len(noteIDs))
+		default:
+			fmt.Printf("✅ Project %s deleted (%d notes: %s)\n", project.Name, affected, projectDeleteNotes)
+		}
+	},
+}
+
+// migrateProjectMemberships - 旧形式の project_name を projects に移行し、
+// front matter・notes.json・project_notes.json の所属を揃える
+func migrateProjectMemberships(config model.Config) (int, int, error) {
+	notes, notesJsonPath, err := store.LoadNotes(config)
+	if err != nil {
+		return 0, 0, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	projects, _, err := store.LoadProjects(config)
+	if err != nil {
+		return 0, 0, fmt.Errorf("❌ Failed to load projects.json: %w", err)
+	}
+	projectNotes, projectNotesJsonPath, err := store.LoadProjectNotes(config)
+	if err != nil {
+		return 0, 0, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+
+	projectName := make(map[string]string)
+	for _, p := range projects {
+		projectName[p.ProjectID] = p.Name
+	}
+	// project_notes.json にしかない所属を補う
+	for _, pn := range projectNotes {
+		name, ok := projectName[pn.ProjectID]
+		if !ok {
+			continue
+		}
+		for i := range notes {
+			if notes[i].ID == pn.NoteID && !slices.Contains(notes[i].Projects, name) {
+				notes[i].Projects = append(notes[i].Projects, name)
+			}
+		}
+	}
+
+	// front matter に反映（旧形式のもの、または所属が足りないものだけ書き換える）
+	filesUpdated := 0
+	for _, note := range notes {
+		path := noteFilePath(note, config)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("⚠️ %s: %v", note.ID, err)
+			continue
+		}
+		// 所属の読み取りだけなのでノート種別を問わず NoteFrontMatter で読む
+		frontMatter, _, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content))
+		if err != nil {
+			log.Printf("⚠️ %s: %v", note.ID, err)
+			continue
+		}
+		needsUpdate := strings.Contains(string(content), "\nproject_name:")
+		for _, name := range note.Projects {
+			if !slices.Contains(frontMatter.Projects, name) {
+				needsUpdate = true
+			}
+		}
+		for _, name := range frontMatter.Projects {
+			if !slices.Contains(note.Projects, name) {
+				note.Projects = append(note.Projects, name)
+			}
+		}
+
+		if needsUpdate {
+			err = editNoteFrontMatter(path, note.NoteType, func(projects *[]string, _, _ *bool) {
+				*projects = note.Projects
+			})
+			if err != nil {
+				log.Printf("⚠️ %s: %v", note.ID, err)
+				continue
+			}
+			filesUpdated++
+		}
+		for i := range notes {
+			if notes[i].ID == note.ID {
+				notes[i].Projects = note.Projects
+			}
+		}
+	}
+	if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+		return 0, 0, fmt.Errorf("❌ Failed to update notes.json: %w", err)
+	}
+
+	// 所属先のプロジェクトがなければ作成し、project_notes.json にペアを追加
+	pairsAdded := 0
+	for _, note := range notes {
+		for _, name := range note.Projects {
+			i, found := store.FindProject(projects, name)
+			if !found || projects[i].Name != name {
+				now := time.Now().Format("2006-01-02 15:04:05")
+				project := model.Project{
+					ProjectID: store.GetNextProjectID(projects),
+					Name:      name,
+					Status:    model.ProjectStatusActive,
+					CreatedAt: now,
+					UpdatedAt: now,
+				}
+				projects = append(projects, project)
+				i = len(projects) - 1
+				log.Printf("✅ Project '%s' created (%s)", name, project.ProjectID)
+			}
+			pair := model.ProjectNote{ProjectID: projects[i].ProjectID, NoteID: note.ID}
+			if !slices.Contains(projectNotes, pair) {
+				projectNotes = append(projectNotes, pair)
+				pairsAdded++
+			}
+		}
+	}
+	_, projectsJsonPath, err := store.LoadProjects(config)
+	if err != nil {
+		return 0, 0, fmt.Errorf("❌ Failed to load projects.json: %w", err)
+	}
+	if err := store.SaveUpdatedJson(projects, projectsJsonPath); err != nil {
+		return 0, 0, fmt.Errorf("❌ Failed to update projects.json: %w", err)
+	}
+	if err := store.SaveUpdatedJson(projectNotes, projectNotesJsonPath); err != nil {
+		return 0, 0, fmt.Errorf("❌ Failed to update project_notes.json: %w", err)
+	}
+
+	return filesUpdated, pairsAdded, nil
+}
+
+var migrateProjectCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate project_name in front matter to the projects list",
+	Long: `Convert the old single-valued project_name field into the projects list
+in every note, and make front matter, notes.json and project_notes.json agree
+on which projects each note belongs to.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		filesUpdated, pairsAdded, err := migrateProjectMemberships(*config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		fmt.Printf("✅ Migration finished: %d notes updated, %d project links added\n", filesUpdated, pairsAdded)
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(updateProjectCmd)
+	projectCmd.AddCommand(removeNoteProjectCmd)
+	projectCmd.AddCommand(renameProjectCmd)
+	projectCmd.AddCommand(archiveProjectCmd)
+	projectCmd.AddCommand(deleteProjectCmd)
+	projectCmd.AddCommand(migrateProjectCmd)
+	addProjectFieldFlags(updateProjectCmd)
+	archiveProjectCmd.Flags().BoolVar(&projectArchiveNotes, "notes", false, "Also move the project's notes to the archive directory")
+	deleteProjectCmd.Flags().StringVar(&projectDeleteNotes, "notes", "keep", "What to do with the project's notes (keep, archive, trash, delete)")
+	deleteProjectCmd.Flags().BoolVarP(&projectDeleteYes, "yes", "y", false, "Do not ask for confirmation")
+}
@@ -0,0 +1,237 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var promoteTo string
+var promoteSourceID string
+var promoteEdit bool
+var promoteArchive bool
+
+// promoteNote - ノートを別タイプの新しいノートとして書き起こし、元ノートへのリンクを残す
+func promoteNote(noteSeqID, noteType, sourceID string, config model.Config) (string, model.Note, error) {
+	if noteType != "permanent" && noteType != "literature" {
+		return "", model.Note{}, fmt.Errorf("❌ Invalid note type: %s. Must be 'permanent' or 'literature'", noteType)
+	}
+
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	var original model.Note
+	found := false
+	for _, note := range notes {
+		if note.SeqID == noteSeqID {
+			original = note
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", model.Note{}, fmt.Errorf("❌ Note with ID %s not found", noteSeqID)
+	}
+	if original.NoteType == noteType {
+		return "", model.Note{}, fmt.Errorf("❌ Note %s is already a %s note", noteSeqID, noteType)
+	}
+	if original.NoteType == "task" {
+		return "", model.Note{}, fmt.Errorf("❌ Task notes cannot be promoted")
+	}
+
+	// `--source` の存在を事前に確認
+	if sourceID != "" {
+		sources, _, err := store.LoadSources(config)
+		if err != nil {
+			return "", model.Note{}, fmt.Errorf("❌ Failed to load sources.json: %w", err)
+		}
+		foundSource := false
+		for _, s := range sources {
+			if s.SourceID == sourceID {
+				foundSource = true
+				break
+			}
+		}
+		if !foundSource {
+			return "", model.Note{}, fmt.Errorf("❌ Source ID '%s' not found", sourceID)
+		}
+	}
+
+	originalContent, err := os.ReadFile(filepath.Join(config.ZettelDir, original.ID+".md"))
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+
+	originalFrontMatter, body, err := store.ParseFrontMatter[model.NoteFrontMatter](string(originalContent))
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	var filePath string
+	var note model.Note
+	if noteType == "permanent" {
+		filePath, note, err = createNewPermanentNote(original.Title, config)
+	} else {
+		filePath, note, err = createNewLiteratureNote(original.Title, config)
+	}
+	if err != nil {
+		return "", model.Note{}, err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+
+	frontMatter, _, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content))
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	// タグ・リンク・プロジェクトを引き継ぎ、元ノートへのリンクを追加
+	frontMatter.Tags = originalFrontMatter.Tags
+	frontMatter.Links = append(append([]string{}, originalFrontMatter.Links...), original.ID)
+	frontMatter.Projects = originalFrontMatter.Projects
+
+	updatedContent := store.UpdateFrontMatter(&frontMatter, body)
+	if err := os.WriteFile(filePath, []byte(updatedContent), 0644); err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+
+	if err := syncNoteRelations(note.ID, "", updatedContent, config); err != nil {
+		return "", model.Note{}, err
+	}
+
+	// `notes.json` に本文とプロジェクトを反映
+	notes, notesJsonPath, err := store.LoadNotes(config)
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	for i := range notes {
+		if notes[i].ID == note.ID {
+			notes[i].Content = body
+			notes[i].Projects = original.Projects
+			note = notes[i]
+			break
+		}
+	}
+	if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to update notes.json: %w", err)
+	}
+
+	// 元ノートが所属していたプロジェクトにも追加
+	projectNotes, _, err := store.LoadProjectNotes(config)
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+	for _, pn := range projectNotes {
+		if pn.NoteID == original.ID {
+			if err := store.InsertProjectNoteToJson(model.ProjectNote{ProjectID: pn.ProjectID, NoteID: note.ID}, config); err != nil {
+				return "", model.Note{}, err
+			}
+		}
+	}
+
+	if sourceID != "" {
+		if err := store.InsertSourceNoteToJson(model.SourceNote{SourceID: sourceID, NoteID: note.ID}, config); err != nil {
+			return "", model.Note{}, err
+		}
+	}
+
+	return filePath, note, nil
+}
+
+// promoteCmd represents the promote command
+var promoteCmd = &cobra.Command{
+	Use:   "promote [noteID]",
+	Short: "Promote a fleeting note to a literature or permanent note",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		noteID := args[0]
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		filePath, note, err := promoteNote(noteID, promoteTo, promoteSourceID, *config)
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Printf("✅ Note %s promoted to %s note %s\n", noteID, promoteTo, note.SeqID)
+
+		if promoteArchive {
+			if err := store.ArchiveNote(noteID, *config); err != nil {
+				log.Printf("⚠️ Failed to archive original note: %v", err)
+			}
+		}
+
+		if promoteEdit {
+			initialContent, err := os.ReadFile(filePath)
+			if err != nil {
+				log.Printf("❌ Failed to read Markdown file: %v", err)
+				return
+			}
+
+			if err := util.OpenEditor(filePath, *config); err != nil {
+				log.Printf("❌ Failed to open editor: %v\n", err)
+				return
+			}
+
+			mdContent, err := os.ReadFile(filePath)
+			if err != nil {
+				log.Printf("❌ Failed to read Markdown file: %v", err)
+				return
+			}
+
+			if err := syncNoteRelations(note.ID, string(initialContent), string(mdContent), *config); err != nil {
+				log.Printf("⚠️ Failed to sync tags and links: %v", err)
+			}
+
+			frontMatter, body, err := store.ParseFrontMatter[model.NoteFrontMatter](string(mdContent))
+			if err != nil {
+				log.Printf("⚠️ Failed to parse front matter for %s: %v", filePath, err)
+				return
+			}
+
+			notes, notesJsonPath, err := store.LoadNotes(*config)
+			if err != nil {
+				log.Printf("❌ Error loading notes from JSON: %v", err)
+				os.Exit(1)
+			}
+			for i := range notes {
+				if notes[i].ID == note.ID {
+					notes[i].Title = frontMatter.Title
+					notes[i].Content = body
+					notes[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+					break
+				}
+			}
+			if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+				log.Printf("❌ Failed to update notes.json: %v\n", err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promoteCmd)
+	promoteCmd.Flags().StringVar(&promoteTo, "to", "permanent", "Target note type (permanent, literature)")
+	promoteCmd.Flags().StringVar(&promoteSourceID, "source", "", "Link the promoted note to a source (e.g. s001)")
+	promoteCmd.Flags().BoolVarP(&promoteEdit, "edit", "e", false, "Open the promoted note in the editor")
+	promoteCmd.Flags().BoolVar(&promoteArchive, "archive", false, "Archive the original note after promotion")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+)
+
+// collectNoteLinks - フロントマターの `links:` と本文の Markdown リンクをまとめる
+func collectNoteLinks(frontMatterLinks []string, body string) []string {
+	links := append([]string{}, frontMatterLinks...)
+	return append(links, extractMarkdownLinks(body)...)
+}
+
+// syncNoteRelations - 編集前後のフロントマターを比較し、tags / note_tags / links を更新
+func syncNoteRelations(noteID, oldContent, newContent string, config model.Config) error {
+	oldFrontMatter, oldBody, err := store.ParseFrontMatter[model.NoteFrontMatter](oldContent)
+	if err != nil {
+		// 編集前のフロントマターが壊れていた場合は、空の状態から同期する
+		oldFrontMatter = model.NoteFrontMatter{}
+		oldBody = ""
+	}
+
+	newFrontMatter, newBody, err := store.ParseFrontMatter[model.NoteFrontMatter](newContent)
+	if err != nil {
+		return fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	if err := store.SyncNoteTags(noteID, oldFrontMatter.Tags, newFrontMatter.Tags, config); err != nil {
+		return err
+	}
+
+	oldLinks := collectNoteLinks(oldFrontMatter.Links, oldBody)
+	newLinks := collectNoteLinks(newFrontMatter.Links, newBody)
+	if err := store.SyncNoteLinks(noteID, oldLinks, newLinks, config); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,11 +4,18 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
+	"log"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+// outputFormat は機械可読な出力形式 (table, json, csv)
+var outputFormat string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "ztl",
@@ -32,4 +39,38 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, csv)")
+}
+
+// validateOutputFormat - `--output` の値をチェック。formats を指定した場合はそのコマンドが対応する形式だけを受け付ける
+func validateOutputFormat(formats ...string) error {
+	if len(formats) == 0 {
+		formats = []string{"table", "json", "csv"}
+	}
+	if slices.Contains(formats, outputFormat) {
+		return nil
+	}
+	quoted := make([]string, len(formats))
+	for i, f := range formats {
+		quoted[i] = "'" + f + "'"
+	}
+	must := quoted[len(quoted)-1]
+	if len(quoted) > 1 {
+		must = strings.Join(quoted[:len(quoted)-1], ", ") + " or " + must
+	}
+	return fmt.Errorf("❌ Invalid output format: %s. Must be %s", outputFormat, must)
+}
+
+// errorMessage - エラーを ❌ 付きのメッセージにする。メッセージが既に ❌ で始まる場合は重ねて付けない
+func errorMessage(err error) string {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "❌") {
+		msg = "❌ " + msg
+	}
+	return msg
+}
+
+// fatal - エラーを表示して終了する
+func fatal(err error) {
+	log.Fatal(errorMessage(err))
 }
@@ -5,24 +5,47 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/nakachan-ing/ztl-cli/internal/model"
 	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
 	"github.com/spf13/cobra"
 )
 
-var sourceType, sourceTitle, sourceAuthor, sourcePublisher, sourceURL, sourceISBN string
+var sourceType, sourceTitle, sourcePublisher, sourceURL, sourceISBN string
+var sourceAuthors []string
 var sourceYear int
 var sourcePageSize int
-var newTitle, newAuthor, newPublisher, newURL, newISBN string
+var newTitle, newPublisher, newURL, newISBN string
+var newAuthors []string
 var newYear int
 
+// 書誌情報（new / edit 共通）
+var sourceDOI, sourceArXiv, sourceJournal, sourceVolume, sourceIssue, sourcePages, sourceKey string
+var sourceEditors []string
+
+// 読書管理（new / edit 共通）
+var sourceStatus, sourceStarted, sourceFinished string
+var sourceRating int
+var sourceTags []string
+
+// source list のフィルタと並び順
+var sourceListStatus, sourceListType, sourceListAuthor, sourceListSort string
+var sourceListTags []string
+var sourceListMinRating int
+var sourceListReverse bool
+
+var sourceShowExcerpt int
+
 // sourceCmd represents the source command
 var sourceCmd = &cobra.Command{
 	Use:   "source",
@@ -56,11 +79,20 @@ var sourceNewCmd = &cobra.Command{
 			SourceID:   store.GetNextSourceID(sources),
 			SourceType: sourceType,
 			Title:      sourceTitle,
-			Author:     sourceAuthor,
+			Authors:    parseAuthorFlags(sourceAuthors),
 			Publisher:  sourcePublisher,
 			Year:       sourceYear,
 			ISBN:       sourceISBN,
 			URL:        sourceURL,
+			Status:     model.SourceStatusToRead,
+			CreatedAt:  time.Now().Format("2006-01-02 15:04:05"),
+		}
+		applySourceCitationFlags(cmd, &source)
+		if err := applySourceReadingFlags(cmd, &source, time.Now()); err != nil {
+			log.Fatalf("%v", err)
+		}
+		if source.CitationKey == "" {
+			source.CitationKey = generateCitationKey(source, sources)
 		}
 
 		err = store.InsertSourceToJson(source, *config)
@@ -77,6 +109,10 @@ var sourceListCmd = &cobra.Command{
 	Short:   "List all sources (book, web, paper, video)",
 	Aliases: []string{"ls"},
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateOutputFormat(); err != nil {
+			log.Fatalf("%v", err)
+		}
+
 		config, err := store.LoadConfig()
 		if err != nil {
 			log.Fatalf("❌ Error loading config: %v", err)
@@ -87,9 +123,36 @@ var sourceListCmd = &cobra.Command{
 			log.Printf("❌ Failed to load sources.json: %v", err)
 		}
 
+		sources, err = filterSources(sources)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if err := sortSources(sources, sourceListSort, sourceListReverse); err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		switch outputFormat {
+		case "json":
+			jsonBytes, err := json.MarshalIndent(sources, "", "  ")
+			if err != nil {
+				log.Fatalf("❌ Failed to convert to JSON: %v", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return
+		case "csv":
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"source_id", "source_type", "title", "authors", "year", "status", "rating", "started_at", "finished_at", "tags"})
+			for _, row := range sources {
+				w.Write([]string{row.SourceID, row.SourceType, row.Title, model.JoinPeople(row.Authors), fmt.Sprint(row.Year),
+					row.Status, fmt.Sprint(row.Rating), row.StartedAt, row.FinishedAt, strings.Join(row.Tags, ",")})
+			}
+			w.Flush()
+			return
+		}
+
 		// Handle case where no notes match
 		if len(sources) == 0 {
-			fmt.Println("No matching notes found.")
+			fmt.Println("No matching sources found.")
 			return
 		}
 
@@ -97,7 +160,7 @@ var sourceListCmd = &cobra.Command{
 		page := 0
 
 		fmt.Println(strings.Repeat("=", 30))
-		fmt.Printf("Zettelkasten: %v notes shown\n", len(sources))
+		fmt.Printf("Zettelkasten: %v sources shown\n", len(sources))
 		fmt.Println(strings.Repeat("=", 30))
 
 		if sourcePageSize == -1 {
@@ -111,7 +174,7 @@ var sourceListCmd = &cobra.Command{
 
 			// 範囲チェック
 			if start >= len(sources) {
-				fmt.Println("No more notes to display.")
+				fmt.Println("No more sources to display.")
 				break
 			}
 			if end > len(sources) {
@@ -129,27 +192,37 @@ var sourceListCmd = &cobra.Command{
 				text.FgGreen.Sprintf("Source Type"),
 				text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Title")),
 				text.FgGreen.Sprintf("Author"),
-				text.FgGreen.Sprintf("Publisher"),
-				text.FgGreen.Sprintf("Year"), text.FgGreen.Sprintf("URL"),
+				text.FgGreen.Sprintf("Year"),
+				text.FgGreen.Sprintf("Status"),
+				text.FgGreen.Sprintf("Rating"),
+				text.FgGreen.Sprintf("Tags"),
 			})
 
-			// フィルタされたノートをテーブルに追加
+			// フィルタされたソースをテーブルに追加
 			for _, row := range sources[start:end] {
+				status := row.Status
+				switch status {
+				case model.SourceStatusReading:
+					status = text.FgHiYellow.Sprint(status)
+				case model.SourceStatusRead:
+					status = text.FgHiGreen.Sprint(status)
+				}
 
 				t.AppendRow(table.Row{
 					row.SourceID,
 					row.SourceType,
-					row.Title,
-					row.Author,
-					row.Publisher,
+					truncateDisplay(row.Title, 50),
+					authorSummary(row.Authors),
 					row.Year,
-					row.URL,
+					status,
+					ratingStars(row.Rating),
+					strings.Join(row.Tags, ", "),
 				})
 			}
 
 			t.Render()
 
-			if permanentPageSize == len(sources) {
+			if sourcePageSize == len(sources) {
 				break
 			}
 
@@ -171,7 +244,7 @@ var sourceListCmd = &cobra.Command{
 }
 
 var sourceShowCmd = &cobra.Command{
-	Use:     "show",
+	Use:     "show [sourceID]",
 	Short:   "Show source detail (book, web, paper, video)",
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"s"},
@@ -189,59 +262,83 @@ var sourceShowCmd = &cobra.Command{
 			log.Printf("❌ Failed to load sources.json: %v", err)
 		}
 
-		// `notes.json` をロード（タイトル取得用）
-		notes, _, err := store.LoadNotes(*config)
-		if err != nil {
-			log.Printf("❌ Failed to load notes.json: %v", err)
+		// 指定されたソース（ID または引用キー）の情報を取得
+		source, found := findSourceByRef(sources, sourceID)
+		if !found {
+			log.Fatalf("❌ Source ID '%s' not found", sourceID)
 		}
+		sourceID = source.SourceID
 
-		// `source_notes.json` をロード
-		sourceNotes, _, err := store.LoadSourceNotes(*config)
+		// 関連ノートをロケーター順に取得
+		relatedNotes, err := sourceReadingNotes(sourceID, sourceShowExcerpt, *config)
 		if err != nil {
-			log.Printf("❌ Failed to load source_notes.json: %v", err)
-		}
-
-		// 指定された `sourceID` の情報を取得
-		var source model.Source
-		found := false
-		for _, s := range sources {
-			if s.SourceID == sourceID {
-				source = s
-				found = true
-				break
-			}
-		}
-		if !found {
-			log.Printf("❌ Source ID '%s' not found", sourceID)
+			log.Fatalf("%v", err)
 		}
 
-		// 関連ノートを取得
-		var relatedNotes []model.Note
-		for _, sn := range sourceNotes {
-			if sn.SourceID == sourceID {
-				for _, n := range notes {
-					if n.SeqID == sn.NoteID {
-						relatedNotes = append(relatedNotes, n)
-						break
-					}
-				}
+		if outputFormat == "json" {
+			jsonBytes, err := json.MarshalIndent(struct {
+				Source model.Source        `json:"source"`
+				Notes  []sourceReadingNote `json:"notes"`
+			}{source, relatedNotes}, "", "  ")
+			if err != nil {
+				log.Fatalf("❌ Failed to convert to JSON: %v", err)
 			}
+			fmt.Println(string(jsonBytes))
+			return
 		}
 
 		// 出力
 		fmt.Printf("📖 %s\n", source.Title)
 		fmt.Println(strings.Repeat("─", len(source.Title)+3))
 		fmt.Printf("Type:      %s\n", source.SourceType)
-		fmt.Printf("Author:    %s\n", source.Author)
+		fmt.Printf("Authors:   %s\n", model.JoinPeople(source.Authors))
 		fmt.Printf("Publisher: %s\n", source.Publisher)
 		fmt.Printf("Year:      %d\n", source.Year)
 		if source.URL != "" {
 			fmt.Printf("URL:       %s\n", source.URL)
 		}
-		fmt.Println("\n📖 Related Notes:")
+		if len(source.Editors) > 0 {
+			fmt.Printf("Editors:   %s\n", model.JoinPeople(source.Editors))
+		}
+		if source.Journal != "" {
+			fmt.Printf("Journal:   %s\n", source.Journal)
+		}
+		if source.Volume != "" || source.Issue != "" || source.Pages != "" {
+			fmt.Printf("Volume:    %s  Issue: %s  Pages: %s\n", source.Volume, source.Issue, source.Pages)
+		}
+		if source.ISBN != "" {
+			fmt.Printf("ISBN:      %s\n", source.ISBN)
+		}
+		if source.DOI != "" {
+			fmt.Printf("DOI:       %s\n", source.DOI)
+		}
+		if source.ArXiv != "" {
+			fmt.Printf("arXiv:     %s\n", source.ArXiv)
+		}
+		if source.CitationKey != "" {
+			fmt.Printf("Key:       @%s\n", source.CitationKey)
+		}
+		fmt.Printf("Status:    %s\n", source.Status)
+		if source.Rating > 0 {
+			fmt.Printf("Rating:    %s\n", ratingStars(source.Rating))
+		}
+		if source.StartedAt != "" || source.FinishedAt != "" {
+			fmt.Printf("Reading:   %s → %s\n", source.StartedAt, source.FinishedAt)
+		}
+		if len(source.Tags) > 0 {
+			fmt.Printf("Tags:      %s\n", strings.Join(source.Tags, ", "))
+		}
+		fmt.Printf("\n📖 Related Notes (%d):\n", len(relatedNotes))
 
-		for _, note := range relatedNotes {
-			fmt.Printf("- [%s](%s.md)\n", note.Title, note.ID)
+		for _, row := range relatedNotes {
+			locator := row.Locator
+			if locator == "" {
+				locator = "-"
+			}
+			fmt.Printf("- %s  [%s] %s %s\n", text.FgHiCyan.Sprintf("%-10s", locator), row.Note.SeqID, row.Note.Title, text.FgHiBlack.Sprintf("(%s.md)", row.Note.ID))
+			if row.Excerpt != "" {
+				fmt.Printf("    %s\n", text.FgHiBlack.Sprint(row.Excerpt))
+			}
 		}
 
 		fmt.Println()
@@ -277,7 +374,7 @@ var sourceEditCmd = &cobra.Command{
 					sources[i].Title = newTitle
 				}
 				if cmd.Flags().Changed("author") {
-					sources[i].Author = newAuthor
+					sources[i].Authors = parseAuthorFlags(newAuthors)
 				}
 				if cmd.Flags().Changed("publisher") {
 					sources[i].Publisher = newPublisher
@@ -291,6 +388,10 @@ var sourceEditCmd = &cobra.Command{
 				if cmd.Flags().Changed("url") {
 					sources[i].URL = newURL
 				}
+				applySourceCitationFlags(cmd, &sources[i])
+				if err := applySourceReadingFlags(cmd, &sources[i], time.Now()); err != nil {
+					log.Fatalf("%v", err)
+				}
 
 				break
 			}
@@ -311,7 +412,7 @@ var sourceEditCmd = &cobra.Command{
 }
 
 var sourceAddNoteCmd = &cobra.Command{
-	Use:     "add-note",
+	Use:     "add-note [noteID] [sourceID]",
 	Short:   "Add note to source",
 	Args:    cobra.ExactArgs(2),
 	Aliases: []string{"a-n"},
@@ -334,9 +435,9 @@ var sourceAddNoteCmd = &cobra.Command{
 			log.Printf("❌ Failed to load sources.json: %v", err)
 		}
 
-		sourceNotes, sourceNotesJsonPath, err := store.LoadSourceNotes(*config)
+		locator, err := locatorFromFlags()
 		if err != nil {
-			log.Printf("❌ Failed to load source_notes.json: %v", err)
+			log.Fatalf("%v", err)
 		}
 
 		foundSource := false
@@ -347,40 +448,27 @@ var sourceAddNoteCmd = &cobra.Command{
 			}
 		}
 		if !foundSource {
-			log.Printf("❌ Source ID '%s' not found", sourceID)
+			log.Fatalf("❌ Source ID '%s' not found", sourceID)
 		}
 
-		var noteTitle string
-		var noteID string
-		foundNote := false
-		for i := range notes {
-			if notes[i].SeqID == noteSeqID {
-				noteTitle = notes[i].Title
-				noteID = notes[i].ID
-				foundNote = true
-				break
-			}
-		}
+		note, foundNote := findNoteByRef(notes, noteSeqID)
 		if !foundNote {
-			log.Printf("❌ Note ID '%s' not found", noteSeqID)
-		}
-
-		for _, sn := range sourceNotes {
-			if sn.SourceID == sourceID && sn.NoteID == noteID {
-				log.Printf("⚠️ Note %s is already linked to source %s", noteID, sourceID)
-			}
+			log.Fatalf("❌ Note ID '%s' not found", noteSeqID)
 		}
 
-		sourceNotes = append(sourceNotes, model.SourceNote{SourceID: sourceID, NoteID: noteID})
-
-		// `source_notes.json` を保存
-		err = store.SaveUpdatedJson(sourceNotes, sourceNotesJsonPath)
+		added, err := store.LinkSourceNote(model.SourceNote{SourceID: sourceID, NoteID: note.ID, Locator: locator}, *config)
 		if err != nil {
-			log.Printf("❌ Failed to update source_notes.json: %v", err)
+			log.Fatalf("%v", err)
 		}
 
-		log.Printf("✅ Note '%s' (%s) added to source '%s'!", noteID, noteTitle, sourceID)
-
+		switch {
+		case added:
+			log.Printf("✅ Note '%s' (%s) added to source '%s'!", note.ID, note.Title, sourceID)
+		case locator != "":
+			log.Printf("✅ Updated locator of note '%s' in source '%s' to %s", note.ID, sourceID, locator)
+		default:
+			log.Printf("⚠️ Note %s is already linked to source %s", note.ID, sourceID)
+		}
 	},
 }
 
@@ -455,17 +543,70 @@ func init() {
 	rootCmd.AddCommand(sourceCmd)
 	sourceNewCmd.Flags().StringVar(&sourceType, "type", "", "Source type (book, web, paper, video)")
 	sourceNewCmd.Flags().StringVar(&sourceTitle, "title", "", "Title of the source")
-	sourceNewCmd.Flags().StringVar(&sourceAuthor, "author", "", "Author of the source")
+	sourceNewCmd.Flags().StringArrayVar(&sourceAuthors, "author", nil, "Author as \"Family, Given\" or \"{Organization}\" (repeatable)")
 	sourceNewCmd.Flags().StringVar(&sourcePublisher, "publisher", "", "Publisher")
 	sourceNewCmd.Flags().IntVar(&sourceYear, "year", 0, "Publication year")
 	sourceNewCmd.Flags().StringVar(&sourceISBN, "isbn", "", "ISBN (for books only)")
 	sourceNewCmd.Flags().StringVar(&sourceURL, "url", "", "URL (for web, video)")
 	sourceListCmd.Flags().IntVar(&sourcePageSize, "limit", 20, "Set the number of notes to display per page (-1 for all)")
 	sourceEditCmd.Flags().StringVar(&newTitle, "title", "", "New title")
-	sourceEditCmd.Flags().StringVar(&newAuthor, "author", "", "New author")
+	sourceEditCmd.Flags().StringArrayVar(&newAuthors, "author", nil, "New authors as \"Family, Given\" or \"{Organization}\" (repeatable, replaces all)")
 	sourceEditCmd.Flags().StringVar(&newPublisher, "publisher", "", "New publisher")
 	sourceEditCmd.Flags().IntVar(&newYear, "year", 0, "New publication year")
 	sourceEditCmd.Flags().StringVar(&newISBN, "isbn", "", "New ISBN")
 	sourceEditCmd.Flags().StringVar(&newURL, "url", "", "New URL")
+	addSourceCitationFlags(sourceNewCmd)
+	addSourceCitationFlags(sourceEditCmd)
+	addSourceReadingFlags(sourceNewCmd)
+	addSourceReadingFlags(sourceEditCmd)
+	addLocatorFlags(sourceAddNoteCmd)
+	sourceShowCmd.Flags().IntVar(&sourceShowExcerpt, "excerpt", 160, "Length of the note excerpt (0 to hide)")
+	sourceListCmd.Flags().StringVar(&sourceListStatus, "status", "", "Filter by reading status (to-read, reading, read)")
+	sourceListCmd.Flags().StringVar(&sourceListType, "type", "", "Filter by source type (book, web, paper, video)")
+	sourceListCmd.Flags().StringVar(&sourceListAuthor, "author", "", "Filter by author name (partial match)")
+	sourceListCmd.Flags().StringSliceVarP(&sourceListTags, "tag", "t", []string{}, "Filter by tags")
+	sourceListCmd.Flags().IntVar(&sourceListMinRating, "min-rating", 0, "Only show sources rated at least this (1-5)")
+	sourceListCmd.Flags().StringVar(&sourceListSort, "sort", "id", "Sort by id, title, author, year, rating, status, started, finished or added")
+	sourceListCmd.Flags().BoolVar(&sourceListReverse, "reverse", false, "Reverse the sort order")
+
+}
+
+// addSourceCitationFlags - 引用用の書誌情報フラグを登録
+func addSourceCitationFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sourceDOI, "doi", "", "DOI (e.g. 10.1000/xyz123)")
+	cmd.Flags().StringVar(&sourceArXiv, "arxiv", "", "arXiv ID (e.g. 2101.00001)")
+	cmd.Flags().StringVar(&sourceJournal, "journal", "", "Journal or container title")
+	cmd.Flags().StringVar(&sourceVolume, "volume", "", "Volume")
+	cmd.Flags().StringVar(&sourceIssue, "issue", "", "Issue / number")
+	cmd.Flags().StringVar(&sourcePages, "pages", "", "Page range (e.g. 123-145)")
+	cmd.Flags().StringVar(&sourceKey, "key", "", "Citation key (e.g. smith2020deep)")
+	cmd.Flags().StringArrayVar(&sourceEditors, "editor", nil, "Editor as \"Family, Given\" or \"{Organization}\" (repeatable)")
+}
 
+// applySourceCitationFlags - 指定された書誌情報フラグのみ反映
+func applySourceCitationFlags(cmd *cobra.Command, source *model.Source) {
+	if cmd.Flags().Changed("doi") {
+		source.DOI = util.NormalizeDOI(sourceDOI)
+	}
+	if cmd.Flags().Changed("arxiv") {
+		source.ArXiv = strings.TrimPrefix(strings.TrimSpace(sourceArXiv), "arXiv:")
+	}
+	if cmd.Flags().Changed("journal") {
+		source.Journal = sourceJournal
+	}
+	if cmd.Flags().Changed("volume") {
+		source.Volume = sourceVolume
+	}
+	if cmd.Flags().Changed("issue") {
+		source.Issue = sourceIssue
+	}
+	if cmd.Flags().Changed("pages") {
+		source.Pages = sourcePages
+	}
+	if cmd.Flags().Changed("key") {
+		source.CitationKey = sourceKey
+	}
+	if cmd.Flags().Changed("editor") {
+		source.Editors = parseAuthorFlags(sourceEditors)
+	}
 }
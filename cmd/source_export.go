@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var sourceExportFormat string
+var sourceExportProjects []string
+var sourceExportFile string
+
+// projectSources - 指定プロジェクトのノートに紐づくソースだけに絞り込む
+func projectSources(sources []model.Source, projectRefs []string, config model.Config) ([]model.Source, error) {
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	noteIDs, err := projectFilterIDs(projectRefs, notes, config)
+	if err != nil {
+		return nil, err
+	}
+	sourceNotes, _, err := store.LoadSourceNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+
+	linked := make(map[string]bool)
+	for _, sn := range sourceNotes {
+		if noteIDs[sn.NoteID] {
+			linked[sn.SourceID] = true
+		}
+	}
+	var filtered []model.Source
+	for _, source := range sources {
+		if linked[source.SourceID] {
+			filtered = append(filtered, source)
+		}
+	}
+	return filtered, nil
+}
+
+var sourceExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export sources as BibTeX, CSL-JSON or RIS",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Fatalf("❌ Error loading config: %v", err)
+		}
+
+		sources, _, err := store.LoadSources(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load sources.json: %v", err)
+		}
+		if len(sourceExportProjects) > 0 {
+			if sources, err = projectSources(sources, sourceExportProjects, *config); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+
+		var refs []util.Reference
+		for _, source := range sources {
+			refs = append(refs, sourceToReference(source))
+		}
+
+		var buf bytes.Buffer
+		switch sourceExportFormat {
+		case "bibtex":
+			err = util.WriteBibTeX(&buf, refs)
+		case "csl-json":
+			err = util.WriteCSLJSON(&buf, refs)
+		case "ris":
+			err = util.WriteRIS(&buf, refs)
+		default:
+			log.Fatalf("❌ Unsupported format: %s. Must be 'bibtex', 'csl-json' or 'ris'", sourceExportFormat)
+		}
+		if err != nil {
+			log.Fatalf("❌ Failed to export sources: %v", err)
+		}
+
+		if sourceExportFile == "" {
+			fmt.Print(buf.String())
+			return
+		}
+		if err := os.WriteFile(sourceExportFile, buf.Bytes(), 0644); err != nil {
+			log.Fatalf("❌ Failed to write %s: %v", sourceExportFile, err)
+		}
+		log.Printf("✅ Exported %d sources to %s", len(refs), sourceExportFile)
+	},
+}
+
+func init() {
+	sourceCmd.AddCommand(sourceExportCmd)
+	sourceExportCmd.Flags().StringVar(&sourceExportFormat, "format", "bibtex", "Output format (bibtex, csl-json, ris)")
+	sourceExportCmd.Flags().StringSliceVar(&sourceExportProjects, "project", nil, "Only export sources linked to notes in these projects (ID or name)")
+	sourceExportCmd.Flags().StringVar(&sourceExportFile, "file", "", "Write to a file instead of stdout")
+}
@@ -0,0 +1,381 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var sourceImportFormat string
+var sourceImportDryRun bool
+var sourceImportOverwrite bool
+
+// detectBibFormat - 拡張子（なければ内容）から bibtex / ris / csl-json を判定
+func detectBibFormat(path string, data []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".bib", ".bibtex":
+		return "bibtex", nil
+	case ".ris":
+		return "ris", nil
+	case ".json":
+		return "csl-json", nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("{")):
+		return "csl-json", nil
+	case bytes.HasPrefix(trimmed, []byte("TY  -")):
+		return "ris", nil
+	case bytes.Contains(trimmed, []byte("@")):
+		return "bibtex", nil
+	}
+	return "", fmt.Errorf("❌ Cannot detect the format of %s (use --format bibtex|ris|csl-json)", path)
+}
+
+func parseReferences(format string, data []byte) ([]util.Reference, error) {
+	switch format {
+	case "bibtex":
+		return util.ParseBibTeX(bytes.NewReader(data))
+	case "ris":
+		return util.ParseRIS(bytes.NewReader(data))
+	case "csl-json":
+		return util.ParseCSLJSON(bytes.NewReader(data))
+	}
+	return nil, fmt.Errorf("❌ Unsupported format: %s. Must be 'bibtex', 'ris' or 'csl-json'", format)
+}
+
+// referenceSourceType - 各フォーマットの種別を book / web / paper / video に対応付ける
+func referenceSourceType(refType string) string {
+	switch strings.ToLower(refType) {
+	case "book", "inbook", "incollection", "booklet", "collection", "mvbook", "chap", "chapter", "ebook", "edbook", "echap":
+		return "book"
+	case "online", "webpage", "elec", "web", "blog", "post", "post-weblog", "misc-web":
+		return "web"
+	case "video", "mpct", "motion_picture", "broadcast":
+		return "video"
+	case "misc", "gen", "":
+		return ""
+	}
+	return "paper"
+}
+
+func referenceToSource(ref util.Reference) model.Source {
+	source := model.Source{
+		SourceType:  referenceSourceType(ref.Type),
+		Title:       ref.Title,
+		Authors:     parsePeople(ref.Authors),
+		Editors:     parsePeople(ref.Editors),
+		Publisher:   ref.Publisher,
+		Journal:     ref.Journal,
+		Volume:      ref.Volume,
+		Issue:       ref.Issue,
+		Pages:       ref.Pages,
+		Year:        ref.Year,
+		ISBN:        ref.ISBN,
+		DOI:         ref.DOI,
+		ArXiv:       ref.ArXiv,
+		URL:         ref.URL,
+		CitationKey: ref.CitationKey,
+	}
+	if source.ArXiv == "" {
+		source.ArXiv = util.ArXivFromDOI(source.DOI)
+	}
+	return source
+}
+
+// normalizeTitle - 重複判定用にタイトルを小文字の英数字だけにする
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// findDuplicateSource - DOI → 引用キー → ISBN → タイトル＋出版年 の順で既存ソースと照合
+func findDuplicateSource(sources []model.Source, candidate model.Source) int {
+	matchers := []func(s model.Source) bool{
+		func(s model.Source) bool {
+			return candidate.DOI != "" && strings.EqualFold(s.DOI, candidate.DOI)
+		},
+		func(s model.Source) bool {
+			return candidate.CitationKey != "" && s.CitationKey == candidate.CitationKey
+		},
+		func(s model.Source) bool {
+			isbn := func(v string) string { return strings.NewReplacer("-", "", " ", "").Replace(v) }
+			return candidate.ISBN != "" && isbn(s.ISBN) == isbn(candidate.ISBN)
+		},
+		func(s model.Source) bool {
+			return normalizeTitle(s.Title) != "" && normalizeTitle(s.Title) == normalizeTitle(candidate.Title) &&
+				(s.Year == 0 || candidate.Year == 0 || s.Year == candidate.Year)
+		},
+	}
+	for _, match := range matchers {
+		for i, s := range sources {
+			if match(s) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// sameSourceValue - 表記の違いだけなら同じ値とみなす（DOI の大文字小文字、ISBN のハイフン）
+func sameSourceValue(name string, a, b reflect.Value) bool {
+	switch name {
+	case "DOI":
+		return strings.EqualFold(a.String(), b.String())
+	case "ISBN":
+		isbn := func(v string) string { return strings.NewReplacer("-", "", " ", "").Replace(v) }
+		return isbn(a.String()) == isbn(b.String())
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// mergeSource - 既存ソースの空の項目を取り込んだ値で埋める。変更があれば true。
+// 値が異なる項目は overwrite のときだけ上書きし、差分として返す。著者・編者は短いリストでは置き換えない
+func mergeSource(existing *model.Source, imported model.Source, overwrite bool) (bool, []string) {
+	before := *existing
+	var diffs []string
+	ev := reflect.ValueOf(existing).Elem()
+	iv := reflect.ValueOf(imported)
+	for i := 0; i < iv.NumField(); i++ {
+		name := iv.Type().Field(i).Name
+		if name == "SourceID" || name == "CreatedAt" || iv.Field(i).IsZero() {
+			continue
+		}
+		if ev.Field(i).IsZero() {
+			ev.Field(i).Set(iv.Field(i))
+			continue
+		}
+		if sameSourceValue(name, ev.Field(i), iv.Field(i)) {
+			continue
+		}
+		if (name == "Authors" || name == "Editors") && iv.Field(i).Len() < ev.Field(i).Len() {
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: %v → %v", name, ev.Field(i).Interface(), iv.Field(i).Interface()))
+		if overwrite {
+			ev.Field(i).Set(iv.Field(i))
+		}
+	}
+	return !reflect.DeepEqual(before, *existing), diffs
+}
+
+// generateCitationKey - 筆頭著者の姓＋出版年＋タイトルの最初の単語（例: smith2020deep）。重複時は a, b... を付ける
+func generateCitationKey(source model.Source, sources []model.Source) string {
+	clean := func(s string) string {
+		var b strings.Builder
+		for _, r := range strings.ToLower(s) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				b.WriteRune(r)
+			}
+		}
+		return b.String()
+	}
+
+	author := ""
+	if len(source.Authors) > 0 {
+		author = source.Authors[0].Surname()
+	}
+	word := ""
+	for _, w := range strings.Fields(source.Title) {
+		if w = clean(w); len(w) > 3 || (w != "" && word == "" && len(strings.Fields(source.Title)) == 1) {
+			word = w
+			break
+		}
+	}
+
+	base := clean(author)
+	if base == "" {
+		base = "anon"
+	}
+	if source.Year > 0 {
+		base += fmt.Sprint(source.Year)
+	}
+	base += word
+
+	taken := make(map[string]bool)
+	for _, s := range sources {
+		taken[s.CitationKey] = true
+	}
+	key := base
+	for suffix := 'a'; taken[key] && suffix <= 'z'; suffix++ {
+		key = base + string(suffix)
+	}
+	return key
+}
+
+type sourceImportResult struct {
+	Action string
+	Source model.Source
+	Diffs  int // 既存ソースと値が異なる項目の数
+}
+
+// importSources - 書誌情報を sources.json に取り込む（重複は空の項目だけ埋め、overwrite なら異なる値も上書き）
+func importSources(refs []util.Reference, dryRun, overwrite bool, config model.Config) ([]sourceImportResult, error) {
+	sources, sourcesJsonPath, err := store.LoadSources(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load sources.json: %w", err)
+	}
+
+	var results []sourceImportResult
+	for _, ref := range refs {
+		imported := referenceToSource(ref)
+		if imported.Title == "" {
+			log.Printf("⚠️ Skipping entry without title: %s", ref.CitationKey)
+			results = append(results, sourceImportResult{Action: "skipped", Source: imported})
+			continue
+		}
+
+		if i := findDuplicateSource(sources, imported); i >= 0 {
+			// 既存の引用キーは変えない（ノートから参照されているため）
+			if sources[i].CitationKey != "" {
+				imported.CitationKey = ""
+			}
+			action := "unchanged"
+			changed, diffs := mergeSource(&sources[i], imported, overwrite)
+			if changed {
+				action = "updated"
+			}
+			if !overwrite {
+				for _, diff := range diffs {
+					log.Printf("⚠️ %s differs from the imported entry: %s", sources[i].SourceID, diff)
+				}
+			}
+			results = append(results, sourceImportResult{Action: action, Source: sources[i], Diffs: len(diffs)})
+			continue
+		}
+
+		if imported.SourceType == "" {
+			// misc は arXiv なら paper、URL があれば web、それ以外は book として扱う（既存ソースの種別は上書きしない）
+			switch {
+			case imported.ArXiv != "":
+				imported.SourceType = "paper"
+			case imported.URL != "":
+				imported.SourceType = "web"
+			default:
+				imported.SourceType = "book"
+			}
+		}
+		if imported.CitationKey == "" {
+			imported.CitationKey = generateCitationKey(imported, sources)
+		}
+		imported.SourceID = store.GetNextSourceID(sources)
+		imported.Status = model.SourceStatusToRead
+		imported.CreatedAt = time.Now().Format("2006-01-02 15:04:05")
+		sources = append(sources, imported)
+		results = append(results, sourceImportResult{Action: "added", Source: imported})
+	}
+
+	if !dryRun {
+		if err := store.SaveUpdatedJson(sources, sourcesJsonPath); err != nil {
+			return nil, fmt.Errorf("❌ Failed to update sources.json: %w", err)
+		}
+	}
+	return results, nil
+}
+
+var sourceImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import sources from BibTeX, RIS or CSL-JSON",
+	Long: `Import bibliography entries into sources.json.
+
+The format is detected from the file extension (.bib, .ris, .json) unless
+--format is given. Entries that already exist (same DOI, citation key, ISBN
+or title and year) are not duplicated: their empty fields are filled in and
+fields with different values are reported. Use --overwrite to replace them.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatalf("❌ Failed to read %s: %v", args[0], err)
+		}
+
+		format := sourceImportFormat
+		if format == "" {
+			if format, err = detectBibFormat(args[0], data); err != nil {
+				log.Fatalf("%v", err)
+			}
+		}
+		refs, err := parseReferences(format, data)
+		if err != nil {
+			fatal(err)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Fatalf("❌ Error loading config: %v", err)
+		}
+
+		results, err := importSources(refs, sourceImportDryRun, sourceImportOverwrite, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		counts := make(map[string]int)
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleDouble)
+		t.AppendHeader(table.Row{
+			text.FgGreen.Sprintf("Action"), text.FgGreen.Sprintf("Source ID"), text.FgGreen.Sprintf("Key"),
+			text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Title")), text.FgGreen.Sprintf("Year"),
+		})
+		diffs := 0
+		for _, r := range results {
+			counts[r.Action]++
+			if r.Diffs > 0 {
+				diffs++
+			}
+			action := r.Action
+			switch r.Action {
+			case "added":
+				action = text.FgHiGreen.Sprint(action)
+			case "updated":
+				action = text.FgHiYellow.Sprint(action)
+			case "skipped":
+				action = text.FgHiRed.Sprint(action)
+			}
+			t.AppendRow(table.Row{action, r.Source.SourceID, r.Source.CitationKey, truncateDisplay(r.Source.Title, 50), r.Source.Year})
+		}
+		if len(results) > 0 {
+			t.Render()
+		}
+
+		prefix := "✅"
+		if sourceImportDryRun {
+			prefix = "🔍 (dry run)"
+		}
+		fmt.Printf("%s %d entries (%s): %d added, %d updated, %d unchanged, %d skipped\n",
+			prefix, len(refs), format, counts["added"], counts["updated"], counts["unchanged"], counts["skipped"])
+		if diffs > 0 && !sourceImportOverwrite {
+			fmt.Printf("⚠️ %d existing sources have different values. Run again with --overwrite to replace them\n", diffs)
+		}
+	},
+}
+
+func init() {
+	sourceCmd.AddCommand(sourceImportCmd)
+	sourceImportCmd.Flags().StringVar(&sourceImportFormat, "format", "", "Input format (bibtex, ris, csl-json). Detected from the extension by default")
+	sourceImportCmd.Flags().BoolVar(&sourceImportOverwrite, "overwrite", false, "Replace fields of existing sources that differ from the imported values")
+	sourceImportCmd.Flags().BoolVar(&sourceImportDryRun, "dry-run", false, "Show what would be imported without writing sources.json")
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var locatorPage, locatorChapter, locatorTimestamp string
+
+func addLocatorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&locatorPage, "page", "", "Page or page range in the source (e.g. 42, 42-45)")
+	cmd.Flags().StringVar(&locatorChapter, "chapter", "", "Chapter in the source")
+	cmd.Flags().StringVar(&locatorTimestamp, "timestamp", "", "Position in a video (mm:ss or h:mm:ss)")
+}
+
+// locatorFromFlags - --page / --chapter / --timestamp からロケーターを組み立てる（いずれか 1 つ）
+func locatorFromFlags() (string, error) {
+	set := 0
+	for _, v := range []string{locatorPage, locatorChapter, locatorTimestamp} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return "", fmt.Errorf("❌ Specify only one of --page, --chapter or --timestamp")
+	}
+
+	switch {
+	case locatorPage != "":
+		locator, err := model.PageLocator(locatorPage)
+		if err != nil {
+			return "", fmt.Errorf("❌ %w", err)
+		}
+		return locator, nil
+	case locatorChapter != "":
+		return model.ChapterLocator(locatorChapter), nil
+	case locatorTimestamp != "":
+		locator, err := model.TimestampLocator(locatorTimestamp)
+		if err != nil {
+			return "", fmt.Errorf("❌ %w", err)
+		}
+		return locator, nil
+	}
+	return "", nil
+}
+
+var markdownNoise = regexp.MustCompile("(?m)^#+\\s.*$|<!--.*?-->|[*_`>]|\\[\\[|\\]\\]")
+
+// noteExcerpt - 本文から見出しと Markdown 記号を除いた冒頭 width 文字を返す
+func noteExcerpt(body string, width int) string {
+	excerpt := strings.Join(strings.Fields(markdownNoise.ReplaceAllString(body, " ")), " ")
+	if runes := []rune(excerpt); len(runes) > width {
+		return string(runes[:width]) + "…"
+	}
+	return excerpt
+}
+
+type sourceReadingNote struct {
+	Note    model.Note `json:"note"`
+	Locator string     `json:"locator"`
+	Excerpt string     `json:"excerpt"`
+}
+
+// sourceReadingNotes - ソースに紐づくノートをロケーター順に並べて抜粋を付ける
+func sourceReadingNotes(sourceID string, excerptWidth int, config model.Config) ([]sourceReadingNote, error) {
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	sourceNotes, _, err := store.LoadSourceNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+
+	notesByID := make(map[string]model.Note)
+	for _, note := range notes {
+		notesByID[note.ID] = note
+	}
+
+	var result []sourceReadingNote
+	for _, sn := range sourceNotes {
+		note, ok := notesByID[sn.NoteID]
+		if sn.SourceID != sourceID || !ok || note.Deleted {
+			continue
+		}
+
+		row := sourceReadingNote{Note: note, Locator: sn.Locator}
+		if excerptWidth > 0 {
+			body := note.Content
+			if content, err := os.ReadFile(noteFilePath(note, config)); err == nil {
+				if _, parsed, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content)); err == nil {
+					body = parsed
+				}
+			}
+			row.Excerpt = noteExcerpt(body, excerptWidth)
+		}
+		result = append(result, row)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Locator == result[j].Locator {
+			return result[i].Note.CreatedAt < result[j].Note.CreatedAt
+		}
+		return model.LocatorLess(result[i].Locator, result[j].Locator)
+	})
+	return result, nil
+}
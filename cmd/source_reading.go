@@ -0,0 +1,281 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// parseAuthorFlags - --author / --editor の値を Person に変換（"A, B; C, D" の形式も受け付ける）
+func parseAuthorFlags(values []string) []model.Person {
+	var people []model.Person
+	for _, value := range values {
+		people = append(people, model.ParsePeople(value)...)
+	}
+	return people
+}
+
+// authorSummary - 一覧表示用の著者表記（"Smith", "Smith & Doe", "Smith et al."）
+func authorSummary(people []model.Person) string {
+	switch len(people) {
+	case 0:
+		return ""
+	case 1:
+		return people[0].Surname()
+	case 2:
+		return people[0].Surname() + " & " + people[1].Surname()
+	}
+	return people[0].Surname() + " et al."
+}
+
+// ratingStars - 評価を ★★★☆☆ の形式で表示
+func ratingStars(rating int) string {
+	if rating <= 0 {
+		return ""
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+// setSourceStatus - 読書ステータスを変更し、未設定なら開始日・読了日を埋める
+func setSourceStatus(source *model.Source, status string, now time.Time) {
+	today := now.Format("2006-01-02")
+	source.Status = status
+	switch status {
+	case model.SourceStatusReading:
+		if source.StartedAt == "" {
+			source.StartedAt = today
+		}
+	case model.SourceStatusRead:
+		if source.StartedAt == "" {
+			source.StartedAt = today
+		}
+		if source.FinishedAt == "" {
+			source.FinishedAt = today
+		}
+	}
+}
+
+func addSourceReadingFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sourceStatus, "status", "", "Reading status (to-read, reading, read)")
+	cmd.Flags().IntVar(&sourceRating, "rating", 0, "Rating from 1 to 5 (0 to clear)")
+	cmd.Flags().StringVar(&sourceStarted, "started", "", "Date you started reading (YYYY-MM-DD, today, ...)")
+	cmd.Flags().StringVar(&sourceFinished, "finished", "", "Date you finished reading (YYYY-MM-DD, today, ...)")
+	cmd.Flags().StringSliceVarP(&sourceTags, "tag", "t", []string{}, "Tags for the source (replaces existing tags)")
+}
+
+// applySourceReadingFlags - 指定された読書管理フラグのみ反映
+func applySourceReadingFlags(cmd *cobra.Command, source *model.Source, now time.Time) error {
+	if cmd.Flags().Changed("started") {
+		date, err := util.ParseDate(sourceStarted)
+		if err != nil {
+			return fmt.Errorf("❌ Invalid --started date: %w", err)
+		}
+		source.StartedAt = date
+	}
+	if cmd.Flags().Changed("finished") {
+		date, err := util.ParseDate(sourceFinished)
+		if err != nil {
+			return fmt.Errorf("❌ Invalid --finished date: %w", err)
+		}
+		source.FinishedAt = date
+	}
+	if cmd.Flags().Changed("status") {
+		status := model.NormalizeSourceStatus(sourceStatus)
+		if status == "" {
+			return fmt.Errorf("❌ Invalid status: %s. Must be one of %s", sourceStatus, strings.Join(model.SourceStatuses, ", "))
+		}
+		setSourceStatus(source, status, now)
+	}
+	if cmd.Flags().Changed("rating") {
+		if sourceRating < 0 || sourceRating > 5 {
+			return fmt.Errorf("❌ Invalid rating: %d. Must be between 0 and 5", sourceRating)
+		}
+		source.Rating = sourceRating
+	}
+	if cmd.Flags().Changed("tag") {
+		source.Tags = nil
+		for _, tag := range sourceTags {
+			if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(source.Tags, tag) {
+				source.Tags = append(source.Tags, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// filterSources - source list のフィルタを適用
+func filterSources(sources []model.Source) ([]model.Source, error) {
+	status := ""
+	if sourceListStatus != "" {
+		if status = model.NormalizeSourceStatus(sourceListStatus); status == "" {
+			return nil, fmt.Errorf("❌ Invalid status: %s. Must be one of %s", sourceListStatus, strings.Join(model.SourceStatuses, ", "))
+		}
+	}
+
+	var filtered []model.Source
+	for _, source := range sources {
+		if status != "" && source.Status != status {
+			continue
+		}
+		if sourceListType != "" && !strings.EqualFold(source.SourceType, sourceListType) {
+			continue
+		}
+		if sourceListAuthor != "" && !strings.Contains(strings.ToLower(model.JoinPeople(source.Authors)), strings.ToLower(sourceListAuthor)) {
+			continue
+		}
+		if len(sourceListTags) > 0 && !util.HasTags(source.Tags, sourceListTags) {
+			continue
+		}
+		if source.Rating < sourceListMinRating {
+			continue
+		}
+		filtered = append(filtered, source)
+	}
+	return filtered, nil
+}
+
+func sourceStatusRank(status string) int {
+	for i, s := range model.SourceStatuses {
+		if s == status {
+			return i
+		}
+	}
+	return len(model.SourceStatuses)
+}
+
+// sortSources - `--sort` のキーでソース一覧を安定ソート。値が未設定のものは最後に並べる
+func sortSources(sources []model.Source, key string, reverse bool) error {
+	emptyLast := func(x, y string) (bool, bool) {
+		switch {
+		case x == y:
+			return false, false
+		case x == "":
+			return false, true
+		case y == "":
+			return true, true
+		}
+		return false, false
+	}
+
+	var less func(a, b model.Source) bool
+	switch key {
+	case "id", "":
+		less = func(a, b model.Source) bool { return a.SourceID < b.SourceID }
+	case "title":
+		less = func(a, b model.Source) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case "author":
+		less = func(a, b model.Source) bool {
+			x, y := strings.ToLower(model.JoinPeople(a.Authors)), strings.ToLower(model.JoinPeople(b.Authors))
+			if result, ok := emptyLast(x, y); ok {
+				return result
+			}
+			return x < y
+		}
+	case "year":
+		less = func(a, b model.Source) bool { return a.Year < b.Year }
+	case "rating":
+		less = func(a, b model.Source) bool { return a.Rating > b.Rating }
+	case "status":
+		less = func(a, b model.Source) bool { return sourceStatusRank(a.Status) < sourceStatusRank(b.Status) }
+	case "started", "finished", "added":
+		field := func(s model.Source) string {
+			switch key {
+			case "started":
+				return s.StartedAt
+			case "finished":
+				return s.FinishedAt
+			}
+			return s.CreatedAt
+		}
+		less = func(a, b model.Source) bool {
+			x, y := field(a), field(b)
+			if result, ok := emptyLast(x, y); ok {
+				return result
+			}
+			return x < y
+		}
+	default:
+		return fmt.Errorf("❌ Invalid sort key: %s. Must be id, title, author, year, rating, status, started, finished or added", key)
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		if reverse {
+			return less(sources[j], sources[i])
+		}
+		return less(sources[i], sources[j])
+	})
+	return nil
+}
+
+var sourceStatusCmd = &cobra.Command{
+	Use:   "status [sourceID] [status]",
+	Short: "Set reading status of a source (to-read, reading, read)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		status := model.NormalizeSourceStatus(args[1])
+		if status == "" {
+			log.Fatalf("❌ Invalid status: %s. Must be one of %s", args[1], strings.Join(model.SourceStatuses, ", "))
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Fatalf("❌ Error loading config: %v", err)
+		}
+
+		sources, sourcesJsonPath, err := store.LoadSources(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load sources.json: %v", err)
+		}
+
+		found := false
+		for i := range sources {
+			if sources[i].SourceID == args[0] {
+				found = true
+				setSourceStatus(&sources[i], status, time.Now())
+				break
+			}
+		}
+		if !found {
+			log.Fatalf("❌ Source ID '%s' not found", args[0])
+		}
+
+		if err := store.SaveUpdatedJson(sources, sourcesJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update sources.json: %v", err)
+		}
+		log.Printf("✅ Source '%s' marked as %s", args[0], status)
+	},
+}
+
+var sourceMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate sources.json to structured authors and reading status",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Fatalf("❌ Error loading config: %v", err)
+		}
+
+		migrated, err := store.MigrateSources(*config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Printf("✅ Migrated %d sources", migrated)
+	},
+}
+
+func init() {
+	sourceCmd.AddCommand(sourceStatusCmd)
+	sourceCmd.AddCommand(sourceMigrateCmd)
+}
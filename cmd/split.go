@@ -0,0 +1,352 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var splitHeadingLevel int
+var splitLines string
+
+type noteSection struct {
+	Title string
+	Body  string
+}
+
+// nextFreeNoteID - 既存ノートと重複しない yyyymmddhhmmss 形式の ID を生成
+func nextFreeNoteID(notes []model.Note, config model.Config) string {
+	used := make(map[string]bool)
+	for _, note := range notes {
+		used[note.ID] = true
+	}
+
+	t := time.Now()
+	for {
+		id := t.Format("20060102150405")
+		if _, err := os.Stat(filepath.Join(config.ZettelDir, id+".md")); !used[id] && os.IsNotExist(err) {
+			return id
+		}
+		t = t.Add(time.Second)
+	}
+}
+
+// createDerivedNote - 既存ノートから派生したノートを作成（タグ・プロジェクト・ソースを引き継ぐ）
+func createDerivedNote(original model.Note, originalFrontMatter model.NoteFrontMatter, section noteSection, config model.Config) (model.Note, error) {
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	noteID := nextFreeNoteID(notes, config)
+	createdAt := time.Now().Format("2006-01-02 15:04:05")
+
+	frontMatter := model.NoteFrontMatter{
+		ID:        noteID,
+		Title:     section.Title,
+		NoteType:  original.NoteType,
+		Tags:      originalFrontMatter.Tags,
+		Links:     []string{original.ID},
+		Projects:  originalFrontMatter.Projects,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	frontMatterBytes, err := yaml.Marshal(frontMatter)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("failed to convert to YAML: %w", err)
+	}
+	content := fmt.Sprintf("---\n%s---\n\n%s", string(frontMatterBytes), section.Body)
+
+	filePath := filepath.Join(config.ZettelDir, noteID+".md")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return model.Note{}, fmt.Errorf("failed to create note file (%s): %w", filePath, err)
+	}
+
+	note := model.Note{
+		ID:        noteID,
+		Title:     section.Title,
+		NoteType:  original.NoteType,
+		Projects:  original.Projects,
+		Content:   section.Body,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	if err := store.InsertNoteToJson(note, config); err != nil {
+		return model.Note{}, fmt.Errorf("failed to write to JSON file: %w", err)
+	}
+
+	if err := syncNoteRelations(noteID, "", content, config); err != nil {
+		return model.Note{}, err
+	}
+
+	// プロジェクト・ソースへの所属を引き継ぐ
+	projectNotes, _, err := store.LoadProjectNotes(config)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+	for _, pn := range projectNotes {
+		if pn.NoteID == original.ID {
+			if err := store.InsertProjectNoteToJson(model.ProjectNote{ProjectID: pn.ProjectID, NoteID: noteID}, config); err != nil {
+				return model.Note{}, err
+			}
+		}
+	}
+
+	sourceNotes, _, err := store.LoadSourceNotes(config)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+	for _, sn := range sourceNotes {
+		if sn.NoteID == original.ID {
+			if err := store.InsertSourceNoteToJson(model.SourceNote{SourceID: sn.SourceID, NoteID: noteID}, config); err != nil {
+				return model.Note{}, err
+			}
+		}
+	}
+
+	// SeqID を取得するために再読み込み
+	notes, _, err = store.LoadNotes(config)
+	if err != nil {
+		return model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	for _, n := range notes {
+		if n.ID == noteID {
+			return n, nil
+		}
+	}
+	return note, nil
+}
+
+// splitByHeading - 指定レベルの見出しごとに本文を分割。最初の見出しより前の部分は残りとして返す
+func splitByHeading(body string, level int) (string, []noteSection) {
+	prefix := strings.Repeat("#", level) + " "
+	lines := strings.Split(body, "\n")
+
+	var rest []string
+	var sections []noteSection
+	var current *noteSection
+	var currentLines []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(strings.Join(currentLines, "\n"))
+			sections = append(sections, *current)
+		}
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			flush()
+			current = &noteSection{Title: strings.TrimSpace(strings.TrimPrefix(line, prefix))}
+			currentLines = []string{line}
+			continue
+		}
+		if current == nil {
+			rest = append(rest, line)
+		} else {
+			currentLines = append(currentLines, line)
+		}
+	}
+	flush()
+
+	return strings.TrimSpace(strings.Join(rest, "\n")), sections
+}
+
+// splitByRanges - "1-5,8-12" 形式の行範囲（本文の 1 始まり）ごとに分割
+func splitByRanges(body, ranges, baseTitle string) (string, []noteSection, error) {
+	lines := strings.Split(body, "\n")
+	taken := make([]bool, len(lines))
+
+	var sections []noteSection
+	for i, r := range strings.Split(ranges, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(r), "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return "", nil, fmt.Errorf("❌ Invalid line range: %s", r)
+		}
+		end := start
+		if len(bounds) == 2 {
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return "", nil, fmt.Errorf("❌ Invalid line range: %s", r)
+			}
+		}
+		if start < 1 || end < start || end > len(lines) {
+			return "", nil, fmt.Errorf("❌ Line range %s is out of bounds (1-%d)", r, len(lines))
+		}
+
+		var sectionLines []string
+		for j := start - 1; j < end; j++ {
+			if taken[j] {
+				return "", nil, fmt.Errorf("❌ Line range %s overlaps another range", r)
+			}
+			taken[j] = true
+			sectionLines = append(sectionLines, lines[j])
+		}
+
+		// タイトルは範囲内の最初の空でない行から取る
+		title := fmt.Sprintf("%s (part %d)", baseTitle, i+1)
+		for _, line := range sectionLines {
+			if trimmed := strings.TrimSpace(strings.TrimLeft(line, "# ")); trimmed != "" {
+				title = trimmed
+				break
+			}
+		}
+
+		sections = append(sections, noteSection{Title: title, Body: strings.TrimSpace(strings.Join(sectionLines, "\n"))})
+	}
+
+	var rest []string
+	for i, line := range lines {
+		if !taken[i] {
+			rest = append(rest, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(rest, "\n")), sections, nil
+}
+
+func splitNote(noteSeqID string, config model.Config) ([]model.Note, error) {
+	notes, notesJsonPath, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	var original model.Note
+	found := false
+	for _, note := range notes {
+		if note.SeqID == noteSeqID {
+			original = note
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("❌ Note with ID %s not found", noteSeqID)
+	}
+	// 分割先は tasks.json に行を持たないため、タスクノートは分割しない
+	if original.NoteType == "task" {
+		return nil, fmt.Errorf("❌ Task notes cannot be split")
+	}
+
+	notePath := filepath.Join(config.ZettelDir, original.ID+".md")
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+	frontMatter, body, err := store.ParseFrontMatter[model.NoteFrontMatter](string(content))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	var rest string
+	var sections []noteSection
+	if splitLines != "" {
+		rest, sections, err = splitByRanges(body, splitLines, original.Title)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		rest, sections = splitByHeading(body, splitHeadingLevel)
+	}
+
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("❌ Nothing to split: no level-%d headings found", splitHeadingLevel)
+	}
+
+	if err := store.BackupNote(notePath, config.Backup.BackupDir); err != nil {
+		log.Printf("⚠️ Backup failed: %v", err)
+	}
+
+	var created []model.Note
+	var linkLines []string
+	for _, section := range sections {
+		note, err := createDerivedNote(original, frontMatter, section, config)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, note)
+		linkLines = append(linkLines, fmt.Sprintf("- [%s](%s.md)", note.Title, note.ID))
+	}
+
+	// 元ノートには残りの本文と分割先へのリンクを残す
+	updatedBody := strings.TrimSpace(rest + "\n\n" + strings.Join(linkLines, "\n"))
+	updatedAt := time.Now().Format("2006-01-02 15:04:05")
+	updatedContent, _, err := rewriteNoteContent(string(content), original.NoteType, func(fm noteFrontMatterFields, body *string) bool {
+		*body = updatedBody
+		*fm.UpdatedAt = updatedAt
+		return true
+	})
+	if err != nil {
+		return created, err
+	}
+	if err := os.WriteFile(notePath, []byte(updatedContent), 0644); err != nil {
+		return created, fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+
+	if err := syncNoteRelations(original.ID, string(content), updatedContent, config); err != nil {
+		return created, err
+	}
+
+	notes, notesJsonPath, err = store.LoadNotes(config)
+	if err != nil {
+		return created, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	for i := range notes {
+		if notes[i].ID == original.ID {
+			notes[i].Content = updatedBody
+			notes[i].UpdatedAt = updatedAt
+			break
+		}
+	}
+	if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+		return created, fmt.Errorf("❌ Failed to update notes.json: %w", err)
+	}
+
+	return created, nil
+}
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split [noteID]",
+	Short: "Split a note into several notes by heading or line ranges",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		noteID := args[0]
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		created, err := splitNote(noteID, *config)
+		for _, note := range created {
+			fmt.Printf("✅ Created %s: %s\n", note.SeqID, note.Title)
+		}
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Printf("✅ Note %s split into %d notes\n", noteID, len(created))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	splitCmd.Flags().IntVar(&splitHeadingLevel, "level", 2, "Heading level to split on")
+	splitCmd.Flags().StringVar(&splitLines, "lines", "", "Split by body line ranges instead of headings (e.g. 3-10,12-20)")
+}
@@ -13,12 +13,12 @@ import (
 
 var syncCmd = &cobra.Command{
 	Use:   "sync",
-	Short: "Synchronize local files with S3",
+	Short: "Synchronize local files with S3, MinIO or a shared directory",
 }
 
 var syncPushCmd = &cobra.Command{
 	Use:   "push",
-	Short: "Upload local changes to S3",
+	Short: "Upload local changes to the sync target",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Println("🔄 Running `ztl sync push`...") // デバッグログ
 		config, err := store.LoadConfig()
@@ -27,7 +27,7 @@ var syncPushCmd = &cobra.Command{
 			return fmt.Errorf("❌ Error loading config: %w", err)
 		}
 
-		err = SyncWithS3(*config, "push")
+		err = SyncWithRemote(*config, "push")
 		if err != nil {
 			log.Printf("❌ Sync failed: %v", err)
 			return fmt.Errorf("❌ Sync failed: %w", err)
@@ -40,7 +40,7 @@ var syncPushCmd = &cobra.Command{
 
 var syncPullCmd = &cobra.Command{
 	Use:   "pull",
-	Short: "Download latest changes from S3",
+	Short: "Download latest changes from the sync target",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		log.Println("🔄 Running `ztl sync pull`...") // デバッグログ
 		config, err := store.LoadConfig()
@@ -49,7 +49,7 @@ var syncPullCmd = &cobra.Command{
 			return fmt.Errorf("❌ Error loading config: %w", err)
 		}
 
-		err = SyncWithS3(*config, "pull")
+		err = SyncWithRemote(*config, "pull")
 		if err != nil {
 			log.Printf("❌ Sync failed: %v", err)
 			return fmt.Errorf("❌ Sync failed: %w", err)
@@ -62,7 +62,7 @@ var syncPullCmd = &cobra.Command{
 
 var syncStatusCmd = &cobra.Command{
 	Use:   "status",
-	Short: "Show differences between local and S3 files",
+	Short: "Show differences between local and remote files",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := store.LoadConfig()
 		if err != nil {
@@ -73,7 +73,30 @@ var syncStatusCmd = &cobra.Command{
 	},
 }
 
+var syncRekeyOldKeyFile string
+
+var syncRekeyCmd = &cobra.Command{
+	Use:   "rekey",
+	Short: "Re-encrypt all files on the sync target with a new passphrase or key file",
+	Long: `Re-encrypt all files on the sync target with a new key.
+
+The new key is sync.key_file, or a new passphrase (ZTL_SYNC_PASSPHRASE or prompted).
+The current key is --old-key-file, or the current passphrase (ZTL_SYNC_OLD_PASSPHRASE or prompted).
+Running it against an unencrypted target encrypts the existing files.
+If it is interrupted, run it again to resume.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v", err)
+			return fmt.Errorf("❌ Error loading config: %w", err)
+		}
+
+		return RekeySyncTarget(*config, syncRekeyOldKeyFile)
+	},
+}
+
 func init() {
-	syncCmd.AddCommand(syncPushCmd, syncPullCmd, syncStatusCmd)
+	syncRekeyCmd.Flags().StringVar(&syncRekeyOldKeyFile, "old-key-file", "", "Key file the sync target is currently encrypted with")
+	syncCmd.AddCommand(syncPushCmd, syncPullCmd, syncStatusCmd, syncRekeyCmd)
 	rootCmd.AddCommand(syncCmd)
 }
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"golang.org/x/term"
+)
+
+// 対話的に入力できない環境（cron など）ではパスフレーズを環境変数で渡す
+const (
+	syncPassphraseEnv    = "ZTL_SYNC_PASSPHRASE"
+	syncOldPassphraseEnv = "ZTL_SYNC_OLD_PASSPHRASE"
+)
+
+// readPassphrase - 端末からエコーなしでパスフレーズを読む
+func readPassphrase(prompt string, confirm bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("❌ A passphrase is required. Set %s or sync.key_file", syncPassphraseEnv)
+	}
+
+	read := func(prompt string) (string, error) {
+		fmt.Fprintf(os.Stderr, "%s: ", prompt)
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("❌ Failed to read passphrase: %w", err)
+		}
+		return string(data), nil
+	}
+
+	passphrase, err := read(prompt)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("❌ Passphrase must not be empty")
+	}
+	if confirm {
+		again, err := read("Confirm passphrase")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("❌ Passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// syncKeySource - params の導出方法に合わせて鍵ファイルかパスフレーズを用意する（params が nil なら新しい鍵）
+func syncKeySource(params *util.EncryptionParams, keyFile, env, prompt string) (util.KeySource, error) {
+	if (params != nil && params.KDF == util.KDFKeyFile) || (params == nil && keyFile != "") {
+		return util.KeySource{KeyFile: keyFile}, nil
+	}
+	if passphrase := os.Getenv(env); passphrase != "" {
+		return util.KeySource{Passphrase: passphrase}, nil
+	}
+	passphrase, err := readPassphrase(prompt, params == nil)
+	if err != nil {
+		return util.KeySource{}, err
+	}
+	return util.KeySource{Passphrase: passphrase}, nil
+}
+
+// checkEncryptionConfig - sync.encryption の値を確認する
+func checkEncryptionConfig(config model.Config) error {
+	switch strings.ToLower(config.Sync.Encryption) {
+	case "", "aes-gcm", util.CipherAESGCM:
+		return nil
+	}
+	return fmt.Errorf("❌ Unknown sync.encryption: %s. Must be aes-gcm", config.Sync.Encryption)
+}
+
+// remoteHasFiles - 同期先にファイルがあるか
+func remoteHasFiles(ctx context.Context, backend util.SyncBackend, config model.Config) (bool, error) {
+	for _, area := range allSyncAreas(config) {
+		keys, err := backend.List(ctx, area.Prefix+"/")
+		if err != nil {
+			return false, err
+		}
+		if len(keys) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// openSyncBackend - 同期先を開く。sync.encryption が設定されていれば暗号化する同期先を返す
+func openSyncBackend(ctx context.Context, config model.Config) (util.SyncBackend, error) {
+	if err := checkEncryptionConfig(config); err != nil {
+		return nil, err
+	}
+	backend, err := util.NewSyncBackend(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to initialize sync backend: %w", err)
+	}
+	params, err := util.LoadEncryptionParams(ctx, backend)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to download %s: %w", util.EncryptionParamsName, err)
+	}
+
+	switch {
+	case config.Sync.Encryption == "" && params == nil:
+		return backend, nil
+	case config.Sync.Encryption == "":
+		return nil, fmt.Errorf("❌ %s is encrypted. Set sync.encryption: aes-gcm (and sync.key_file if it uses a key file)", backend.Name())
+	case params != nil && params.Rekeying != nil:
+		return nil, fmt.Errorf("❌ Re-encryption of %s is incomplete. Run `ztl sync rekey` again", backend.Name())
+	case params != nil:
+		src, err := syncKeySource(params, config.Sync.KeyFile, syncPassphraseEnv, "Sync passphrase")
+		if err != nil {
+			return nil, err
+		}
+		c, err := util.OpenEncryptionParams(*params, src)
+		if err != nil {
+			return nil, err
+		}
+		return util.NewEncryptedBackend(backend, c), nil
+	}
+
+	// 初めて暗号化する。既存のファイルは rekey で暗号化してもらう
+	hasFiles, err := remoteHasFiles(ctx, backend, config)
+	if err != nil {
+		return nil, err
+	}
+	if hasFiles {
+		return nil, fmt.Errorf("❌ %s has unencrypted files. Run `ztl sync rekey` to encrypt them", backend.Name())
+	}
+	src, err := syncKeySource(nil, config.Sync.KeyFile, syncPassphraseEnv, "New sync passphrase")
+	if err != nil {
+		return nil, err
+	}
+	newParams, c, err := util.NewEncryptionParams(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := util.SaveEncryptionParams(ctx, backend, newParams); err != nil {
+		return nil, fmt.Errorf("❌ Failed to upload %s: %w", util.EncryptionParamsName, err)
+	}
+	log.Printf("🔒 Enabled encryption on %s", backend.Name())
+	return util.NewEncryptedBackend(backend, c), nil
+}
+
+// RekeySyncTarget - 同期先の全てのファイルを新しい鍵（config の鍵ファイルか新しいパスフレーズ）で暗号化し直す。
+// 暗号化していない同期先なら暗号化する。途中で中断しても、もう一度実行すれば続きから再開する
+func RekeySyncTarget(config model.Config, oldKeyFile string) error {
+	if config.Sync.Encryption == "" {
+		return fmt.Errorf("❌ sync.encryption is not set")
+	}
+	if err := checkEncryptionConfig(config); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	backend, err := util.NewSyncBackend(ctx, config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to initialize sync backend: %w", err)
+	}
+	params, err := util.LoadEncryptionParams(ctx, backend)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to download %s: %w", util.EncryptionParamsName, err)
+	}
+
+	// 移行元の鍵
+	oldParams := util.EncryptionParams{Cipher: util.CipherNone}
+	switch {
+	case params != nil && params.Rekeying != nil:
+		oldParams = *params.Rekeying
+		log.Printf("🔄 Resuming re-encryption of %s...", backend.Name())
+	case params != nil:
+		oldParams = *params
+	}
+	if oldKeyFile == "" {
+		oldKeyFile = config.Sync.KeyFile
+	}
+	oldSrc := util.KeySource{}
+	if oldParams.Cipher != util.CipherNone {
+		if oldSrc, err = syncKeySource(&oldParams, oldKeyFile, syncOldPassphraseEnv, "Current sync passphrase"); err != nil {
+			return err
+		}
+	}
+	oldCipher, err := util.OpenEncryptionParams(oldParams, oldSrc)
+	if err != nil {
+		return err
+	}
+
+	// 新しい鍵。再開時は前回作った鍵をそのまま使う
+	var newParams util.EncryptionParams
+	var newCipher *util.SyncCipher
+	if params != nil && params.Rekeying != nil {
+		newParams = *params
+		newParams.Rekeying = nil
+		src, err := syncKeySource(&newParams, config.Sync.KeyFile, syncPassphraseEnv, "New sync passphrase")
+		if err != nil {
+			return err
+		}
+		if newCipher, err = util.OpenEncryptionParams(newParams, src); err != nil {
+			return err
+		}
+	} else {
+		src, err := syncKeySource(nil, config.Sync.KeyFile, syncPassphraseEnv, "New sync passphrase")
+		if err != nil {
+			return err
+		}
+		if newParams, newCipher, err = util.NewEncryptionParams(src); err != nil {
+			return err
+		}
+	}
+
+	// 他の端末が途中で同期しないよう、移行中であることを先に記録する
+	pending := newParams
+	pending.Rekeying = &oldParams
+	if err := util.SaveEncryptionParams(ctx, backend, pending); err != nil {
+		return fmt.Errorf("❌ Failed to upload %s: %w", util.EncryptionParamsName, err)
+	}
+
+	count := 0
+	for _, area := range allSyncAreas(config) {
+		keys, err := backend.List(ctx, area.Prefix+"/")
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			data, err := backend.Get(ctx, key)
+			if err != nil {
+				return fmt.Errorf("❌ Failed to download %s: %w", key, err)
+			}
+			if newCipher.HasKeyID(data) {
+				continue // 中断前に暗号化し直したもの
+			}
+			plain, err := oldCipher.Open(key, data)
+			if err != nil {
+				return err
+			}
+			sealed, err := newCipher.Seal(key, plain)
+			if err != nil {
+				return err
+			}
+			if err := backend.Put(ctx, key, sealed); err != nil {
+				return fmt.Errorf("❌ Failed to upload %s: %w", key, err)
+			}
+			count++
+		}
+	}
+
+	if err := util.SaveEncryptionParams(ctx, backend, newParams); err != nil {
+		return fmt.Errorf("❌ Failed to upload %s: %w", util.EncryptionParamsName, err)
+	}
+	log.Printf("✅ Re-encrypted %d files on %s", count, backend.Name())
+	return nil
+}
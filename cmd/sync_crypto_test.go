@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+)
+
+func TestRekeySyncTargetResumes(t *testing.T) {
+	m := newTestMachine(t)
+	keyDir := t.TempDir()
+	oldKey := filepath.Join(keyDir, "old.key")
+	newKey := filepath.Join(keyDir, "new.key")
+	for path, content := range map[string]string{oldKey: "old secret", newKey: "new secret"} {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.config.Sync.Platform = "local"
+	m.config.Sync.Path = t.TempDir()
+	m.config.Sync.Encryption = "aes-gcm"
+	m.config.Sync.KeyFile = newKey
+
+	oldParams, oldCipher, err := util.NewEncryptionParams(util.KeySource{KeyFile: oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newParams, newCipher, err := util.NewEncryptionParams(util.KeySource{KeyFile: newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// notes/a.md だけ新しい鍵で暗号化し直したところで中断した状態
+	ctx := context.Background()
+	backend := util.NewLocalBackend(m.config.Sync.Path)
+	files := map[string]string{
+		"notes/a.md":      "alpha\n",
+		"notes/b.md":      "beta\n",
+		"json/notes.json": "[]",
+	}
+	for key, content := range files {
+		c := oldCipher
+		if key == "notes/a.md" {
+			c = newCipher
+		}
+		sealed, err := c.Seal(key, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := backend.Put(ctx, key, sealed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pending := newParams
+	pending.Rekeying = &oldParams
+	if err := util.SaveEncryptionParams(ctx, backend, pending); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := openSyncBackend(ctx, m.config); err == nil {
+		t.Fatal("openSyncBackend() opened a target whose re-encryption is incomplete")
+	}
+
+	if err := RekeySyncTarget(m.config, oldKey); err != nil {
+		t.Fatalf("RekeySyncTarget() error: %v", err)
+	}
+
+	params, err := util.LoadEncryptionParams(ctx, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params == nil || params.Rekeying != nil || params.KeyID != newParams.KeyID {
+		t.Fatalf("encryption params after rekey = %+v, want the new key without rekeying", params)
+	}
+
+	opened, err := openSyncBackend(ctx, m.config)
+	if err != nil {
+		t.Fatalf("openSyncBackend() error: %v", err)
+	}
+	for key, want := range files {
+		got, err := opened.Get(ctx, key)
+		if err != nil {
+			t.Errorf("Get(%s) error: %v", key, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("Get(%s) = %q, want %q", key, got, want)
+		}
+	}
+}
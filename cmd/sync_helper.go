@@ -1,151 +1,739 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
 	"github.com/nakachan-ing/ztl-cli/internal/util"
 )
 
-// SyncWithS3 - S3 との同期処理
-func SyncWithS3(config model.Config, direction string) error {
-	s3Client, err := util.NewS3Client(config)
+// syncArea - 同期するローカルのディレクトリと、同期先のキーの接頭辞
+type syncArea struct {
+	Prefix string
+	Dir    string
+}
+
+// allSyncAreas - 同期先で使う全ての接頭辞（ディレクトリが未設定のものを含む）
+func allSyncAreas(config model.Config) []syncArea {
+	return []syncArea{
+		{Prefix: "notes", Dir: config.ZettelDir},
+		{Prefix: "json", Dir: config.JsonDataDir},
+		{Prefix: "archive", Dir: config.ArchiveDir},
+		{Prefix: "trash", Dir: config.Trash.TrashDir},
+	}
+}
+
+// syncAreas - アーカイブ・ゴミ箱も同期し、ディレクトリ間の移動（アーカイブ・削除・復元）を他の端末に反映する
+func syncAreas(config model.Config) []syncArea {
+	var areas []syncArea
+	for _, area := range allSyncAreas(config) {
+		if area.Dir != "" {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+// syncEntry - 1 ファイルについてのローカル・同期先・前回同期時の状態
+type syncEntry struct {
+	Key          string // notes/xxx.md
+	Area         syncArea
+	Name         string // 同期するディレクトリからの相対パス（"/" 区切り）
+	LocalPath    string
+	LocalExists  bool
+	LocalHash    string
+	RemoteListed bool // manifest に載っている（tombstone を含む）
+	Remote       util.ManifestEntry
+	BaseExists   bool
+	Base         model.SyncFileState // Hash が空なら前回の同期時点で削除済み
+}
+
+// localDeleted - 前回の同期後にローカルで削除（移動）されたか
+func (e syncEntry) localDeleted() bool {
+	return !e.LocalExists && e.BaseExists && e.Base.Hash != ""
+}
+
+// localChanged - 前回の同期からローカルで変更・作成・削除されたか
+func (e syncEntry) localChanged() bool {
+	if e.LocalExists {
+		return !e.BaseExists || e.LocalHash != e.Base.Hash
+	}
+	return e.localDeleted()
+}
+
+// remoteDeleted - 同期先で削除されているか（tombstone）
+func (e syncEntry) remoteDeleted() bool {
+	return e.RemoteListed && e.Remote.Deleted
+}
+
+// remoteChanged - 前回の同期から同期先で変更・作成・削除されたか（内容のハッシュで比較する）
+func (e syncEntry) remoteChanged() bool {
+	switch {
+	case !e.RemoteListed:
+		return false
+	case !e.BaseExists:
+		return true
+	case e.Remote.Deleted:
+		return e.Base.Hash != ""
+	case e.Remote.Hash != "":
+		return e.Remote.Hash != e.Base.Hash
+	}
+	return e.Remote.Version != e.Base.Version // ハッシュのない旧形式の manifest
+}
+
+// unseenTombstone - 一度も同期していないファイルの削除。ローカルには何もしなくてよい
+func (e syncEntry) unseenTombstone() bool {
+	return e.remoteDeleted() && !e.LocalExists && !e.localDeleted()
+}
+
+// syncSession - 1 回の push / pull / status の処理
+type syncSession struct {
+	ctx       context.Context
+	backend   util.SyncBackend
+	config    model.Config
+	state     model.SyncState
+	statePath string
+	stateDir  string
+	manifests map[string]map[string]util.ManifestEntry // 接頭辞 → manifest
+	filter    *util.SyncFilter
+	entries   []syncEntry
+	now       time.Time
+
+	// push / pull の並列処理で共有する状態（state・manifests・changed は mu で保護する）
+	mu         sync.Mutex
+	changed    map[string]bool // アップロードが必要な manifest の接頭辞
+	version    string          // push でアップロードしたファイルのバージョン
+	unresolved map[string]bool // 競合コピーが残っているキー
+	progress   *syncProgress
+}
+
+func newSyncSession(ctx context.Context, config model.Config) (*syncSession, error) {
+	backend, err := openSyncBackend(ctx, config)
 	if err != nil {
-		return fmt.Errorf("❌ Failed to initialize S3 client: %w", err)
+		return nil, err
 	}
+	return newSyncSessionWithBackend(ctx, config, backend)
+}
 
-	if direction == "pull" {
-		// S3 から `metadata.json` を取得
-		remoteMetadataNotes, err := util.DownloadMetadataFromS3(s3Client, config, "notes")
+// newSyncSessionWithBackend - 開いた同期先との差分を調べる（テストではメモリ上の同期先を渡す）
+func newSyncSessionWithBackend(ctx context.Context, config model.Config, backend util.SyncBackend) (*syncSession, error) {
+	filter, err := util.NewSyncFilter(config.Sync.Include, config.Sync.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	state, statePath, err := store.LoadSyncState(backend.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	s := &syncSession{
+		ctx:       ctx,
+		backend:   backend,
+		config:    config,
+		state:     state,
+		statePath: statePath,
+		stateDir:  filepath.Dir(statePath),
+		manifests: make(map[string]map[string]util.ManifestEntry),
+		filter:    filter,
+		changed:   make(map[string]bool),
+		now:       time.Now(),
+	}
+	s.pruneResolvedConflicts()
+	if err := s.scan(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// scan - ローカルのファイル、同期先の manifest、前回の同期状態を突き合わせる。
+// サブディレクトリも対象にし、sync.include / sync.exclude に一致しないファイルは扱わない
+func (s *syncSession) scan() error {
+	areas := syncAreas(s.config)
+	for _, area := range areas {
+		manifest, err := util.LoadManifest(s.ctx, s.backend, path.Join(area.Prefix, util.ManifestName))
 		if err != nil {
-			return fmt.Errorf("❌ Failed to download metadata.json for notes: %w", err)
+			return fmt.Errorf("❌ Failed to download %s manifest: %w", area.Prefix, err)
+		}
+		s.manifests[area.Prefix] = manifest
+
+		// ゴミ箱などを同期するディレクトリの中に置いている場合は、それぞれの接頭辞でだけ同期する
+		var skipDirs []string
+		for _, other := range areas {
+			if other.Dir != area.Dir {
+				skipDirs = append(skipDirs, other.Dir)
+			}
 		}
-		remoteMetadataJson, err := util.DownloadMetadataFromS3(s3Client, config, "json")
+		names, err := util.ListSyncFiles(area.Dir, skipDirs)
 		if err != nil {
-			return fmt.Errorf("❌ Failed to download metadata.json for json: %w", err)
+			return err
 		}
 
-		// ローカルの `metadata.json` をロード
-		localMetadataNotes, _ := util.LoadMetadata(filepath.Join(config.ZettelDir, "metadata.json"))
-		localMetadataJson, _ := util.LoadMetadata(filepath.Join(config.JsonDataDir, "metadata.json"))
+		seen := make(map[string]bool)
+		add := func(name string) {
+			key := path.Join(area.Prefix, name)
+			if seen[name] || !s.filter.Match(key) {
+				return
+			}
+			seen[name] = true
+			e := syncEntry{Key: key, Area: area, Name: name, LocalPath: filepath.Join(area.Dir, filepath.FromSlash(name))}
+			e.Remote, e.RemoteListed = manifest[name]
+			s.entries = append(s.entries, e)
+		}
+		for _, name := range names {
+			add(name)
+		}
+		for name := range manifest {
+			add(name)
+		}
+	}
 
-		// `notes/` の変更を取得
-		notesDiff := util.DetectChanges(localMetadataNotes, remoteMetadataNotes, "s3")
+	if err := s.refreshEntries(); err != nil {
+		return err
+	}
+	sort.Slice(s.entries, func(i, j int) bool { return s.entries[i].Key < s.entries[j].Key })
+	return nil
+}
 
-		// `json/` の変更を取得
-		jsonDiff := util.DetectChanges(localMetadataJson, remoteMetadataJson, "s3")
+// refreshEntries - ローカルのファイルの有無・ハッシュと前回同期時の状態を読み直す
+func (s *syncSession) refreshEntries() error {
+	for i := range s.entries {
+		e := &s.entries[i]
+		e.Base, e.BaseExists = s.state.Files[e.Key]
+		e.LocalExists, e.LocalHash = false, ""
+		data, err := os.ReadFile(e.LocalPath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("❌ Failed to read %s: %w", e.LocalPath, err)
+		}
+		e.LocalExists = true
+		e.LocalHash = util.HashBytes(data)
+	}
+	return nil
+}
 
-		// 変更があるファイルのみ取得
-		fileList := append(notesDiff, jsonDiff...)
-		if len(fileList) == 0 {
-			log.Println("✅ No changes detected. Everything is up-to-date.")
-			return nil
+// pruneResolvedConflicts - 競合コピーが削除された競合を解決済みとして外す
+func (s *syncSession) pruneResolvedConflicts() {
+	var remaining []model.SyncConflict
+	for _, c := range s.state.Conflicts {
+		if _, err := os.Stat(c.Copy); err == nil {
+			remaining = append(remaining, c)
 		}
+	}
+	s.state.Conflicts = remaining
+}
 
-		log.Println("🔄 Syncing files from S3...")
-		err = util.SyncFilesToS3(config, "pull", fileList)
-		if err != nil {
-			return fmt.Errorf("❌ Sync failed: %w", err)
+// basePath - マージ用に保存する前回同期時の内容（Markdown と json/ の表のみ）
+func (s *syncSession) basePath(key string) string {
+	return filepath.Join(s.stateDir, "base", filepath.FromSlash(key))
+}
+
+// recordDeleted - 前回同期時の状態を「削除済み」にする
+func (s *syncSession) recordDeleted(e syncEntry, version string) error {
+	s.mu.Lock()
+	s.state.Files[e.Key] = model.SyncFileState{Version: version}
+	s.mu.Unlock()
+	if err := os.Remove(s.basePath(e.Key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("❌ Failed to remove sync base of %s: %w", e.Key, err)
+	}
+	return nil
+}
+
+// recordBase - 前回同期時の状態を更新する
+func (s *syncSession) recordBase(e syncEntry, data []byte, version string) error {
+	if filepath.Ext(e.Name) == ".md" || (e.Area.Prefix == "json" && syncTableMergers[e.Name] != nil) {
+		basePath := s.basePath(e.Key)
+		if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+			return fmt.Errorf("❌ Failed to create sync state directory: %w", err)
 		}
+		if err := os.WriteFile(basePath, data, 0644); err != nil {
+			return fmt.Errorf("❌ Failed to save sync base of %s: %w", e.Key, err)
+		}
+	}
+	s.mu.Lock()
+	s.state.Files[e.Key] = model.SyncFileState{Hash: util.HashBytes(data), Version: version}
+	s.mu.Unlock()
+	return nil
+}
+
+// setManifest - 同期先の manifest を更新する（push の最後とチェックポイントでアップロードする）
+func (s *syncSession) setManifest(e syncEntry, entry util.ManifestEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.manifests[e.Area.Prefix][e.Name] = entry
+	s.changed[e.Area.Prefix] = true
+}
 
-		log.Println("✅ Sync completed successfully.")
-		return nil
+// writeLocalFile - 一時ファイルに書いてから置き換え、中断しても書きかけのファイルを残さない
+func writeLocalFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("❌ Failed to create directory %s: %w", filepath.Dir(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ztl-sync-*")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to write file %s: %w", path, err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("❌ Failed to write file %s: %w", path, err)
+	}
+	return nil
+}
 
-	} else if direction == "push" {
-		// ローカルの `metadata.json` を生成
-		localMetadataNotes, err := util.GenerateMetadata(config.ZettelDir)
-		if err != nil {
-			return fmt.Errorf("❌ Failed to generate metadata.json for notes: %w", err)
+// resolveConcurrent - 両方で変更されたファイルを解決する。
+// 内容が同じなら何もせず、Markdown は三方向マージを試み、できなければ同期先の内容を競合コピーとして保存する。
+// json/ の表は先に mergeTables でレコード単位にマージし、マージできなかったものだけここに来る。
+// ローカルのファイルはマージ結果かローカルの内容のまま残る
+func (s *syncSession) resolveConcurrent(e syncEntry, remoteData []byte) (string, []byte, error) {
+	localData, err := os.ReadFile(e.LocalPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("❌ Failed to read %s: %w", e.LocalPath, err)
+	}
+	if util.HashBytes(localData) == util.HashBytes(remoteData) {
+		return "same", localData, nil
+	}
+
+	if filepath.Ext(e.Name) == ".md" {
+		base := ""
+		if data, err := os.ReadFile(s.basePath(e.Key)); err == nil {
+			base = string(data)
 		}
-		localMetadataJson, err := util.GenerateMetadata(config.JsonDataDir)
-		if err != nil {
-			return fmt.Errorf("❌ Failed to generate metadata.json for json: %w", err)
+		if merged, ok := util.Merge3(base, string(localData), string(remoteData)); ok {
+			if err := writeLocalFile(e.LocalPath, []byte(merged)); err != nil {
+				return "", nil, err
+			}
+			s.progress.logf("🔀 Merged: %s", e.Key)
+			return "merged", []byte(merged), nil
 		}
+	}
 
-		// `metadata.json` をローカルに保存
-		err = util.SaveMetadata(filepath.Join(config.ZettelDir, "metadata.json"), localMetadataNotes)
-		if err != nil {
-			return fmt.Errorf("❌ Failed to save metadata.json for notes: %w", err)
+	copyPath := util.ConflictCopyPath(e.LocalPath, s.now)
+	if err := writeLocalFile(copyPath, remoteData); err != nil {
+		return "", nil, err
+	}
+	s.mu.Lock()
+	s.state.Conflicts = append(s.state.Conflicts, model.SyncConflict{
+		Key:        e.Key,
+		Copy:       copyPath,
+		DetectedAt: s.now.Format("2006-01-02 15:04:05"),
+	})
+	s.mu.Unlock()
+	s.progress.logf("⚠️ Conflict: %s (remote version saved as %s)", e.Key, copyPath)
+	return "conflict", localData, nil
+}
+
+// syncSummary - push / pull の結果の件数
+type syncSummary struct {
+	Uploaded, Downloaded, Deleted, Merged, Conflicts, Pending int
+}
+
+func (s syncSummary) String() string {
+	return fmt.Sprintf("%d uploaded, %d downloaded, %d deleted, %d merged, %d conflicts", s.Uploaded, s.Downloaded, s.Deleted, s.Merged, s.Conflicts)
+}
+
+func (s *syncSummary) add(o syncSummary) {
+	s.Uploaded += o.Uploaded
+	s.Downloaded += o.Downloaded
+	s.Deleted += o.Deleted
+	s.Merged += o.Merged
+	s.Conflicts += o.Conflicts
+	s.Pending += o.Pending
+}
+
+// syncResult - push / pull の結果
+type syncResult struct {
+	syncSummary
+	Failures    []syncFailure
+	Interrupted int // 中断されて同期しなかったファイルの数
+}
+
+// pull - 同期先で変更されたファイルを並列に取り込む
+func (s *syncSession) pull() syncResult {
+	merged, failures := s.mergeTablesFirst()
+	var targets []syncEntry
+	for _, e := range s.entries {
+		if e.remoteChanged() {
+			targets = append(targets, e)
 		}
-		err = util.SaveMetadata(filepath.Join(config.JsonDataDir, "metadata.json"), localMetadataJson)
-		if err != nil {
-			return fmt.Errorf("❌ Failed to save metadata.json for json: %w", err)
+	}
+
+	s.progress = newSyncProgress("Pulling", len(targets))
+	summary, transferFailures, interrupted := s.runTransfers(targets, s.pullEntry)
+	failures = append(failures, transferFailures...)
+	s.progress.stop(len(failures) > 0 || interrupted > 0)
+	summary.add(merged)
+	return syncResult{syncSummary: summary, Failures: failures, Interrupted: interrupted}
+}
+
+// mergeTablesFirst - 転送の前に json/ の表をマージする。失敗しても他のファイルの同期は続ける
+func (s *syncSession) mergeTablesFirst() (syncSummary, []syncFailure) {
+	merged, err := s.mergeTables()
+	if err != nil {
+		return merged, []syncFailure{{Key: "json", Err: err}}
+	}
+	return merged, nil
+}
+
+// pullEntry - 同期先で変更された 1 ファイルを取り込む
+func (s *syncSession) pullEntry(e syncEntry) (syncSummary, error) {
+	var summary syncSummary
+	if e.remoteDeleted() {
+		switch {
+		case e.LocalExists && e.localChanged():
+			// 未同期の編集は削除より優先し、次の push で同期先に戻す
+			s.progress.logf("⚠️ %s was deleted on %s but has local changes. Keeping the local file", e.Key, s.backend.Name())
+		case e.LocalExists:
+			if err := os.Remove(e.LocalPath); err != nil {
+				return summary, fmt.Errorf("❌ Failed to delete %s: %w", e.LocalPath, err)
+			}
+			s.progress.logf("🗑️ Deleted: %s", e.Key)
+			summary.Deleted++
 		}
+		return summary, s.recordDeleted(e, e.Remote.Version)
+	}
 
-		// S3 から `metadata.json` を取得
-		remoteMetadataNotes, err := util.DownloadMetadataFromS3(s3Client, config, "notes")
-		if err != nil {
-			return fmt.Errorf("❌ Failed to download metadata.json for notes: %w", err)
+	remoteData, err := s.backend.Get(s.ctx, e.Key)
+	if errors.Is(err, util.ErrObjectNotFound) {
+		s.progress.logf("⚠️ %s is listed in the manifest but missing on %s", e.Key, s.backend.Name())
+		return summary, nil
+	} else if err != nil {
+		return summary, fmt.Errorf("❌ Failed to download %s: %w", e.Key, err)
+	}
+	if e.Remote.Hash != "" && util.HashBytes(remoteData) != e.Remote.Hash {
+		// 他の端末が push している途中など。次の pull で取り込む
+		s.progress.logf("⚠️ %s on %s does not match the manifest hash. Skipping", e.Key, s.backend.Name())
+		return summary, nil
+	}
+
+	if !e.localChanged() || e.localDeleted() {
+		// ローカルで削除していても、同期先での編集を優先して復元する
+		if err := writeLocalFile(e.LocalPath, remoteData); err != nil {
+			return summary, err
+		}
+		if e.localDeleted() {
+			s.progress.logf("♻️ Restored: %s (changed on %s after local deletion)", e.Key, s.backend.Name())
+		} else {
+			s.progress.logf("✅ Downloaded: %s", e.Key)
 		}
-		remoteMetadataJson, err := util.DownloadMetadataFromS3(s3Client, config, "json")
+		summary.Downloaded++
+	} else {
+		action, _, err := s.resolveConcurrent(e, remoteData)
 		if err != nil {
-			return fmt.Errorf("❌ Failed to download metadata.json for json: %w", err)
+			return summary, err
+		}
+		switch action {
+		case "merged":
+			summary.Merged++
+		case "conflict":
+			summary.Conflicts++
 		}
+	}
 
-		// `notes/` の変更を取得
-		notesDiff := util.DetectChanges(localMetadataNotes, remoteMetadataNotes, "local")
+	// マージ結果や競合時のローカルの内容は、次の push で同期先に反映される
+	return summary, s.recordBase(e, remoteData, e.Remote.Version)
+}
 
-		// `json/` の変更を取得
-		jsonDiff := util.DetectChanges(localMetadataJson, remoteMetadataJson, "local")
+// push - ローカルで変更されたファイルを並列にアップロードする。
+// 同期先でも変更されている場合は上書きせず、マージできたものだけアップロードする
+func (s *syncSession) push() syncResult {
+	s.version = s.now.UTC().Format(time.RFC3339Nano)
+	s.unresolved = make(map[string]bool)
+	for _, c := range s.state.Conflicts {
+		s.unresolved[c.Key] = true
+	}
+	merged, failures := s.mergeTablesFirst()
 
-		// 変更があるファイルのみアップロード
-		fileList := append(notesDiff, jsonDiff...)
-		if len(fileList) == 0 {
-			log.Println("✅ No changes detected. Everything is up-to-date.")
-			return nil
+	var targets []syncEntry
+	for _, e := range s.entries {
+		if e.localChanged() || e.remoteChanged() {
+			targets = append(targets, e)
+		}
+	}
+
+	s.progress = newSyncProgress("Pushing", len(targets))
+	summary, transferFailures, interrupted := s.runTransfers(targets, s.pushEntry)
+	failures = append(failures, transferFailures...)
+	s.progress.stop(len(failures) > 0 || interrupted > 0)
+	summary.add(merged)
+
+	// 失敗・中断しても、アップロードできたファイルは manifest に反映して次回は続きから同期する
+	if err := s.saveManifests(); err != nil {
+		failures = append(failures, syncFailure{Key: util.ManifestName, Err: err})
+	}
+	return syncResult{syncSummary: summary, Failures: failures, Interrupted: interrupted}
+}
+
+// upload - ファイルをアップロードして manifest と同期状態を更新する
+func (s *syncSession) upload(e syncEntry, data []byte) error {
+	if err := s.backend.Put(s.ctx, e.Key, data); err != nil {
+		return fmt.Errorf("❌ Failed to upload %s: %w", e.Key, err)
+	}
+	s.setManifest(e, util.ManifestEntry{Hash: util.HashBytes(data), Version: s.version})
+	s.progress.logf("✅ Uploaded: %s", e.Key)
+	return s.recordBase(e, data, s.version)
+}
+
+// pushEntry - 1 ファイル分の変更を同期先に反映する
+func (s *syncSession) pushEntry(e syncEntry) (syncSummary, error) {
+	var summary syncSummary
+	switch {
+	case e.localChanged() && s.unresolved[e.Key]:
+		// 競合コピーが残っている間は、確認前のローカルの内容で同期先を上書きしない
+		s.progress.logf("⚠️ Skipping %s: resolve the conflict and delete its conflict copy first", e.Key)
+		summary.Conflicts++
+
+	case e.localDeleted():
+		switch {
+		case !e.remoteChanged():
+			if err := s.backend.Delete(s.ctx, e.Key); err != nil {
+				return summary, fmt.Errorf("❌ Failed to delete %s: %w", e.Key, err)
+			}
+			s.setManifest(e, util.ManifestEntry{
+				Version:   s.version,
+				Deleted:   true,
+				DeletedAt: s.now.Format("2006-01-02 15:04:05"),
+			})
+			s.progress.logf("🗑️ Deleted: %s", e.Key)
+			summary.Deleted++
+			return summary, s.recordDeleted(e, s.version)
+		case e.remoteDeleted():
+			return summary, s.recordDeleted(e, e.Remote.Version)
+		default:
+			// 同期先で編集されている。pull で復元されるまで削除しない
+			summary.Pending++
+		}
+
+	case e.localChanged() && (!e.remoteChanged() || e.remoteDeleted()):
+		// 同期先で削除されていても、ローカルの編集を優先して戻す
+		data, err := os.ReadFile(e.LocalPath)
+		if err != nil {
+			return summary, fmt.Errorf("❌ Failed to read %s: %w", e.LocalPath, err)
+		}
+		if err := s.upload(e, data); err != nil {
+			return summary, err
 		}
+		summary.Uploaded++
 
-		log.Println("🔄 Uploading changed files to S3...")
-		err = util.SyncFilesToS3(config, "push", fileList)
+	case e.localChanged() && e.remoteChanged() && e.LocalHash == e.Remote.Hash:
+		// 両方で同じ内容に変更されている
+		data, err := os.ReadFile(e.LocalPath)
 		if err != nil {
-			return fmt.Errorf("❌ Sync failed: %w", err)
+			return summary, fmt.Errorf("❌ Failed to read %s: %w", e.LocalPath, err)
 		}
+		return summary, s.recordBase(e, data, e.Remote.Version)
 
-		// `metadata.json` を S3 にアップロード
-		err = util.UploadMetadataToS3(s3Client, config, "notes")
+	case e.localChanged() && e.remoteChanged():
+		remoteData, err := s.backend.Get(s.ctx, e.Key)
 		if err != nil {
-			return fmt.Errorf("❌ Failed to upload metadata.json for notes: %w", err)
+			return summary, fmt.Errorf("❌ Failed to download %s: %w", e.Key, err)
 		}
-		err = util.UploadMetadataToS3(s3Client, config, "json")
+		action, data, err := s.resolveConcurrent(e, remoteData)
 		if err != nil {
-			return fmt.Errorf("❌ Failed to upload metadata.json for json: %w", err)
+			return summary, err
 		}
+		switch action {
+		case "same":
+			return summary, s.recordBase(e, remoteData, e.Remote.Version)
+		case "merged":
+			summary.Merged++
+			if err := s.upload(e, data); err != nil {
+				return summary, err
+			}
+			summary.Uploaded++
+		case "conflict":
+			// 同期先の内容は競合コピーに保存済み。確認後の push でローカルの内容を反映する
+			summary.Conflicts++
+			return summary, s.recordBase(e, remoteData, e.Remote.Version)
+		}
+
+	case e.unseenTombstone():
+		return summary, s.recordDeleted(e, e.Remote.Version)
+
+	case e.remoteChanged():
+		summary.Pending++
+	}
+	return summary, nil
+}
 
-		log.Println("✅ Sync completed successfully.")
-		return nil
+// saveManifests - 変更した manifest をアップロードする。中断後も保存できるよう、キャンセルされない context を使う
+func (s *syncSession) saveManifests() error {
+	s.mu.Lock()
+	pending := make(map[string]map[string]util.ManifestEntry)
+	for prefix := range s.changed {
+		manifest := make(map[string]util.ManifestEntry, len(s.manifests[prefix]))
+		for name, entry := range s.manifests[prefix] {
+			manifest[name] = entry
+		}
+		pending[prefix] = manifest
 	}
+	s.changed = make(map[string]bool)
+	s.mu.Unlock()
 
-	return fmt.Errorf("❌ Unknown sync direction: %s", direction)
+	ctx := context.WithoutCancel(s.ctx)
+	for prefix, manifest := range pending {
+		key := path.Join(prefix, util.ManifestName)
+		if err := util.SaveManifest(ctx, s.backend, key, manifest); err != nil {
+			// 次のチェックポイントか最後にもう一度アップロードする
+			s.mu.Lock()
+			s.changed[prefix] = true
+			s.mu.Unlock()
+			return fmt.Errorf("❌ Failed to upload %s: %w", key, err)
+		}
+	}
+	return nil
 }
 
-// ShowSyncStatus - S3 との同期状態を表示
-func ShowSyncStatus(config model.Config) error {
-	s3Client, err := util.NewS3Client(config)
-	if err != nil {
-		return fmt.Errorf("❌ Failed to initialize S3 client: %w", err)
+// checkpoint - 途中までの同期状態を保存する
+func (s *syncSession) checkpoint() error {
+	if err := s.saveManifests(); err != nil {
+		return err
 	}
+	return s.saveState()
+}
 
-	localMetadataNotes, _ := util.LoadMetadata(filepath.Join(config.ZettelDir, "metadata.json"))
-	localMetadataJson, _ := util.LoadMetadata(filepath.Join(config.JsonDataDir, "metadata.json"))
+func (s *syncSession) saveState() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return store.SaveSyncState(s.state, s.statePath)
+}
 
-	remoteMetadataNotes, err := util.DownloadMetadataFromS3(s3Client, config, "notes")
+// SyncWithRemote - sync.platform で指定した同期先との同期処理。
+// Ctrl-C で中断でき、失敗したファイルがあれば一覧を表示してエラーを返す
+func SyncWithRemote(config model.Config, direction string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	session, err := newSyncSession(ctx, config)
 	if err != nil {
 		return err
 	}
-	remoteMetadataJson, err := util.DownloadMetadataFromS3(s3Client, config, "json")
+
+	var result syncResult
+	switch direction {
+	case "pull":
+		log.Printf("🔄 Syncing files from %s...", session.backend.Name())
+		result = session.pull()
+	case "push":
+		log.Printf("🔄 Uploading changed files to %s...", session.backend.Name())
+		result = session.push()
+	default:
+		return fmt.Errorf("❌ Unknown sync direction: %s", direction)
+	}
+
+	// 途中で失敗・中断しても、それまでに同期したファイルの状態は保存する
+	if err := session.saveState(); err != nil {
+		result.Failures = append(result.Failures, syncFailure{Key: "sync state", Err: err})
+	}
+
+	if result.Pending > 0 {
+		log.Printf("⚠️ %d files changed on %s. Run `ztl sync pull` first", result.Pending, session.backend.Name())
+	}
+	if result.Conflicts > 0 {
+		log.Printf("⚠️ %d unresolved conflicts. Compare the .conflict-* copies, edit the file and delete the copy, then run `ztl sync push`", result.Conflicts)
+	}
+
+	switch {
+	case result.Interrupted > 0:
+		log.Printf("⚠️ Sync interrupted: %s, %d files not synced. Run `ztl sync %s` again to resume", result.syncSummary, result.Interrupted, direction)
+		if len(result.Failures) == 0 {
+			return fmt.Errorf("❌ Sync interrupted")
+		}
+		fallthrough
+	case len(result.Failures) > 0:
+		log.Printf("❌ Sync finished with errors: %s, %d failed", result.syncSummary, len(result.Failures))
+		for _, f := range result.Failures {
+			log.Printf("   - %s: %v", f.Key, f.Err)
+		}
+		return fmt.Errorf("❌ %d files failed to sync", len(result.Failures))
+	}
+	log.Printf("✅ Sync completed: %s", result.syncSummary)
+	return nil
+}
+
+// ShowSyncStatus - 同期先との差分と未解決の競合を表示
+func ShowSyncStatus(config model.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	session, err := newSyncSession(ctx, config)
 	if err != nil {
 		return err
 	}
 
-	notesDiff := util.DetectChanges(localMetadataNotes, remoteMetadataNotes, "s3")
-	jsonDiff := util.DetectChanges(localMetadataJson, remoteMetadataJson, "s3")
+	var toPush, toPull, both []string
+	for _, e := range session.entries {
+		switch {
+		case e.unseenTombstone(), e.localDeleted() && e.remoteDeleted():
+		case e.LocalExists && e.remoteChanged() && e.LocalHash == e.Remote.Hash:
+			// 両方で同じ内容に変更されている
+		case e.localDeleted() && e.remoteChanged():
+			toPull = append(toPull, e.Key+" (restored: changed after local deletion)")
+		case e.localChanged() && e.remoteDeleted() && e.remoteChanged():
+			toPush = append(toPush, e.Key+" (deleted on remote, kept local changes)")
+		case e.localChanged() && e.remoteChanged():
+			both = append(both, e.Key)
+		case e.localDeleted():
+			toPush = append(toPush, e.Key+" (deleted)")
+		case e.localChanged():
+			toPush = append(toPush, e.Key)
+		case e.remoteDeleted():
+			toPull = append(toPull, e.Key+" (deleted)")
+		case e.remoteChanged():
+			toPull = append(toPull, e.Key)
+		}
+	}
 
-	log.Println("📌 Files to be updated from S3:")
-	for _, file := range append(notesDiff, jsonDiff...) {
-		log.Println("   -", file)
+	fmt.Printf("🔗 Sync target: %s\n", session.backend.Name())
+	if _, ok := session.backend.(*util.EncryptedBackend); ok {
+		fmt.Println("🔒 Encrypted with AES-256-GCM")
 	}
+	printList := func(title string, keys []string) {
+		if len(keys) == 0 {
+			return
+		}
+		fmt.Printf("\n%s (%d):\n", title, len(keys))
+		for _, key := range keys {
+			fmt.Printf("   - %s\n", key)
+		}
+	}
+	printList("📤 Local changes to push", toPush)
+	printList("📥 Remote changes to pull", toPull)
+	printList("⚠️ Changed on both sides (merged or saved as conflict copies on sync)", both)
 
-	return nil
+	if len(session.state.Conflicts) > 0 {
+		fmt.Printf("\n❌ Unresolved conflicts (%d):\n", len(session.state.Conflicts))
+		for _, c := range session.state.Conflicts {
+			fmt.Printf("   - %s ↔ %s (%s)\n", c.Key, c.Copy, c.DetectedAt)
+		}
+		fmt.Println("   Resolve by editing the file and deleting the conflict copy.")
+	}
+
+	if len(toPush)+len(toPull)+len(both)+len(session.state.Conflicts) == 0 {
+		fmt.Println("\n✅ Everything is up-to-date.")
+	}
+
+	// 解決済みの競合を state から外す
+	return session.saveState()
 }
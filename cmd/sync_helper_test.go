@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// testMachine - 同じ同期先を使う 1 台分の端末（設定ファイルの場所ごとに同期状態が分かれる）
+type testMachine struct {
+	config     model.Config
+	configPath string
+}
+
+func newTestMachine(t *testing.T) testMachine {
+	t.Helper()
+	root := t.TempDir()
+	var config model.Config
+	config.ZettelDir = filepath.Join(root, "zk")
+	config.JsonDataDir = filepath.Join(root, "data")
+	config.ArchiveDir = filepath.Join(root, "archive")
+	config.Trash.TrashDir = filepath.Join(root, "trash")
+	for _, dir := range []string{config.ZettelDir, config.JsonDataDir, config.ArchiveDir, config.Trash.TrashDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return testMachine{config: config, configPath: filepath.Join(root, "config.yaml")}
+}
+
+func (m testMachine) sync(t *testing.T, backend util.SyncBackend, direction string) syncResult {
+	t.Helper()
+	t.Setenv("ZTL_CONFIG", m.configPath)
+	s, err := newSyncSessionWithBackend(context.Background(), m.config, backend)
+	if err != nil {
+		t.Fatalf("newSyncSession() error: %v", err)
+	}
+	var result syncResult
+	if direction == "push" {
+		result = s.push()
+	} else {
+		result = s.pull()
+	}
+	if err := s.saveState(); err != nil {
+		t.Fatalf("saveState() error: %v", err)
+	}
+	if len(result.Failures) > 0 {
+		t.Fatalf("%s failed: %v", direction, result.Failures[0].Err)
+	}
+	return result
+}
+
+func (m testMachine) write(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(m.config.ZettelDir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (m testMachine) read(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(m.config.ZettelDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func writeTable[T any](t *testing.T, m testMachine, name string, records []T) {
+	t.Helper()
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(m.config.JsonDataDir, name), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTable[T any](t *testing.T, m testMachine, name string) []T {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(m.config.JsonDataDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []T
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestSyncPushPull(t *testing.T) {
+	backend := util.NewMemoryBackend()
+	a, b := newTestMachine(t), newTestMachine(t)
+
+	a.write(t, "20250101120000.md", "# note\n")
+	if r := a.sync(t, backend, "push"); r.Uploaded != 1 {
+		t.Fatalf("push uploaded %d files, want 1", r.Uploaded)
+	}
+	if r := b.sync(t, backend, "pull"); r.Downloaded != 1 {
+		t.Fatalf("pull downloaded %d files, want 1", r.Downloaded)
+	}
+	if got := b.read(t, "20250101120000.md"); got != "# note\n" {
+		t.Errorf("pulled content = %q", got)
+	}
+
+	// 変更がなければ何も転送しない
+	if r := a.sync(t, backend, "push"); r.Uploaded != 0 || r.Pending != 0 {
+		t.Errorf("second push = %+v, want nothing to do", r.syncSummary)
+	}
+	if r := b.sync(t, backend, "pull"); r.Downloaded != 0 {
+		t.Errorf("second pull downloaded %d files", r.Downloaded)
+	}
+}
+
+func TestSyncTombstone(t *testing.T) {
+	backend := util.NewMemoryBackend()
+	a, b := newTestMachine(t), newTestMachine(t)
+
+	a.write(t, "20250101120000.md", "# note\n")
+	a.sync(t, backend, "push")
+	b.sync(t, backend, "pull")
+
+	if err := os.Remove(filepath.Join(a.config.ZettelDir, "20250101120000.md")); err != nil {
+		t.Fatal(err)
+	}
+	if r := a.sync(t, backend, "push"); r.Deleted != 1 {
+		t.Fatalf("push deleted %d files, want 1", r.Deleted)
+	}
+	manifest, err := util.LoadManifest(context.Background(), backend, "notes/"+util.ManifestName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := manifest["20250101120000.md"]; !entry.Deleted {
+		t.Errorf("manifest entry = %+v, want a tombstone", entry)
+	}
+
+	if r := b.sync(t, backend, "pull"); r.Deleted != 1 {
+		t.Fatalf("pull deleted %d files, want 1", r.Deleted)
+	}
+	if _, err := os.Stat(filepath.Join(b.config.ZettelDir, "20250101120000.md")); !os.IsNotExist(err) {
+		t.Errorf("deleted note still exists on the other machine: %v", err)
+	}
+	// 削除を取り込んだ端末の push で復活させない
+	if r := b.sync(t, backend, "push"); r.Uploaded != 0 {
+		t.Errorf("push after pulling the deletion uploaded %d files", r.Uploaded)
+	}
+
+	// 一度も同期していない端末は tombstone を無視する
+	c := newTestMachine(t)
+	if r := c.sync(t, backend, "pull"); r.Deleted != 0 || r.Downloaded != 0 {
+		t.Errorf("pull on a new machine = %+v", r.syncSummary)
+	}
+}
+
+func TestSyncKeepsLocalEditOverRemoteDeletion(t *testing.T) {
+	backend := util.NewMemoryBackend()
+	a, b := newTestMachine(t), newTestMachine(t)
+
+	a.write(t, "20250101120000.md", "# note\n")
+	a.sync(t, backend, "push")
+	b.sync(t, backend, "pull")
+
+	if err := os.Remove(filepath.Join(a.config.ZettelDir, "20250101120000.md")); err != nil {
+		t.Fatal(err)
+	}
+	a.sync(t, backend, "push")
+	b.write(t, "20250101120000.md", "# note\n\nedited\n")
+
+	b.sync(t, backend, "pull")
+	if got := b.read(t, "20250101120000.md"); got != "# note\n\nedited\n" {
+		t.Fatalf("local edit was lost: %q", got)
+	}
+	if r := b.sync(t, backend, "push"); r.Uploaded != 1 {
+		t.Fatalf("push uploaded %d files, want 1", r.Uploaded)
+	}
+	if r := a.sync(t, backend, "pull"); r.Downloaded != 1 {
+		t.Fatalf("pull downloaded %d files, want 1 (restored)", r.Downloaded)
+	}
+}
+
+func TestSyncMergesMarkdown(t *testing.T) {
+	backend := util.NewMemoryBackend()
+	a, b := newTestMachine(t), newTestMachine(t)
+
+	a.write(t, "20250101120000.md", "title\n\nalpha\nbeta\ngamma\n")
+	a.sync(t, backend, "push")
+	b.sync(t, backend, "pull")
+
+	a.write(t, "20250101120000.md", "title\n\nALPHA\nbeta\ngamma\n")
+	b.write(t, "20250101120000.md", "title\n\nalpha\nbeta\nGAMMA\n")
+	a.sync(t, backend, "push")
+
+	// 同期先でも変更されているので、上書きせずにマージしてからアップロードする
+	if r := b.sync(t, backend, "push"); r.Merged != 1 || r.Uploaded != 1 {
+		t.Fatalf("push = %+v, want 1 merged and uploaded", r.syncSummary)
+	}
+	want := "title\n\nALPHA\nbeta\nGAMMA\n"
+	if got := b.read(t, "20250101120000.md"); got != want {
+		t.Fatalf("merged content = %q, want %q", got, want)
+	}
+	a.sync(t, backend, "pull")
+	if got := a.read(t, "20250101120000.md"); got != want {
+		t.Errorf("content on the other machine = %q, want %q", got, want)
+	}
+}
+
+func TestSyncConflictCopy(t *testing.T) {
+	backend := util.NewMemoryBackend()
+	a, b := newTestMachine(t), newTestMachine(t)
+
+	a.write(t, "20250101120000.md", "title\n\nbody\n")
+	a.sync(t, backend, "push")
+	b.sync(t, backend, "pull")
+
+	a.write(t, "20250101120000.md", "title\n\nfrom a\n")
+	b.write(t, "20250101120000.md", "title\n\nfrom b\n")
+	a.sync(t, backend, "push")
+
+	if r := b.sync(t, backend, "pull"); r.Conflicts != 1 {
+		t.Fatalf("pull = %+v, want 1 conflict", r.syncSummary)
+	}
+	if got := b.read(t, "20250101120000.md"); got != "title\n\nfrom b\n" {
+		t.Errorf("local content = %q, want the local edit", got)
+	}
+	copies, err := filepath.Glob(filepath.Join(b.config.ZettelDir, "*conflict*"))
+	if err != nil || len(copies) != 1 {
+		t.Fatalf("conflict copies = %v, %v", copies, err)
+	}
+	data, err := os.ReadFile(copies[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "title\n\nfrom a\n" {
+		t.Errorf("conflict copy = %q, want the remote content", data)
+	}
+
+	// 競合コピーが残っている間は push しない
+	if r := b.sync(t, backend, "push"); r.Conflicts != 1 || r.Uploaded != 0 {
+		t.Fatalf("push with an unresolved conflict = %+v", r.syncSummary)
+	}
+	if err := os.Remove(copies[0]); err != nil {
+		t.Fatal(err)
+	}
+	if r := b.sync(t, backend, "push"); r.Uploaded != 1 {
+		t.Fatalf("push after resolving = %+v, want 1 uploaded", r.syncSummary)
+	}
+	a.sync(t, backend, "pull")
+	if got := a.read(t, "20250101120000.md"); got != "title\n\nfrom b\n" {
+		t.Errorf("resolved content on the other machine = %q", got)
+	}
+}
+
+func TestSyncMergesJSONTables(t *testing.T) {
+	backend := util.NewMemoryBackend()
+	a, b := newTestMachine(t), newTestMachine(t)
+
+	writeTable(t, a, "tasks.json", []model.Task{{ID: "task-001", NoteID: "20250101120000", Status: "Not started"}})
+	a.sync(t, backend, "push")
+	b.sync(t, backend, "pull")
+
+	// 両方の端末で task-002 を別々に作る。b のタスクには時間の記録がある
+	writeTable(t, a, "tasks.json", []model.Task{
+		{ID: "task-001", NoteID: "20250101120000", Status: "Done"},
+		{ID: "task-002", NoteID: "20250102120000", Status: "Not started"},
+	})
+	writeTable(t, b, "tasks.json", []model.Task{
+		{ID: "task-001", NoteID: "20250101120000", Status: "Not started", Priority: "high"},
+		{ID: "task-002", NoteID: "20250103120000", Status: "Not started", BlockedBy: []string{"task-001"}},
+	})
+	writeTable(t, b, "time_entries.json", []model.TimeEntry{{ID: "te-001", TaskID: "task-002", StartAt: "2025-01-03 12:00:00"}})
+	a.sync(t, backend, "push")
+
+	if r := b.sync(t, backend, "pull"); r.Merged != 1 || r.Conflicts != 0 {
+		t.Fatalf("pull = %+v, want tasks.json merged without conflicts", r.syncSummary)
+	}
+	tasks := readTable[model.Task](t, b, "tasks.json")
+	byNote := make(map[string]model.Task)
+	for _, task := range tasks {
+		byNote[task.NoteID] = task
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("merged tasks = %+v, want 3 tasks", tasks)
+	}
+	if task := byNote["20250101120000"]; task.Status != "Done" || task.Priority != "high" {
+		t.Errorf("task-001 = %+v, want both field changes", task)
+	}
+	if task := byNote["20250102120000"]; task.ID != "task-002" {
+		t.Errorf("remote task = %+v, want it to keep task-002", task)
+	}
+	local := byNote["20250103120000"]
+	if local.ID != "task-003" {
+		t.Fatalf("local task = %+v, want it renumbered to task-003", local)
+	}
+	entries := readTable[model.TimeEntry](t, b, "time_entries.json")
+	if len(entries) != 1 || entries[0].TaskID != "task-003" {
+		t.Errorf("time entries = %+v, want the reference renumbered", entries)
+	}
+
+	b.sync(t, backend, "push")
+	a.sync(t, backend, "pull")
+	if got := readTable[model.Task](t, a, "tasks.json"); len(got) != 3 {
+		t.Errorf("tasks on the other machine = %+v", got)
+	}
+	copies, _ := filepath.Glob(filepath.Join(a.config.JsonDataDir, "*conflict*"))
+	copies2, _ := filepath.Glob(filepath.Join(b.config.JsonDataDir, "*conflict*"))
+	if len(copies)+len(copies2) > 0 {
+		t.Errorf("conflict copies were created: %s", strings.Join(append(copies, copies2...), ", "))
+	}
+}
@@ -0,0 +1,633 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+)
+
+// syncTableMergers - json/ のファイルごとの、レコードを識別する値を使った三方向マージ
+var syncTableMergers = map[string]func(base, local, remote []byte) ([]byte, error){
+	"notes.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(n model.Note) string { return n.ID })
+	},
+	"tasks.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(t model.Task) string { return t.ID })
+	},
+	"tags.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(t model.Tag) string { return t.ID })
+	},
+	"note_tags.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(nt model.NoteTag) string { return nt.NoteID + "\x00" + nt.TagID })
+	},
+	"links.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(k model.Link) string { return k.SourceNoteID + "\x00" + k.TargetNoteID })
+	},
+	"projects.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(p model.Project) string { return p.ProjectID })
+	},
+	"project_notes.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(pn model.ProjectNote) string { return pn.ProjectID + "\x00" + pn.NoteID })
+	},
+	"sources.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(s model.Source) string { return s.SourceID })
+	},
+	"source_notes.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(sn model.SourceNote) string { return sn.SourceID + "\x00" + sn.NoteID + "\x00" + sn.Locator })
+	},
+	"time_entries.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(te model.TimeEntry) string { return te.ID })
+	},
+	"checkboxes.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(c model.Checkbox) string { return c.NoteID + "\x00" + strconv.Itoa(c.Line) })
+	},
+	"highlight_imports.json": func(b, l, r []byte) ([]byte, error) {
+		return mergeTable(b, l, r, func(h model.HighlightImport) string { return h.Key })
+	},
+}
+
+// decodeTable - JSON の配列を読み込む。空のデータは空の表として扱う
+func decodeTable[T any](data []byte) ([]T, error) {
+	var records []T
+	if len(bytes.TrimSpace(data)) == 0 {
+		return records, nil
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// mergeTable - 前回同期時の内容を基準にレコード単位で三方向マージする。
+// 並び順はローカルのまま、同期先だけにあるレコードを末尾に追加する
+func mergeTable[T any](baseData, localData, remoteData []byte, key func(T) string) ([]byte, error) {
+	base, err := decodeTable[T](baseData)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse sync base: %w", err)
+	}
+	local, err := decodeTable[T](localData)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse local file: %w", err)
+	}
+	remote, err := decodeTable[T](remoteData)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse remote file: %w", err)
+	}
+
+	index := func(records []T) map[string]T {
+		m := make(map[string]T, len(records))
+		for _, r := range records {
+			m[key(r)] = r
+		}
+		return m
+	}
+	baseByKey, localByKey, remoteByKey := index(base), index(local), index(remote)
+
+	merged := make([]T, 0, len(local)+len(remote))
+	seen := make(map[string]bool)
+	for _, l := range local {
+		k := key(l)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		b, inBase := baseByKey[k]
+		r, inRemote := remoteByKey[k]
+		switch {
+		case !inRemote && inBase && reflect.DeepEqual(l, b):
+			// 同期先で削除され、ローカルでは変更していない
+		case !inRemote:
+			// ローカルで追加・変更したもの（同期先での削除より変更を優先する）
+			merged = append(merged, l)
+		default:
+			var basePtr *T
+			if inBase {
+				basePtr = &b
+			}
+			record, err := mergeRecord(basePtr, l, r)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, record)
+		}
+	}
+	for _, r := range remote {
+		k := key(r)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		b, inBase := baseByKey[k]
+		if _, inLocal := localByKey[k]; !inLocal && inBase && reflect.DeepEqual(r, b) {
+			continue // ローカルで削除され、同期先では変更していない
+		}
+		merged = append(merged, r)
+	}
+
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// recordFields - レコードを項目名 → JSON の値に分解する
+func recordFields(v any) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string, len(raw))
+	for name, value := range raw {
+		fields[name] = string(value)
+	}
+	return fields, nil
+}
+
+// mergeRecord - 両方で変更されたレコードを項目ごとにマージする。同じ項目を別々に変更していればローカルを優先する
+func mergeRecord[T any](base *T, local, remote T) (T, error) {
+	if reflect.DeepEqual(local, remote) {
+		return local, nil
+	}
+	if base != nil && reflect.DeepEqual(local, *base) {
+		return remote, nil
+	}
+	if base != nil && reflect.DeepEqual(remote, *base) {
+		return local, nil
+	}
+
+	var merged T
+	baseFields := map[string]string{}
+	if base != nil {
+		var err error
+		if baseFields, err = recordFields(*base); err != nil {
+			return merged, err
+		}
+	}
+	localFields, err := recordFields(local)
+	if err != nil {
+		return merged, err
+	}
+	remoteFields, err := recordFields(remote)
+	if err != nil {
+		return merged, err
+	}
+
+	fields := make(map[string]json.RawMessage, len(localFields))
+	for name, value := range localFields {
+		fields[name] = json.RawMessage(value)
+	}
+	for name, value := range remoteFields {
+		if l, ok := localFields[name]; !ok || l == baseFields[name] {
+			fields[name] = json.RawMessage(value)
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return merged, err
+	}
+	err = json.Unmarshal(data, &merged)
+	return merged, err
+}
+
+// seqIDPattern - 端末ごとに採番する連番の ID（n001, task-001, p001 など）
+var seqIDPattern = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// nextSeqID - ids で使われていない次の連番の ID。store の採番と同じく 999 までは3桁ゼロ埋め
+func nextSeqID(prefix string, ids map[string]bool) string {
+	maxSeq := 0
+	for id := range ids {
+		match := seqIDPattern.FindStringSubmatch(id)
+		if match == nil || match[1] != prefix {
+			continue
+		}
+		if seq, err := strconv.Atoi(match[2]); err == nil && seq > maxSeq {
+			maxSeq = seq
+		}
+	}
+	if maxSeq+1 < 1000 {
+		return fmt.Sprintf("%s%03d", prefix, maxSeq+1)
+	}
+	return fmt.Sprintf("%s%d", prefix, maxSeq+1)
+}
+
+// seqTable - 連番の ID を持つ表。ID の衝突を調べるのに使う
+type seqTable[T any] struct {
+	prefix string
+	key    func(T) string    // レコードを識別する値
+	seq    func(T) string    // 端末ごとに採番する連番の ID
+	same   func(a, b T) bool // 別々の ID で追加されていても同じものとみなすか（タグ名など）。nil なら比べない
+}
+
+// seqRenames - 前回の同期後に両方の端末で追加され、同じ連番の ID を使っているローカルのレコードの新しい ID。
+// same で同じものとみなせる同期先のレコードがあれば、その ID に揃える
+func seqRenames[T any](t seqTable[T], baseData, localData, remoteData []byte) (map[string]string, error) {
+	base, err := decodeTable[T](baseData)
+	if err != nil {
+		return nil, err
+	}
+	local, err := decodeTable[T](localData)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := decodeTable[T](remoteData)
+	if err != nil {
+		return nil, err
+	}
+
+	inBase := make(map[string]bool)
+	for _, b := range base {
+		inBase[t.key(b)] = true
+	}
+	used := make(map[string]bool)
+	remoteBySeq := make(map[string]T)
+	var remoteAdded []T
+	for _, r := range remote {
+		used[t.seq(r)] = true
+		remoteBySeq[t.seq(r)] = r
+		if !inBase[t.key(r)] {
+			remoteAdded = append(remoteAdded, r)
+		}
+	}
+	for _, l := range local {
+		used[t.seq(l)] = true
+	}
+
+	renames := make(map[string]string)
+	for _, l := range local {
+		if inBase[t.key(l)] {
+			continue
+		}
+		if t.same != nil {
+			unified := false
+			for _, r := range remoteAdded {
+				if t.same(l, r) {
+					if t.seq(r) != t.seq(l) {
+						renames[t.seq(l)] = t.seq(r)
+					}
+					unified = true
+					break
+				}
+			}
+			if unified {
+				continue
+			}
+		}
+		r, ok := remoteBySeq[t.seq(l)]
+		if !ok || (t.key(r) == t.key(l) && reflect.DeepEqual(l, r)) {
+			continue
+		}
+		if t.key(r) == t.key(l) && t.same != nil && t.same(l, r) {
+			continue
+		}
+		newID := nextSeqID(t.prefix, used)
+		used[newID] = true
+		renames[t.seq(l)] = newID
+	}
+	return renames, nil
+}
+
+// syncTableRenames - 表ごとのローカルの ID の付け替え（元の ID → 新しい ID）
+type syncTableRenames struct {
+	notes, tasks, tags, projects, sources, timeEntries map[string]string
+}
+
+func (r syncTableRenames) empty() bool {
+	return len(r.notes)+len(r.tasks)+len(r.tags)+len(r.projects)+len(r.sources)+len(r.timeEntries) == 0
+}
+
+// renameID - 付け替える ID なら新しい ID を返す
+func renameID(renames map[string]string, id string) (string, bool) {
+	if newID, ok := renames[id]; ok {
+		return newID, true
+	}
+	return id, false
+}
+
+// tableInput - 両方で変更された json/ のファイル
+type tableInput struct {
+	entry        syncEntry
+	base, remote []byte
+}
+
+// findSyncTableRenames - 両方で変更された表について、ID の衝突を調べる
+func findSyncTableRenames(inputs map[string]*tableInput) (syncTableRenames, error) {
+	var renames syncTableRenames
+	find := func(name string, fn func(base, local, remote []byte) (map[string]string, error)) (map[string]string, error) {
+		in, ok := inputs[name]
+		if !ok || in.base == nil {
+			// 前回同期時の内容がなければ、どちらで追加したレコードか判断できない
+			return nil, nil
+		}
+		local, err := os.ReadFile(in.entry.LocalPath)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to read %s: %w", in.entry.LocalPath, err)
+		}
+		m, err := fn(in.base, local, in.remote)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to parse %s: %w", in.entry.Key, err)
+		}
+		return m, nil
+	}
+
+	var err error
+	if renames.notes, err = find("notes.json", func(b, l, r []byte) (map[string]string, error) {
+		return seqRenames(seqTable[model.Note]{prefix: "n", key: func(n model.Note) string { return n.ID }, seq: func(n model.Note) string { return n.SeqID }}, b, l, r)
+	}); err != nil {
+		return renames, err
+	}
+	if renames.tasks, err = find("tasks.json", func(b, l, r []byte) (map[string]string, error) {
+		id := func(t model.Task) string { return t.ID }
+		return seqRenames(seqTable[model.Task]{prefix: "task-", key: id, seq: id}, b, l, r)
+	}); err != nil {
+		return renames, err
+	}
+	if renames.tags, err = find("tags.json", func(b, l, r []byte) (map[string]string, error) {
+		id := func(t model.Tag) string { return t.ID }
+		same := func(a, b model.Tag) bool { return a.Name == b.Name }
+		return seqRenames(seqTable[model.Tag]{prefix: "t", key: id, seq: id, same: same}, b, l, r)
+	}); err != nil {
+		return renames, err
+	}
+	if renames.projects, err = find("projects.json", func(b, l, r []byte) (map[string]string, error) {
+		id := func(p model.Project) string { return p.ProjectID }
+		same := func(a, b model.Project) bool { return a.Name == b.Name }
+		return seqRenames(seqTable[model.Project]{prefix: "p", key: id, seq: id, same: same}, b, l, r)
+	}); err != nil {
+		return renames, err
+	}
+	if renames.sources, err = find("sources.json", func(b, l, r []byte) (map[string]string, error) {
+		id := func(s model.Source) string { return s.SourceID }
+		return seqRenames(seqTable[model.Source]{prefix: "s", key: id, seq: id}, b, l, r)
+	}); err != nil {
+		return renames, err
+	}
+	if renames.timeEntries, err = find("time_entries.json", func(b, l, r []byte) (map[string]string, error) {
+		id := func(te model.TimeEntry) string { return te.ID }
+		return seqRenames(seqTable[model.TimeEntry]{prefix: "te-", key: id, seq: id}, b, l, r)
+	}); err != nil {
+		return renames, err
+	}
+	return renames, nil
+}
+
+// rewriteLocalTable - ローカルの表の各レコードを mutate で書き換え、変更があれば保存する
+func rewriteLocalTable[T any](path string, mutate func(*T) bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("❌ Failed to read %s: %w", path, err)
+	}
+	records, err := decodeTable[T](data)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse %s: %w", path, err)
+	}
+	changed := false
+	for i := range records {
+		if mutate(&records[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	updated, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to convert to JSON: %w", err)
+	}
+	return writeLocalFile(path, updated)
+}
+
+// applySyncTableRenames - ローカルの表とタスクの front matter の ID を付け替える
+func applySyncTableRenames(renames syncTableRenames, config model.Config) error {
+	dir := config.JsonDataDir
+	rename := func(m map[string]string, id *string) bool {
+		newID, ok := renameID(m, *id)
+		*id = newID
+		return ok
+	}
+	renameAll := func(m map[string]string, ids []string) bool {
+		changed := false
+		for i := range ids {
+			if rename(m, &ids[i]) {
+				changed = true
+			}
+		}
+		return changed
+	}
+
+	if err := rewriteLocalTable(filepath.Join(dir, "notes.json"), func(n *model.Note) bool {
+		return rename(renames.notes, &n.SeqID)
+	}); err != nil {
+		return err
+	}
+
+	var blockedTasks []model.Task
+	if err := rewriteLocalTable(filepath.Join(dir, "tasks.json"), func(t *model.Task) bool {
+		changed := rename(renames.tasks, &t.ID)
+		if renameAll(renames.tasks, t.BlockedBy) {
+			blockedTasks = append(blockedTasks, *t)
+			changed = true
+		}
+		return changed
+	}); err != nil {
+		return err
+	}
+	if err := rewriteLocalTable(filepath.Join(dir, "time_entries.json"), func(te *model.TimeEntry) bool {
+		changed := rename(renames.timeEntries, &te.ID)
+		return rename(renames.tasks, &te.TaskID) || changed
+	}); err != nil {
+		return err
+	}
+
+	if err := rewriteLocalTable(filepath.Join(dir, "tags.json"), func(t *model.Tag) bool {
+		return rename(renames.tags, &t.ID)
+	}); err != nil {
+		return err
+	}
+	if err := rewriteLocalTable(filepath.Join(dir, "note_tags.json"), func(nt *model.NoteTag) bool {
+		return rename(renames.tags, &nt.TagID)
+	}); err != nil {
+		return err
+	}
+
+	if err := rewriteLocalTable(filepath.Join(dir, "projects.json"), func(p *model.Project) bool {
+		return rename(renames.projects, &p.ProjectID)
+	}); err != nil {
+		return err
+	}
+	if err := rewriteLocalTable(filepath.Join(dir, "project_notes.json"), func(pn *model.ProjectNote) bool {
+		return rename(renames.projects, &pn.ProjectID)
+	}); err != nil {
+		return err
+	}
+
+	if err := rewriteLocalTable(filepath.Join(dir, "sources.json"), func(s *model.Source) bool {
+		return rename(renames.sources, &s.SourceID)
+	}); err != nil {
+		return err
+	}
+	if err := rewriteLocalTable(filepath.Join(dir, "source_notes.json"), func(sn *model.SourceNote) bool {
+		return rename(renames.sources, &sn.SourceID)
+	}); err != nil {
+		return err
+	}
+	if err := rewriteLocalTable(filepath.Join(dir, "highlight_imports.json"), func(h *model.HighlightImport) bool {
+		return rename(renames.sources, &h.SourceID)
+	}); err != nil {
+		return err
+	}
+
+	return renameTaskBlockers(blockedTasks, renames.tasks, config)
+}
+
+// renameTaskBlockers - タスクのノートの front matter の blocked_by を付け替える
+func renameTaskBlockers(tasks []model.Task, renames map[string]string, config model.Config) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		note, ok := findNoteByID(notes, task.NoteID)
+		if !ok {
+			continue
+		}
+		notePath := noteFilePath(note, config)
+		content, err := os.ReadFile(notePath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("❌ Error reading note file: %w", err)
+		}
+		frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content))
+		if err != nil {
+			return fmt.Errorf("❌ Error parsing front matter of %s: %w", notePath, err)
+		}
+		changed := false
+		for i, id := range frontMatter.BlockedBy {
+			if newID, ok := renameID(renames, id); ok {
+				frontMatter.BlockedBy[i] = newID
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := writeLocalFile(notePath, []byte(store.UpdateFrontMatter(&frontMatter, body))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findNoteByID - ノート ID（yyyymmddhhmmss）でノートを探す
+func findNoteByID(notes []model.Note, noteID string) (model.Note, bool) {
+	for _, note := range notes {
+		if note.ID == noteID {
+			return note, true
+		}
+	}
+	return model.Note{}, false
+}
+
+// mergeTables - 両方で変更された json/ の表をレコード単位でマージする。
+// 両方の端末で同じ連番の ID を使って追加したレコードは、ローカル側に新しい ID を振り直し、参照も書き換える。
+// マージした結果はローカルに書き、前回同期時の状態を同期先の内容にして、次の push でアップロードする
+func (s *syncSession) mergeTables() (syncSummary, error) {
+	var summary syncSummary
+	inputs := make(map[string]*tableInput)
+	for _, e := range s.entries {
+		if e.Area.Prefix != "json" || syncTableMergers[e.Name] == nil || s.hasConflict(e.Key) {
+			continue
+		}
+		if !e.LocalExists || !e.localChanged() || !e.remoteChanged() || e.remoteDeleted() || e.LocalHash == e.Remote.Hash {
+			continue
+		}
+		remote, err := s.backend.Get(s.ctx, e.Key)
+		if err != nil {
+			return summary, fmt.Errorf("❌ Failed to download %s: %w", e.Key, err)
+		}
+		if e.Remote.Hash != "" && util.HashBytes(remote) != e.Remote.Hash {
+			continue // 他の端末が push している途中。次の同期でマージする
+		}
+		// 前回の同期時に無かった表は空の表を基準にする
+		base := []byte("[]")
+		if e.BaseExists && e.Base.Hash != "" {
+			base, err = os.ReadFile(s.basePath(e.Key))
+			if errors.Is(err, os.ErrNotExist) {
+				base = nil
+			} else if err != nil {
+				return summary, fmt.Errorf("❌ Failed to read sync base of %s: %w", e.Key, err)
+			}
+		}
+		inputs[e.Name] = &tableInput{entry: e, base: base, remote: remote}
+	}
+	if len(inputs) == 0 {
+		return summary, nil
+	}
+
+	renames, err := findSyncTableRenames(inputs)
+	if err != nil {
+		return summary, err
+	}
+	if !renames.empty() {
+		for _, m := range []map[string]string{renames.notes, renames.tasks, renames.tags, renames.projects, renames.sources, renames.timeEntries} {
+			for oldID, newID := range m {
+				s.progress.logf("🔢 Renumbered local %s to %s (also used on %s)", oldID, newID, s.backend.Name())
+			}
+		}
+		if err := applySyncTableRenames(renames, s.config); err != nil {
+			return summary, err
+		}
+	}
+
+	for name, in := range inputs {
+		if in.base == nil {
+			// 前回同期時の内容がない（この仕組みを入れる前に同期した）ので、削除は検出できない
+			s.progress.logf("⚠️ No sync base for %s. Merging without detecting deletions", in.entry.Key)
+		}
+		local, err := os.ReadFile(in.entry.LocalPath)
+		if err != nil {
+			return summary, fmt.Errorf("❌ Failed to read %s: %w", in.entry.LocalPath, err)
+		}
+		merged, err := syncTableMergers[name](in.base, local, in.remote)
+		if err != nil {
+			// マージできなければ、これまでどおり競合コピーで解決してもらう
+			s.progress.logf("⚠️ Could not merge %s record by record: %v", in.entry.Key, err)
+			continue
+		}
+		if err := writeLocalFile(in.entry.LocalPath, merged); err != nil {
+			return summary, err
+		}
+		if err := s.recordBase(in.entry, in.remote, in.entry.Remote.Version); err != nil {
+			return summary, err
+		}
+		s.progress.logf("🔀 Merged: %s", in.entry.Key)
+		summary.Merged++
+	}
+	return summary, s.refreshEntries()
+}
+
+// hasConflict - 競合コピーが残っているキーか
+func (s *syncSession) hasConflict(key string) bool {
+	for _, c := range s.state.Conflicts {
+		if c.Key == key {
+			return true
+		}
+	}
+	return false
+}
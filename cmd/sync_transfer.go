@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/progress"
+	"golang.org/x/term"
+)
+
+const (
+	defaultSyncWorkers = 4
+	checkpointInterval = 50 // この件数ごとに同期状態を保存し、中断しても続きから再開できるようにする
+)
+
+// syncProgress - 転送の進捗バー。端末でなければログだけ出す
+type syncProgress struct {
+	pw      progress.Writer
+	tracker *progress.Tracker
+	done    chan struct{}
+}
+
+func newSyncProgress(message string, total int) *syncProgress {
+	p := &syncProgress{}
+	if total == 0 || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return p
+	}
+
+	p.pw = progress.NewWriter()
+	p.pw.SetOutputWriter(os.Stderr)
+	p.pw.SetTrackerLength(30)
+	p.pw.SetTrackerPosition(progress.PositionRight)
+	p.pw.SetMessageLength(24)
+	p.pw.SetUpdateFrequency(100 * time.Millisecond)
+	p.pw.SetStyle(progress.StyleDefault)
+	p.pw.Style().Visibility.ETA = true
+	p.pw.Style().Visibility.Percentage = true
+	p.pw.Style().Visibility.Value = true
+	p.pw.Style().Options.TimeInProgressPrecision = time.Second
+	p.pw.Style().Options.TimeDonePrecision = time.Second
+
+	p.tracker = &progress.Tracker{Message: message, Total: int64(total), Units: progress.UnitsDefault}
+	p.pw.AppendTracker(p.tracker)
+	p.done = make(chan struct{})
+	go func() {
+		p.pw.Render()
+		close(p.done)
+	}()
+	return p
+}
+
+// logf - 進捗バーを表示中はバーの上にログを出す
+func (p *syncProgress) logf(format string, a ...any) {
+	if p == nil || p.pw == nil {
+		log.Printf(format, a...)
+		return
+	}
+	p.pw.Log(time.Now().Format("2006/01/02 15:04:05 ") + fmt.Sprintf(format, a...))
+}
+
+func (p *syncProgress) increment() {
+	if p != nil && p.tracker != nil {
+		p.tracker.Increment(1)
+	}
+}
+
+// stop - バーを閉じる（描画が終わるまで待つ）
+func (p *syncProgress) stop(failed bool) {
+	if p == nil || p.pw == nil {
+		return
+	}
+	if failed {
+		p.tracker.MarkAsErrored()
+	} else {
+		p.tracker.MarkAsDone()
+	}
+	p.pw.Stop()
+	<-p.done
+}
+
+// syncFailure - 転送に失敗したファイル
+type syncFailure struct {
+	Key string
+	Err error
+}
+
+// transferResult - 1 ファイル分の処理結果
+type transferResult struct {
+	entry   syncEntry
+	summary syncSummary
+	err     error
+}
+
+// runTransfers - entries を最大 workers 並列で処理する。
+// 失敗しても他のファイルの転送は続け、中断（Ctrl-C）されたら未着手のファイルは処理しない
+func (s *syncSession) runTransfers(entries []syncEntry, fn func(syncEntry) (syncSummary, error)) (syncSummary, []syncFailure, int) {
+	workers := s.config.Sync.Workers
+	if workers <= 0 {
+		workers = defaultSyncWorkers
+	}
+
+	jobs := make(chan syncEntry)
+	results := make(chan transferResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range jobs {
+				summary, err := fn(e)
+				results <- transferResult{entry: e, summary: summary, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, e := range entries {
+			select {
+			case jobs <- e:
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var total syncSummary
+	var failures []syncFailure
+	processed, interrupted := 0, 0
+	for r := range results {
+		processed++
+		s.progress.increment()
+		total.add(r.summary)
+		switch {
+		case r.err == nil:
+		case s.ctx.Err() != nil:
+			// 中断で失敗したものは、次回の実行で続きから転送する
+			interrupted++
+		default:
+			failures = append(failures, syncFailure{Key: r.entry.Key, Err: r.err})
+			s.progress.logf("❌ %s: %v", r.entry.Key, r.err)
+		}
+		if processed%checkpointInterval == 0 {
+			if err := s.checkpoint(); err != nil {
+				s.progress.logf("⚠️ Failed to save sync progress: %v", err)
+			}
+		}
+	}
+	return total, failures, interrupted + len(entries) - processed
+}
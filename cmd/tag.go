@@ -4,10 +4,13 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,6 +23,21 @@ import (
 
 var tagSearchQuery string
 var tagPageSize int
+var tagStatsLimit int
+
+type TagStat struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Count     int            `json:"count"`
+	FirstUsed string         `json:"first_used"`
+	LastUsed  string         `json:"last_used"`
+	NoteTypes map[string]int `json:"note_types"`
+}
+
+type TagStatsReport struct {
+	Tags         []TagStat                 `json:"tags"`
+	CoOccurrence map[string]map[string]int `json:"co_occurrence"`
+}
 
 func AddTagToNote(noteID, tagName string, config model.Config) error {
 
@@ -286,6 +304,202 @@ func ListTags(config model.Config, searchQuery string, pageSize int) error {
 	return nil
 }
 
+func CollectTagStats(config model.Config) (TagStatsReport, error) {
+	tags, _, err := store.LoadTags(config)
+	if err != nil {
+		return TagStatsReport{}, fmt.Errorf("❌ Failed to load tags.json: %w", err)
+	}
+
+	noteTags, _, err := store.LoadNoteTags(config)
+	if err != nil {
+		return TagStatsReport{}, fmt.Errorf("❌ Failed to load note_tags.json: %w", err)
+	}
+
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return TagStatsReport{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+
+	// ノートID → ノートのマッピング（削除済みノートは除外）
+	noteMap := make(map[string]model.Note)
+	for _, note := range notes {
+		if note.Deleted {
+			continue
+		}
+		noteMap[note.ID] = note
+	}
+
+	statMap := make(map[string]*TagStat)
+	for _, tag := range tags {
+		statMap[tag.ID] = &TagStat{ID: tag.ID, Name: tag.Name, NoteTypes: make(map[string]int)}
+	}
+
+	// ノートID → タグ名一覧（共起の計算用）
+	noteTagNames := make(map[string][]string)
+	for _, nt := range noteTags {
+		stat, exists := statMap[nt.TagID]
+		if !exists {
+			continue
+		}
+		note, exists := noteMap[nt.NoteID]
+		if !exists {
+			continue
+		}
+
+		stat.Count++
+		stat.NoteTypes[note.NoteType]++
+		if stat.FirstUsed == "" || note.CreatedAt < stat.FirstUsed {
+			stat.FirstUsed = note.CreatedAt
+		}
+		if note.UpdatedAt > stat.LastUsed {
+			stat.LastUsed = note.UpdatedAt
+		}
+		noteTagNames[nt.NoteID] = append(noteTagNames[nt.NoteID], stat.Name)
+	}
+
+	coOccurrence := make(map[string]map[string]int)
+	for _, names := range noteTagNames {
+		for _, a := range names {
+			for _, b := range names {
+				if a == b {
+					continue
+				}
+				if coOccurrence[a] == nil {
+					coOccurrence[a] = make(map[string]int)
+				}
+				coOccurrence[a][b]++
+			}
+		}
+	}
+
+	report := TagStatsReport{CoOccurrence: coOccurrence}
+	for _, tag := range tags {
+		report.Tags = append(report.Tags, *statMap[tag.ID])
+	}
+
+	// 使用回数の多い順に並べる
+	sort.SliceStable(report.Tags, func(i, j int) bool {
+		return report.Tags[i].Count > report.Tags[j].Count
+	})
+
+	return report, nil
+}
+
+func formatNoteTypes(noteTypes map[string]int) string {
+	var types []string
+	for noteType := range noteTypes {
+		types = append(types, noteType)
+	}
+	sort.Strings(types)
+
+	var parts []string
+	for _, noteType := range types {
+		parts = append(parts, fmt.Sprintf("%s:%d", noteType, noteTypes[noteType]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func renderTagStats(report TagStatsReport, limit int) error {
+	stats := report.Tags
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	switch outputFormat {
+	case "json":
+		jsonBytes, err := json.MarshalIndent(TagStatsReport{Tags: stats, CoOccurrence: report.CoOccurrence}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("❌ Failed to convert to JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"id", "name", "count", "first_used", "last_used", "note_types"})
+		for _, stat := range stats {
+			w.Write([]string{stat.ID, stat.Name, fmt.Sprint(stat.Count), stat.FirstUsed, stat.LastUsed, formatNoteTypes(stat.NoteTypes)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("No tags found.")
+		return nil
+	}
+
+	maxCount := 0
+	for _, stat := range stats {
+		if stat.Count > maxCount {
+			maxCount = stat.Count
+		}
+	}
+
+	fmt.Println(strings.Repeat("=", 30))
+	fmt.Printf("Zettelkasten: %v tags shown\n", len(stats))
+	fmt.Println(strings.Repeat("=", 30))
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleDouble)
+	t.Style().Options.SeparateRows = false
+
+	t.AppendHeader(table.Row{
+		text.FgGreen.Sprintf("Tag ID"), text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Tag Name")),
+		text.FgGreen.Sprintf("Usage"),
+		text.FgGreen.Sprintf("Count"),
+		text.FgGreen.Sprintf("First Used"), text.FgGreen.Sprintf("Last Used"),
+		text.FgGreen.Sprintf("Note Types"),
+	})
+
+	// 棒グラフの幅は最大 30 文字
+	const barWidth = 30
+	for _, stat := range stats {
+		bar := ""
+		if maxCount > 0 {
+			bar = strings.Repeat("█", stat.Count*barWidth/maxCount)
+		}
+		t.AppendRow(table.Row{
+			stat.ID,
+			stat.Name,
+			text.FgHiCyan.Sprintf("%s", bar),
+			stat.Count,
+			stat.FirstUsed,
+			stat.LastUsed,
+			formatNoteTypes(stat.NoteTypes),
+		})
+	}
+	t.Render()
+
+	// 共起行列
+	fmt.Println("\n🔗 Co-occurrence:")
+	m := table.NewWriter()
+	m.SetOutputMirror(os.Stdout)
+	m.SetStyle(table.StyleDouble)
+
+	header := table.Row{""}
+	for _, stat := range stats {
+		header = append(header, text.FgGreen.Sprintf("%s", stat.Name))
+	}
+	m.AppendHeader(header)
+
+	for _, a := range stats {
+		row := table.Row{text.FgGreen.Sprintf("%s", a.Name)}
+		for _, b := range stats {
+			if a.Name == b.Name {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, report.CoOccurrence[a.Name][b.Name])
+		}
+		m.AppendRow(row)
+	}
+	m.Render()
+
+	return nil
+}
+
 // tagCmd represents the tag command
 var tagCmd = &cobra.Command{
 	Use:   "tag",
@@ -360,11 +574,38 @@ var listTagCmd = &cobra.Command{
 	},
 }
 
+var statsTagCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show tag usage statistics",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateOutputFormat(); err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		report, err := CollectTagStats(*config)
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := renderTagStats(report, tagStatsLimit); err != nil {
+			fatal(err)
+		}
+	},
+}
+
 func init() {
 	tagCmd.AddCommand(addTagCmd)
 	tagCmd.AddCommand(removeTagCmd)
 	tagCmd.AddCommand(listTagCmd)
+	tagCmd.AddCommand(statsTagCmd)
 	rootCmd.AddCommand(tagCmd)
 	listTagCmd.Flags().StringVarP(&tagSearchQuery, "search", "q", "", "Search by tag name")
 	listTagCmd.Flags().IntVar(&tagPageSize, "limit", 20, "Set the number of tags to display per page (-1 for all)")
+	statsTagCmd.Flags().IntVar(&tagStatsLimit, "limit", 20, "Set the number of tags to include (-1 for all)")
 }
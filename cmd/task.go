@@ -5,7 +5,6 @@ package cmd
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -37,25 +36,46 @@ var taskMeta bool
 var taskForceDelete bool
 var taskRestoreTrash bool
 var taskRestoreArchive bool
-
-func createNewTask(taskTitle string, config model.Config) (string, model.Note, error) {
-	t := time.Now()
-	noteId := fmt.Sprintf("%d%02d%02d%02d%02d%02d",
-		t.Year(), t.Month(), t.Day(),
-		t.Hour(), t.Minute(), t.Second())
-	createdAt := t.Format("2006-01-02 15:04:05")
+var taskSort string
+var taskDueBefore string
+var taskDueAfter string
+var taskOverdue bool
+var taskBlockedOnly bool
+var taskPriorityFilter string
+var taskProjectFilter []string
+
+// createNewTask - タスクノートを作成する。fields の tags / projects / due_date などを初期値として使う
+func createNewTask(taskTitle string, fields model.TaskFrontMatter, config model.Config) (string, model.Note, error) {
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return "", model.Note{}, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	// 同じ秒に作成したノートと ID が重複しないようにする
+	noteId := nextFreeNoteID(notes, config)
+	createdAt := time.Now().Format("2006-01-02 15:04:05")
 
 	// Create front matter
 	frontMatter := model.TaskFrontMatter{
-		ID:        noteId,
-		Title:     taskTitle,
-		NoteType:  "task",
-		Tags:      taskTags,
-		Status:    "Not started",
-		CreatedAt: createdAt,
-		UpdatedAt: createdAt,
-		Archived:  false,
-		Deleted:   false,
+		ID:            noteId,
+		Title:         taskTitle,
+		NoteType:      "task",
+		Tags:          fields.Tags,
+		Projects:      fields.Projects,
+		Status:        model.TaskStatusNotStarted,
+		DueDate:       fields.DueDate,
+		ScheduledDate: fields.ScheduledDate,
+		Priority:      fields.Priority,
+		Estimate:      fields.Estimate,
+		Recurrence:    fields.Recurrence,
+		BlockedBy:     fields.BlockedBy,
+		UID:           fields.UID,
+		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
+		Archived:      false,
+		Deleted:       false,
+	}
+	if fields.Status != "" {
+		frontMatter.Status = fields.Status
 	}
 
 	// Convert to YAML format
@@ -80,6 +100,7 @@ func createNewTask(taskTitle string, config model.Config) (string, model.Note, e
 		SeqID:     "",
 		Title:     taskTitle,
 		NoteType:  "task",
+		Projects:  fields.Projects,
 		CreatedAt: createdAt,
 		UpdatedAt: createdAt,
 		Archived:  false,
@@ -94,8 +115,8 @@ func createNewTask(taskTitle string, config model.Config) (string, model.Note, e
 	task := model.Task{
 		ID:     "",
 		NoteID: noteId,
-		Status: "Not Started",
 	}
+	copyTaskFields(&task, frontMatter)
 
 	err = store.InsertTaskToJson(task, config)
 	if err != nil {
@@ -288,6 +309,18 @@ var newTaskCmd = &cobra.Command{
 			log.Printf("⚠️ Trash cleanup failed: %v", err)
 		}
 
+		fields := model.TaskFrontMatter{Tags: taskTags}
+		applyTaskFieldFlags(cmd, &fields)
+
+		tasks, _, err := store.LoadTasks(*config)
+		if err != nil {
+			log.Printf("❌ Error loading tasks from JSON: %v\n", err)
+			os.Exit(1)
+		}
+		if err := validateTaskFields("", &fields, tasks); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
 		if len(taskTags) > 0 {
 			if err := store.CreateNewTag(taskTags, *config); err != nil {
 				log.Printf("❌ Failed to create tag: %v\n", err)
@@ -295,7 +328,7 @@ var newTaskCmd = &cobra.Command{
 			}
 		}
 
-		newTaskStr, note, err := createNewTask(taskTitle, *config)
+		newTaskStr, note, err := createNewTask(taskTitle, fields, *config)
 		if err != nil {
 			log.Printf("❌ Failed to create note: %v\n", err)
 			return
@@ -310,10 +343,26 @@ var newTaskCmd = &cobra.Command{
 		log.Printf("Opening %q (Title: %q)...", newTaskStr, taskTitle)
 		time.Sleep(2 * time.Second)
 
+		initialContent, err := os.ReadFile(newTaskStr)
+		if err != nil {
+			log.Printf("❌ Failed to read Markdown file: %v", err)
+		}
+
 		err = util.OpenEditor(newTaskStr, *config)
 		if err != nil {
 			log.Printf("❌ Failed to open editor: %v\n", err)
 		}
+
+		mdContent, err := os.ReadFile(newTaskStr)
+		if err != nil {
+			log.Printf("❌ Failed to read Markdown file: %v", err)
+			return
+		}
+
+		// フロントマターの tags / links を JSON に反映
+		if err := syncNoteRelations(note.ID, string(initialContent), string(mdContent), *config); err != nil {
+			log.Printf("⚠️ Failed to sync tags and links: %v", err)
+		}
 	},
 }
 
@@ -393,13 +442,31 @@ var listTaskCmd = &cobra.Command{
 		}
 
 		// **タスクのみに絞った `filteredTasks` を作成**
-		filteredTasks := []struct {
-			Task model.Task
-			Note model.Note
-		}{}
+		filteredTasks := []taskRow{}
 
 		noteTagDisplay := make(map[string][]string)
 
+		dueBefore, err := util.ParseDate(taskDueBefore)
+		if err != nil {
+			log.Fatalf("❌ --due-before: %v", err)
+		}
+		dueAfter, err := util.ParseDate(taskDueAfter)
+		if err != nil {
+			log.Fatalf("❌ --due-after: %v", err)
+		}
+		statusFilter := ""
+		if status != "" {
+			statusFilter = model.NormalizeTaskStatus(status)
+			if statusFilter == "" {
+				log.Fatalf("❌ Invalid status %q (must be one of: %s)", status, strings.Join(model.TaskStatuses, ", "))
+			}
+		}
+		inProjects, err := projectFilterIDs(taskProjectFilter, notes, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		today := time.Now().Format("2006-01-02")
+
 		for _, note := range notes {
 			// タスクでないノートはスキップ
 			if note.NoteType != "task" {
@@ -435,13 +502,44 @@ var listTaskCmd = &cobra.Command{
 				continue
 			}
 
+			// `--project` のフィルタ（いずれかのプロジェクトに属していれば表示）
+			if inProjects != nil && !inProjects[note.ID] {
+				continue
+			}
+
+			// `--status` / `--priority` のフィルタ
+			if statusFilter != "" && model.NormalizeTaskStatus(task.Status) != statusFilter {
+				continue
+			}
+			if taskPriorityFilter != "" && !strings.EqualFold(task.Priority, taskPriorityFilter) {
+				continue
+			}
+
+			// `--due-before` / `--due-after` / `--overdue` の期限フィルタ
+			if (dueBefore != "" || dueAfter != "" || taskOverdue) && task.DueDate == "" {
+				continue
+			}
+			if dueBefore != "" && task.DueDate > dueBefore {
+				continue
+			}
+			if dueAfter != "" && task.DueDate < dueAfter {
+				continue
+			}
+			if taskOverdue && (task.DueDate >= today || task.Status == model.TaskStatusDone) {
+				continue
+			}
+
+			// `--blocked` は未完了のブロッカーがあるタスクのみ
+			if taskBlockedOnly && len(openBlockers(task, tasks)) == 0 {
+				continue
+			}
+
 			// `filteredTasks` に `Task` と `Note` をセット
-			filteredTasks = append(filteredTasks, struct {
-				Task model.Task
-				Note model.Note
-			}{Task: task, Note: note})
+			filteredTasks = append(filteredTasks, taskRow{Task: task, Note: note})
 		}
 
+		sortTaskRows(filteredTasks, taskSort)
+
 		// ページネーションの準備
 		reader := bufio.NewReader(os.Stdin)
 		page := 0
@@ -478,6 +576,10 @@ var listTaskCmd = &cobra.Command{
 				text.FgGreen.Sprintf("Task ID"), text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Title")),
 				text.FgGreen.Sprintf("Tags"),
 				text.FgGreen.Sprintf("Status"),
+				text.FgGreen.Sprintf("Priority"),
+				text.FgGreen.Sprintf("Due"),
+				text.FgGreen.Sprintf("Estimate"),
+				text.FgGreen.Sprintf("Blocked by"),
 				text.FgGreen.Sprintf("Created"), text.FgGreen.Sprintf("Updated"),
 			})
 
@@ -502,11 +604,32 @@ var listTaskCmd = &cobra.Command{
 					statusColored = taskStatus
 				}
 
+				dueColored := row.Task.DueDate
+				if row.Task.DueDate != "" && row.Task.DueDate < today && taskStatus != model.TaskStatusDone {
+					dueColored = text.FgHiRed.Sprintf("%s", row.Task.DueDate)
+				}
+
+				priorityColored := row.Task.Priority
+				switch row.Task.Priority {
+				case "high":
+					priorityColored = text.FgHiRed.Sprintf("%s", row.Task.Priority)
+				case "medium":
+					priorityColored = text.FgHiYellow.Sprintf("%s", row.Task.Priority)
+				}
+
+				if row.Task.Recurrence != "" {
+					note.Title = "🔁 " + note.Title
+				}
+
 				t.AppendRow(table.Row{
 					row.Task.ID,
 					note.Title,
 					tagStr,
 					statusColored,
+					priorityColored,
+					dueColored,
+					row.Task.Estimate,
+					strings.Join(row.Task.BlockedBy, ", "),
 					note.CreatedAt,
 					note.UpdatedAt,
 				})
@@ -535,16 +658,25 @@ var listTaskCmd = &cobra.Command{
 }
 
 var updateTaskCmd = &cobra.Command{
-	Use:   "update [title]",
-	Short: "Update task status",
-	Args:  cobra.ExactArgs(2),
+	Use:   "update [taskID] [status]",
+	Short: "Update task status and fields",
+	Long: `Update task status and fields.
+
+Status must be one of: Not started, In progress, Waiting, On hold, Done.
+When a recurring task is marked Done, its next occurrence is created.`,
+	Args: cobra.RangeArgs(1, 2),
 	// Aliases: []string{""},
 	Run: func(cmd *cobra.Command, args []string) {
 		taskID := args[0]
-		updatedStatus := args[1]
+		updatedStatus := ""
+		if len(args) == 2 {
+			updatedStatus = args[1]
+		}
 
-		if updatedStatus == "" {
-			log.Fatalf("❌ Error: --status flag is required")
+		if updatedStatus == "" && !cmd.Flags().Changed("due") && !cmd.Flags().Changed("scheduled") &&
+			!cmd.Flags().Changed("priority") && !cmd.Flags().Changed("estimate") &&
+			!cmd.Flags().Changed("recur") && !cmd.Flags().Changed("blocked-by") {
+			log.Fatalf("❌ Error: specify a status or at least one field flag")
 		}
 
 		config, err := store.LoadConfig()
@@ -561,74 +693,20 @@ var updateTaskCmd = &cobra.Command{
 			log.Printf("⚠️ Trash cleanup failed: %v", err)
 		}
 
-		// `tasks.json` をロード
-		tasks, taskJsonPath, err := store.LoadTasks(*config)
-		if err != nil {
-			log.Printf("❌ Error loading config: %v\n", err)
-			os.Exit(1)
-		}
-
-		notes, noteJsonPath, err := store.LoadNotes(*config)
-		if err != nil {
-			log.Printf("❌ Error loading notes from JSON: %v", err)
-			os.Exit(1)
-		}
-
-		found := false
-		for i, task := range tasks {
-			if task.ID == taskID {
-				// `status` を更新
-				tasks[i].Status = updatedStatus
-				found = true
-
-				// ノートの `updated_at` も更新
-				for j, note := range notes {
-					if note.ID == task.NoteID {
-						content, err := os.ReadFile(filepath.Join(config.ZettelDir, notes[j].ID+".md"))
-						if err != nil {
-							log.Printf("❌ Failed to read updated note file: %v", err)
-							os.Exit(1)
-						}
-						frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content))
-						if err != nil {
-							log.Printf("❌ Error parsing front matter: %v", err)
-							os.Exit(1)
-						}
-						notes[j].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
-						frontMatter.Status = updatedStatus
-
-						updatedContent := store.UpdateFrontMatter(&frontMatter, body)
-
-						err = os.WriteFile(filepath.Join(config.ZettelDir, notes[j].ID+".md"), []byte(updatedContent), 0644)
-						if err != nil {
-							log.Printf("❌ Error writing updated note file: %v", err)
-							return
-						}
-					}
-				}
+		next, err := updateTask(taskID, func(frontMatter *model.TaskFrontMatter) {
+			if updatedStatus != "" {
+				frontMatter.Status = updatedStatus
 			}
-
-		}
-
-		if !found {
-			log.Printf("❌ Task with ID %s not found", taskID)
-		}
-
-		// `tasks.json` を更新
-		err = store.SaveUpdatedJson(tasks, taskJsonPath)
+			applyTaskFieldFlags(cmd, frontMatter)
+		}, *config)
 		if err != nil {
-			log.Printf("❌ Error updating JSON file: %v", err)
-			return
+			log.Fatalf("%v", err)
 		}
 
-		err = store.SaveUpdatedJson(notes, noteJsonPath)
-		if err != nil {
-			log.Printf("❌ Error updating JSON file: %v", err)
-			return
+		fmt.Printf("✅ Task %s updated\n", taskID)
+		if next != nil {
+			fmt.Printf("🔁 Next occurrence created: %s (due %s)\n", next.ID, next.DueDate)
 		}
-
-		fmt.Printf("✅ Task %s status updated to %s\n", taskID, updatedStatus)
-
 	},
 }
 
@@ -703,12 +781,18 @@ var showTaskCmd = &cobra.Command{
 		fmt.Printf("Tags: %v\n", frontMatterStyle(frontMatter.Tags))
 		fmt.Printf("Links: %v\n", frontMatterStyle(frontMatter.Links))
 		fmt.Printf("Task status: %v\n", frontMatterStyle(frontMatter.Status))
+		fmt.Printf("Priority: %v\n", frontMatterStyle(frontMatter.Priority))
+		fmt.Printf("Due: %v\n", frontMatterStyle(frontMatter.DueDate))
+		fmt.Printf("Scheduled: %v\n", frontMatterStyle(frontMatter.ScheduledDate))
+		fmt.Printf("Estimate: %v\n", frontMatterStyle(frontMatter.Estimate))
+		fmt.Printf("Recurrence: %v\n", frontMatterStyle(frontMatter.Recurrence))
+		fmt.Printf("Blocked by: %v\n", frontMatterStyle(frontMatter.BlockedBy))
 		fmt.Printf("Created at: %v\n", frontMatterStyle(frontMatter.CreatedAt))
 		fmt.Printf("Updated at: %v\n", frontMatterStyle(frontMatter.UpdatedAt))
 
 		// Render Markdown content unless --meta flag is used
 		if !taskMeta {
-			renderedContent, err := glamour.Render(body, "dark")
+			renderedContent, err := glamour.Render(resolveNoteCitations(body, *config), "dark")
 			if err != nil {
 				log.Printf("⚠️ Failed to render markdown content: %v", err)
 			} else {
@@ -782,6 +866,12 @@ var editTaskCmd = &cobra.Command{
 					log.Printf("⚠️ Backup failed: %v", err)
 				}
 
+				originalContent, err := os.ReadFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"))
+				if err != nil {
+					log.Printf("❌ Failed to read note file: %v", err)
+					os.Exit(1)
+				}
+
 				fmt.Printf("Found %v, opening...\n", filepath.Join(config.ZettelDir, notes[i].ID+".md"))
 				time.Sleep(2 * time.Second)
 
@@ -795,46 +885,36 @@ var editTaskCmd = &cobra.Command{
 					os.Exit(1)
 				}
 
-				mdContent, err := os.ReadFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"))
+				// エディタで変更されたステータス・期限などを検証して tasks.json / notes.json に反映する。
+				// 繰り返しタスクを Done にした場合は task update と同じく次回分を作成する
+				next, err := updateTask(taskID, func(*model.TaskFrontMatter) {}, *config)
 				if err != nil {
-					log.Printf("❌ Failed to read updated note file: %v", err)
+					log.Printf("%v", err)
+					// 不正な内容は反映せずに元に戻し、編集した内容は別のファイルに残す
+					if rejected, saveErr := saveRejectedEdit(filepath.Join(config.ZettelDir, notes[i].ID+".md")); saveErr == nil {
+						log.Printf("⚠️ Your edit was saved to %s", rejected)
+					}
+					if err := os.WriteFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"), originalContent, 0644); err != nil {
+						log.Printf("❌ Failed to restore note file: %v", err)
+					}
+					os.Remove(lockFile)
 					os.Exit(1)
 				}
 
-				// Parse front matter
-				frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](string(mdContent))
+				updatedContent, err := os.ReadFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"))
 				if err != nil {
-					log.Printf("❌ Error parsing front matter: %v", err)
+					log.Printf("❌ Failed to read updated note file: %v", err)
 					os.Exit(1)
 				}
 
-				frontMatter.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
-
-				notes[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
-
-				updatedContent := store.UpdateFrontMatter(&frontMatter, body)
-				err = os.WriteFile(filepath.Join(config.ZettelDir, notes[i].ID+".md"), []byte(updatedContent), 0644)
-				if err != nil {
-					log.Printf("❌ Error writing updated note file: %v", err)
-				}
-
-				notes[i].Title = frontMatter.Title
-				// notes[i].Links = frontMatter.Links
-				notes[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
-				notes[i].Content = body
-
-				updatedJson, err := json.MarshalIndent(notes, "", "  ")
-				if err != nil {
-					log.Printf("❌ Failed to convert updated notes to JSON: %v", err)
-					os.Exit(1)
+				// フロントマターの tags / links を JSON に反映
+				if err := syncNoteRelations(notes[i].ID, string(originalContent), string(updatedContent), *config); err != nil {
+					log.Printf("⚠️ Failed to sync tags and links: %v", err)
 				}
 
-				// Write back to `zettel.json`
-				if err := os.WriteFile(notesJsonPath, updatedJson, 0644); err != nil {
-					log.Printf("❌ Failed to write updated notes to JSON file: %v", err)
-					os.Exit(1)
+				if next != nil {
+					fmt.Printf("🔁 Next occurrence created: %s (due %s)\n", next.ID, next.DueDate)
 				}
-
 				fmt.Println("✅ Note metadata updated successfully:", notesJsonPath)
 
 				found = true
@@ -1034,8 +1114,17 @@ func init() {
 	taskCmd.AddCommand(restoreTaskCmd)
 	rootCmd.AddCommand(taskCmd)
 	newTaskCmd.Flags().StringSliceVarP(&taskTags, "tag", "t", []string{}, "Specify tags")
+	addTaskFieldFlags(newTaskCmd)
+	addTaskFieldFlags(updateTaskCmd)
 	listTaskCmd.Flags().StringVar(&status, "status", "", "Filter by status")
+	listTaskCmd.Flags().StringVar(&taskPriorityFilter, "priority", "", "Filter by priority (high, medium, low)")
+	listTaskCmd.Flags().StringVar(&taskDueBefore, "due-before", "", "Show tasks due on or before the date")
+	listTaskCmd.Flags().StringVar(&taskDueAfter, "due-after", "", "Show tasks due on or after the date")
+	listTaskCmd.Flags().BoolVar(&taskOverdue, "overdue", false, "Show only overdue tasks")
+	listTaskCmd.Flags().BoolVar(&taskBlockedOnly, "blocked", false, "Show only tasks blocked by unfinished tasks")
+	listTaskCmd.Flags().StringVar(&taskSort, "sort", "created", "Sort by created, updated, due, scheduled, priority, estimate or status")
 	listTaskCmd.Flags().StringSliceVarP(&taskTags, "tag", "t", []string{}, "Filter by tags")
+	listTaskCmd.Flags().StringSliceVar(&taskProjectFilter, "project", []string{}, "Filter by project ID or name (repeatable, matches any)")
 	listTaskCmd.Flags().StringVar(&taskFrom, "from", "", "Filter by start date (YYYY-MM-DD)")
 	listTaskCmd.Flags().StringVar(&taskTo, "to", "", "Filter by end date (YYYY-MM-DD)")
 	listTaskCmd.Flags().StringVarP(&taskSearchQuery, "search", "q", "", "Search by title or content")
@@ -0,0 +1,318 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var boardProject string
+var boardStatic bool
+
+const boardColumnWidth = 26
+
+type boardCard struct {
+	TaskID   string
+	Title    string
+	DueDate  string
+	Priority string
+}
+
+// loadBoardColumns - ステータスごとにタスクを振り分ける（削除・アーカイブ済みは除外）
+func loadBoardColumns(projectID string, config model.Config) ([][]boardCard, error) {
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	noteMap := make(map[string]model.Note)
+	for _, note := range notes {
+		noteMap[note.ID] = note
+	}
+
+	var inProject map[string]bool
+	if projectID != "" {
+		projectNotes, _, err := store.LoadProjectNotes(config)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+		}
+		inProject = make(map[string]bool)
+		for _, pn := range projectNotes {
+			if pn.ProjectID == projectID {
+				inProject[pn.NoteID] = true
+			}
+		}
+	}
+
+	rows := []taskRow{}
+	for _, task := range tasks {
+		note, exists := noteMap[task.NoteID]
+		if !exists || note.Deleted || note.Archived {
+			continue
+		}
+		if inProject != nil && !inProject[task.NoteID] {
+			continue
+		}
+		rows = append(rows, taskRow{Task: task, Note: note})
+	}
+	sortTaskRows(rows, "priority")
+
+	columns := make([][]boardCard, len(model.TaskStatuses))
+	for _, row := range rows {
+		col := statusRank(row.Task.Status)
+		if col >= len(columns) {
+			col = 0 // 不明なステータスは Not started 扱い
+		}
+		columns[col] = append(columns[col], boardCard{
+			TaskID:   row.Task.ID,
+			Title:    row.Note.Title,
+			DueDate:  row.Task.DueDate,
+			Priority: row.Task.Priority,
+		})
+	}
+	return columns, nil
+}
+
+func formatCard(card boardCard) (string, string) {
+	title := truncateDisplay(card.TaskID+" "+card.Title, boardColumnWidth-2)
+	var meta []string
+	if card.Priority != "" {
+		meta = append(meta, "!"+card.Priority)
+	}
+	if card.DueDate != "" {
+		meta = append(meta, "due "+card.DueDate)
+	}
+	return title, truncateDisplay(strings.Join(meta, " "), boardColumnWidth-2)
+}
+
+// renderBoard - 列を横に並べて描画。selCol / selRow が -1 の場合はカーソルなし
+func renderBoard(columns [][]boardCard, selCol, selRow int) string {
+	var s strings.Builder
+
+	var headers []string
+	for i, status := range model.TaskStatuses {
+		header := padDisplay(fmt.Sprintf("%s (%d)", status, len(columns[i])), boardColumnWidth)
+		if i == selCol {
+			header = text.Bold.Sprint(text.FgCyan.Sprint(header))
+		}
+		headers = append(headers, header)
+	}
+	s.WriteString(strings.Join(headers, " │ ") + "\n")
+
+	var rules []string
+	for range model.TaskStatuses {
+		rules = append(rules, strings.Repeat("─", boardColumnWidth))
+	}
+	s.WriteString(strings.Join(rules, "─┼─") + "\n")
+
+	maxRows := 0
+	for _, col := range columns {
+		if len(col) > maxRows {
+			maxRows = len(col)
+		}
+	}
+
+	today := time.Now().Format("2006-01-02")
+	for r := 0; r < maxRows; r++ {
+		for line := 0; line < 3; line++ {
+			var cells []string
+			for c, col := range columns {
+				cell := padDisplay("", boardColumnWidth)
+				if r < len(col) {
+					title, meta := formatCard(col[r])
+					marker := "  "
+					if c == selCol && r == selRow {
+						marker = "▶ "
+					}
+					switch line {
+					case 0:
+						cell = padDisplay(marker+title, boardColumnWidth)
+						if c == selCol && r == selRow {
+							cell = text.FgHiYellow.Sprint(cell)
+						}
+					case 1:
+						cell = padDisplay("  "+meta, boardColumnWidth)
+						if col[r].DueDate != "" && col[r].DueDate < today && c != len(columns)-1 {
+							cell = text.FgHiRed.Sprint(cell)
+						}
+					}
+				}
+				cells = append(cells, cell)
+			}
+			s.WriteString(strings.Join(cells, " │ ") + "\n")
+		}
+	}
+
+	return s.String()
+}
+
+type boardModel struct {
+	config    model.Config
+	projectID string
+	columns   [][]boardCard
+	col       int
+	row       int
+	message   string
+}
+
+func (m *boardModel) reload() {
+	columns, err := loadBoardColumns(m.projectID, m.config)
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.columns = columns
+	m.clampRow()
+}
+
+func (m *boardModel) clampRow() {
+	if m.row >= len(m.columns[m.col]) {
+		m.row = len(m.columns[m.col]) - 1
+	}
+	if m.row < 0 {
+		m.row = 0
+	}
+}
+
+// moveCard - 選択中のカードを隣の列へ移動（task update と同じ更新処理を使う）
+func (m *boardModel) moveCard(delta int) {
+	target := m.col + delta
+	if target < 0 || target >= len(m.columns) || len(m.columns[m.col]) == 0 {
+		return
+	}
+	card := m.columns[m.col][m.row]
+	status := model.TaskStatuses[target]
+
+	next, err := updateTaskStatus(card.TaskID, status, m.config)
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.message = fmt.Sprintf("✅ %s → %s", card.TaskID, status)
+	if next != nil {
+		m.message += fmt.Sprintf(" (🔁 next occurrence %s due %s)", next.ID, next.DueDate)
+	}
+
+	m.reload()
+	m.col = target
+	for i, c := range m.columns[target] {
+		if c.TaskID == card.TaskID {
+			m.row = i
+		}
+	}
+}
+
+func (m *boardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		m.message = ""
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "left", "h":
+			if m.col > 0 {
+				m.col--
+				m.clampRow()
+			}
+		case "right", "l":
+			if m.col < len(m.columns)-1 {
+				m.col++
+				m.clampRow()
+			}
+		case "up", "k":
+			if m.row > 0 {
+				m.row--
+			}
+		case "down", "j":
+			if m.row < len(m.columns[m.col])-1 {
+				m.row++
+			}
+		case "shift+left", "H", "<":
+			m.moveCard(-1)
+		case "shift+right", "L", ">":
+			m.moveCard(1)
+		case "r":
+			m.reload()
+		}
+	}
+	return m, nil
+}
+
+func (m *boardModel) View() string {
+	var s strings.Builder
+	s.WriteString("📋 Task board")
+	if m.projectID != "" {
+		s.WriteString(" — " + m.projectID)
+	}
+	s.WriteString("\n\n")
+	s.WriteString(renderBoard(m.columns, m.col, m.row))
+	s.WriteString("\n")
+	if m.message != "" {
+		s.WriteString(m.message + "\n")
+	}
+	s.WriteString("←→/hl: column  ↑↓/jk: card  H/L or </>: move card  r: reload  q: quit\n")
+	return s.String()
+}
+
+var boardTaskCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Show tasks as a kanban board",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		columns, err := loadBoardColumns(boardProject, *config)
+		if err != nil {
+			fatal(err)
+		}
+
+		// パイプやリダイレクト時は色を付けずに静的な表示にする
+		stdoutIsTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+		if boardStatic || !stdoutIsTerminal || !term.IsTerminal(int(os.Stdin.Fd())) {
+			if !stdoutIsTerminal {
+				text.DisableColors()
+			}
+			fmt.Print(renderBoard(columns, -1, -1))
+			return
+		}
+
+		// 更新処理のログで画面が崩れないよう、TUI 中はログを抑制
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(os.Stderr)
+
+		m := &boardModel{config: *config, projectID: boardProject, columns: columns}
+		if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+			log.SetOutput(os.Stderr)
+			log.Fatalf("❌ Error running board: %v", err)
+		}
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(boardTaskCmd)
+	boardTaskCmd.Flags().StringVar(&boardProject, "project", "", "Show only tasks in the project (e.g. p001)")
+	boardTaskCmd.Flags().BoolVar(&boardStatic, "static", false, "Print a static board instead of the interactive view")
+}
@@ -0,0 +1,216 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var checkboxShowAll bool
+var checkboxNoteType string
+
+// findNoteByRef - SeqID (n001) またはノートID (yyyymmddhhmmss) からノートを探す
+func findNoteByRef(notes []model.Note, ref string) (model.Note, bool) {
+	for _, note := range notes {
+		if note.SeqID == ref || note.ID == ref {
+			return note, true
+		}
+	}
+	return model.Note{}, false
+}
+
+type checkboxRow struct {
+	Ref      string `json:"ref"`
+	SeqID    string `json:"seq_id"`
+	NoteID   string `json:"note_id"`
+	Title    string `json:"title"`
+	NoteType string `json:"note_type"`
+	Line     int    `json:"line"`
+	Text     string `json:"text"`
+	Done     bool   `json:"done"`
+}
+
+var inboxTaskCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "List open checkboxes found in note bodies",
+	Long: `List open "- [ ]" checkboxes found in every note in ZettelDir.
+
+Each item is shown as <note>:<line>, which can be passed to "ztl task toggle".`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateOutputFormat(); err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		checkboxes, err := store.ScanCheckboxes(*config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		notes, _, err := store.LoadNotes(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load notes.json: %v", err)
+		}
+		noteMap := make(map[string]model.Note)
+		for _, note := range notes {
+			noteMap[note.ID] = note
+		}
+
+		rows := []checkboxRow{}
+		for _, cb := range checkboxes {
+			if cb.Done && !checkboxShowAll {
+				continue
+			}
+			note := noteMap[cb.NoteID]
+			if checkboxNoteType != "" && note.NoteType != checkboxNoteType {
+				continue
+			}
+			ref := cb.NoteID
+			if note.SeqID != "" {
+				ref = note.SeqID
+			}
+			rows = append(rows, checkboxRow{
+				Ref:      fmt.Sprintf("%s:%d", ref, cb.Line),
+				SeqID:    note.SeqID,
+				NoteID:   cb.NoteID,
+				Title:    note.Title,
+				NoteType: note.NoteType,
+				Line:     cb.Line,
+				Text:     cb.Text,
+				Done:     cb.Done,
+			})
+		}
+
+		switch outputFormat {
+		case "json":
+			jsonBytes, err := json.MarshalIndent(rows, "", "  ")
+			if err != nil {
+				log.Fatalf("❌ Failed to convert to JSON: %v", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return
+		case "csv":
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"ref", "note_id", "title", "note_type", "line", "text", "done"})
+			for _, row := range rows {
+				w.Write([]string{row.Ref, row.NoteID, row.Title, row.NoteType, strconv.Itoa(row.Line), row.Text, strconv.FormatBool(row.Done)})
+			}
+			w.Flush()
+			return
+		}
+
+		fmt.Println(strings.Repeat("=", 30))
+		fmt.Printf("Checkboxes: %v items shown\n", len(rows))
+		fmt.Println(strings.Repeat("=", 30))
+
+		if len(rows) == 0 {
+			return
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleDouble)
+		t.Style().Options.SeparateRows = false
+
+		t.AppendHeader(table.Row{
+			text.FgGreen.Sprintf("Ref"), text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Item")),
+			text.FgGreen.Sprintf("Note"), text.FgGreen.Sprintf("Type"),
+		})
+		for _, row := range rows {
+			item := "☐ " + row.Text
+			if row.Done {
+				item = text.FgHiBlack.Sprintf("☑ %s", row.Text)
+			}
+			t.AppendRow(table.Row{row.Ref, item, row.Title, row.NoteType})
+		}
+		t.Render()
+	},
+}
+
+var toggleTaskCmd = &cobra.Command{
+	Use:   "toggle [note:line]",
+	Short: "Toggle a checkbox in a note body",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref, lineStr, ok := strings.Cut(args[0], ":")
+		if !ok {
+			log.Fatalf("❌ Invalid reference %q (expected <note>:<line>, e.g. n001:12)", args[0])
+		}
+		line, err := strconv.Atoi(lineStr)
+		if err != nil {
+			log.Fatalf("❌ Invalid line number %q", lineStr)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		notes, notesJsonPath, err := store.LoadNotes(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load notes.json: %v", err)
+		}
+		note, found := findNoteByRef(notes, ref)
+		if !found {
+			log.Fatalf("❌ Note with ID %s not found", ref)
+		}
+
+		checkbox, updatedContent, err := store.ToggleCheckbox(note.ID, line, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		// `notes.json` の本文と更新日時を反映
+		_, body, err := store.ParseFrontMatter[model.NoteFrontMatter](updatedContent)
+		if err != nil {
+			body = updatedContent
+		}
+		for i := range notes {
+			if notes[i].ID == note.ID {
+				notes[i].Content = body
+				notes[i].UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+				break
+			}
+		}
+		if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+			log.Printf("❌ Failed to update notes.json: %v", err)
+		}
+
+		if _, err := store.ScanCheckboxes(*config); err != nil {
+			log.Printf("⚠️ Failed to update checkbox index: %v", err)
+		}
+
+		state := "☐"
+		if checkbox.Done {
+			state = "☑"
+		}
+		fmt.Printf("✅ %s %s (%s:%d)\n", state, checkbox.Text, ref, line)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(inboxTaskCmd)
+	taskCmd.AddCommand(toggleTaskCmd)
+	inboxTaskCmd.Flags().BoolVar(&checkboxShowAll, "all", false, "Include completed checkboxes")
+	inboxTaskCmd.Flags().StringVar(&checkboxNoteType, "type", "", "Only show checkboxes from notes of this type")
+}
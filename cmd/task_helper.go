@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var taskDue string
+var taskScheduled string
+var taskPriority string
+var taskEstimate string
+var taskRecur string
+var taskBlockedBy []string
+
+// addTaskFieldFlags - due / scheduled / priority などの共通フラグを登録
+func addTaskFieldFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&taskDue, "due", "", "Due date (YYYY-MM-DD, today, tomorrow, +3d)")
+	cmd.Flags().StringVar(&taskScheduled, "scheduled", "", "Scheduled date (YYYY-MM-DD, today, tomorrow, +3d)")
+	cmd.Flags().StringVar(&taskPriority, "priority", "", "Priority (high, medium, low)")
+	cmd.Flags().StringVar(&taskEstimate, "estimate", "", "Time estimate (e.g. 30m, 1h30m)")
+	cmd.Flags().StringVar(&taskRecur, "recur", "", "Recurrence rule (daily, weekly or RRULE like FREQ=WEEKLY;BYDAY=MO,TH)")
+	cmd.Flags().StringSliceVar(&taskBlockedBy, "blocked-by", []string{}, "Task IDs that block this task")
+}
+
+// applyTaskFieldFlags - 指定されたフラグだけをフロントマターに反映
+func applyTaskFieldFlags(cmd *cobra.Command, frontMatter *model.TaskFrontMatter) {
+	if cmd.Flags().Changed("due") {
+		frontMatter.DueDate = taskDue
+	}
+	if cmd.Flags().Changed("scheduled") {
+		frontMatter.ScheduledDate = taskScheduled
+	}
+	if cmd.Flags().Changed("priority") {
+		frontMatter.Priority = taskPriority
+	}
+	if cmd.Flags().Changed("estimate") {
+		frontMatter.Estimate = taskEstimate
+	}
+	if cmd.Flags().Changed("recur") {
+		frontMatter.Recurrence = taskRecur
+	}
+	if cmd.Flags().Changed("blocked-by") {
+		frontMatter.BlockedBy = taskBlockedBy
+	}
+}
+
+// validateTaskFields - ステータス・日付・優先度・見積もり・繰り返し・依存関係を検証し、表記を正規化
+func validateTaskFields(taskID string, frontMatter *model.TaskFrontMatter, tasks []model.Task) error {
+	if frontMatter.Status == "" {
+		frontMatter.Status = model.TaskStatusNotStarted
+	}
+	status := model.NormalizeTaskStatus(frontMatter.Status)
+	if status == "" {
+		return fmt.Errorf("invalid status %q (must be one of: %s)", frontMatter.Status, strings.Join(model.TaskStatuses, ", "))
+	}
+	frontMatter.Status = status
+
+	var err error
+	if frontMatter.DueDate, err = util.ParseDate(frontMatter.DueDate); err != nil {
+		return fmt.Errorf("due date: %w", err)
+	}
+	if frontMatter.ScheduledDate, err = util.ParseDate(frontMatter.ScheduledDate); err != nil {
+		return fmt.Errorf("scheduled date: %w", err)
+	}
+
+	if frontMatter.Priority != "" {
+		if model.PriorityRank(frontMatter.Priority) == len(model.TaskPriorities) {
+			return fmt.Errorf("invalid priority %q (must be one of: %s)", frontMatter.Priority, strings.Join(model.TaskPriorities, ", "))
+		}
+		frontMatter.Priority = strings.ToLower(frontMatter.Priority)
+	}
+
+	if frontMatter.Estimate != "" {
+		if _, err := time.ParseDuration(frontMatter.Estimate); err != nil {
+			return fmt.Errorf("invalid estimate %q (e.g. 30m, 1h30m)", frontMatter.Estimate)
+		}
+	}
+
+	if frontMatter.Recurrence != "" {
+		rule, err := util.ParseRecurrence(frontMatter.Recurrence)
+		if err != nil {
+			return fmt.Errorf("recurrence: %w", err)
+		}
+		frontMatter.Recurrence = rule.String()
+	}
+
+	var blockedBy []string
+	for _, blockerID := range frontMatter.BlockedBy {
+		if blockerID == "" || slices.Contains(blockedBy, blockerID) {
+			continue
+		}
+		if blockerID == taskID {
+			return fmt.Errorf("task %s cannot block itself", taskID)
+		}
+		if _, ok := findTask(tasks, blockerID); !ok {
+			return fmt.Errorf("blocking task %s not found", blockerID)
+		}
+		if path := blockerPath(tasks, blockerID, taskID); path != nil {
+			return fmt.Errorf("blocking task %s creates a cycle: %s", blockerID, strings.Join(append([]string{taskID}, path...), " → "))
+		}
+		blockedBy = append(blockedBy, blockerID)
+	}
+	frontMatter.BlockedBy = blockedBy
+
+	return nil
+}
+
+// blockerPath - fromID から blocked_by をたどって targetID に届くなら、その経路（fromID … targetID）を返す
+func blockerPath(tasks []model.Task, fromID, targetID string) []string {
+	visited := make(map[string]bool)
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == targetID {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		task, ok := findTask(tasks, id)
+		if !ok {
+			return nil
+		}
+		for _, blockerID := range task.BlockedBy {
+			if path := walk(blockerID); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(fromID)
+}
+
+// copyTaskFields - フロントマターの値を tasks.json 用の Task に反映
+func copyTaskFields(task *model.Task, frontMatter model.TaskFrontMatter) {
+	task.Status = frontMatter.Status
+	task.DueDate = frontMatter.DueDate
+	task.ScheduledDate = frontMatter.ScheduledDate
+	task.Priority = frontMatter.Priority
+	task.Estimate = frontMatter.Estimate
+	task.Recurrence = frontMatter.Recurrence
+	task.BlockedBy = frontMatter.BlockedBy
+	task.UID = frontMatter.UID
+}
+
+func findTask(tasks []model.Task, taskID string) (model.Task, bool) {
+	for _, task := range tasks {
+		if task.ID == taskID {
+			return task, true
+		}
+	}
+	return model.Task{}, false
+}
+
+// openBlockers - 未完了のブロッカータスクの ID を返す
+func openBlockers(task model.Task, tasks []model.Task) []string {
+	var open []string
+	for _, blockerID := range task.BlockedBy {
+		if blocker, ok := findTask(tasks, blockerID); ok && blocker.Status != model.TaskStatusDone {
+			open = append(open, blockerID)
+		}
+	}
+	return open
+}
+
+// updateTask - タスクのフロントマターを mutate で更新し、検証した上でノートと tasks.json / notes.json に書き戻す。
+// 繰り返しタスクが Done になった場合は次回分を作成して返す
+func updateTask(taskID string, mutate func(frontMatter *model.TaskFrontMatter), config model.Config) (*model.Task, error) {
+	return updateTaskNote(taskID, func(frontMatter *model.TaskFrontMatter, _ *string) {
+		mutate(frontMatter)
+	}, config)
+}
+
+// updateTaskNote - updateTask と同じだが、フロントマターと一緒に本文も書き換えられる
+func updateTaskNote(taskID string, mutate func(frontMatter *model.TaskFrontMatter, body *string), config model.Config) (*model.Task, error) {
+	tasks, tasksJsonPath, err := store.LoadTasks(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+
+	taskIndex := -1
+	for i := range tasks {
+		if tasks[i].ID == taskID {
+			taskIndex = i
+			break
+		}
+	}
+	if taskIndex == -1 {
+		return nil, fmt.Errorf("❌ Task with ID %s not found", taskID)
+	}
+
+	notePath := filepath.Join(config.ZettelDir, tasks[taskIndex].NoteID+".md")
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read task note: %w", err)
+	}
+	frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	// エディタで直接書き換えた場合も Done への変更を検出できるよう、変更前のステータスは tasks.json から取る
+	previousStatus := model.NormalizeTaskStatus(tasks[taskIndex].Status)
+	mutate(&frontMatter, &body)
+
+	if err := validateTaskFields(taskID, &frontMatter, tasks); err != nil {
+		return nil, fmt.Errorf("❌ %w", err)
+	}
+
+	copyTaskFields(&tasks[taskIndex], frontMatter)
+	if frontMatter.Status == model.TaskStatusDone && previousStatus != model.TaskStatusDone {
+		if blockers := openBlockers(tasks[taskIndex], tasks); len(blockers) > 0 {
+			return nil, fmt.Errorf("❌ Task %s is blocked by unfinished tasks: %s", taskID, strings.Join(blockers, ", "))
+		}
+	}
+
+	frontMatter.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	updatedContent := store.UpdateFrontMatter(&frontMatter, body)
+	if err := os.WriteFile(notePath, []byte(updatedContent), 0644); err != nil {
+		return nil, fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+
+	if err := store.SaveUpdatedJson(tasks, tasksJsonPath); err != nil {
+		return nil, fmt.Errorf("❌ Failed to update tasks.json: %w", err)
+	}
+
+	notes, notesJsonPath, err := store.LoadNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	for i := range notes {
+		if notes[i].ID == tasks[taskIndex].NoteID {
+			notes[i].Title = frontMatter.Title
+			notes[i].Content = body
+			notes[i].UpdatedAt = frontMatter.UpdatedAt
+			break
+		}
+	}
+	if err := store.SaveUpdatedJson(notes, notesJsonPath); err != nil {
+		return nil, fmt.Errorf("❌ Failed to update notes.json: %w", err)
+	}
+
+	if frontMatter.Status == model.TaskStatusDone && previousStatus != model.TaskStatusDone && frontMatter.Recurrence != "" {
+		return spawnNextOccurrence(tasks[taskIndex], frontMatter, body, config)
+	}
+	return nil, nil
+}
+
+// saveRejectedEdit - 反映できなかった編集内容を一時ディレクトリに保存し、そのパスを返す
+func saveRejectedEdit(notePath string) (string, error) {
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "ztl-edit-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(content); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// updateTaskStatus - ステータスのみを変更する（task update / task board 共通）
+func updateTaskStatus(taskID, status string, config model.Config) (*model.Task, error) {
+	return updateTask(taskID, func(frontMatter *model.TaskFrontMatter) {
+		frontMatter.Status = status
+	}, config)
+}
+
+// spawnNextOccurrence - 繰り返しタスクの次回分を作成
+func spawnNextOccurrence(task model.Task, frontMatter model.TaskFrontMatter, body string, config model.Config) (*model.Task, error) {
+	rule, err := util.ParseRecurrence(frontMatter.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Invalid recurrence: %w", err)
+	}
+
+	base := time.Now()
+	if frontMatter.DueDate != "" {
+		if due, err := time.ParseInLocation("2006-01-02", frontMatter.DueDate, time.Local); err == nil {
+			base = due
+		}
+	}
+	nextDue, ok := rule.Next(base)
+	if !ok {
+		log.Printf("🔁 Recurrence of task %s has ended", task.ID)
+		return nil, nil
+	}
+	if (rule.Freq == "MONTHLY" || rule.Freq == "YEARLY") && rule.ByMonthDay == 0 && nextDue.Day() != base.Day() {
+		// 月末で丸めた場合も、次回以降は元の日（31日など）に戻す
+		rule.ByMonthDay = base.Day()
+	}
+
+	fields := model.TaskFrontMatter{
+		Tags:       frontMatter.Tags,
+		Projects:   frontMatter.Projects,
+		DueDate:    nextDue.Format("2006-01-02"),
+		Priority:   frontMatter.Priority,
+		Estimate:   frontMatter.Estimate,
+		Recurrence: rule.Advance().String(),
+	}
+	// scheduled は due との差を保ったままずらす
+	if frontMatter.ScheduledDate != "" {
+		if scheduled, err := time.ParseInLocation("2006-01-02", frontMatter.ScheduledDate, time.Local); err == nil {
+			fields.ScheduledDate = scheduled.AddDate(0, 0, util.DaysBetween(base, nextDue)).Format("2006-01-02")
+		}
+	}
+
+	filePath, note, err := createNewTask(frontMatter.Title, fields, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// 本文（チェックリストなど）を引き継ぐ
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read task note: %w", err)
+	}
+	newFrontMatter, _, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+	updatedContent := store.UpdateFrontMatter(&newFrontMatter, resetCheckboxes(body))
+	if err := os.WriteFile(filePath, []byte(updatedContent), 0644); err != nil {
+		return nil, fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+	if err := syncNoteRelations(note.ID, "", updatedContent, config); err != nil {
+		log.Printf("⚠️ Failed to sync tags and links: %v", err)
+	}
+
+	projectNotes, _, err := store.LoadProjectNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+	for _, pn := range projectNotes {
+		if pn.NoteID == task.NoteID {
+			if err := store.InsertProjectNoteToJson(model.ProjectNote{ProjectID: pn.ProjectID, NoteID: note.ID}, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	for _, t := range tasks {
+		if t.NoteID == note.ID {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+// resetCheckboxes - 完了済みチェックボックスを未完了に戻す
+func resetCheckboxes(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, "- [x] ") || strings.HasPrefix(trimmed, "- [X] ") {
+			indent := line[:len(line)-len(trimmed)]
+			lines[i] = indent + "- [ ] " + trimmed[len("- [x] "):]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// taskSortLess - `--sort` に応じた比較関数。日付が未設定のものは最後に並べる
+func taskSortLess(key string, a, b model.Task, noteA, noteB model.Note) bool {
+	dateLess := func(x, y string) bool {
+		if x == "" {
+			return false
+		}
+		if y == "" {
+			return true
+		}
+		return x < y
+	}
+
+	switch key {
+	case "due":
+		return dateLess(a.DueDate, b.DueDate)
+	case "scheduled":
+		return dateLess(a.ScheduledDate, b.ScheduledDate)
+	case "priority":
+		return model.PriorityRank(a.Priority) < model.PriorityRank(b.Priority)
+	case "estimate":
+		da, _ := time.ParseDuration(a.Estimate)
+		db, _ := time.ParseDuration(b.Estimate)
+		return da < db
+	case "status":
+		return statusRank(a.Status) < statusRank(b.Status)
+	case "updated":
+		return noteA.UpdatedAt > noteB.UpdatedAt
+	default:
+		return noteA.CreatedAt < noteB.CreatedAt
+	}
+}
+
+func statusRank(status string) int {
+	for i, s := range model.TaskStatuses {
+		if s == model.NormalizeTaskStatus(status) {
+			return i
+		}
+	}
+	return len(model.TaskStatuses)
+}
+
+type taskRow struct {
+	Task model.Task
+	Note model.Note
+}
+
+// sortTaskRows - `--sort` のキーでタスク一覧を安定ソート
+func sortTaskRows(rows []taskRow, key string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return taskSortLess(key, rows[i].Task, rows[j].Task, rows[i].Note, rows[j].Note)
+	})
+}
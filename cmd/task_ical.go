@@ -0,0 +1,386 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var taskExportFormat string
+var taskExportFile string
+var taskExportProject string
+
+const icalStatusExtra = "X-ZTL-STATUS"
+
+// ステータスと VTODO の STATUS の対応。Waiting / On hold は X-ZTL-STATUS で保持する
+func taskStatusToICal(status string) string {
+	switch status {
+	case model.TaskStatusInProgress:
+		return "IN-PROCESS"
+	case model.TaskStatusDone:
+		return "COMPLETED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+func icalStatusToTask(todo util.VTodo) string {
+	if status := model.NormalizeTaskStatus(todo.Extra[icalStatusExtra]); status != "" {
+		return status
+	}
+	switch todo.Status {
+	case "IN-PROCESS":
+		return model.TaskStatusInProgress
+	case "COMPLETED":
+		return model.TaskStatusDone
+	case "CANCELLED":
+		return model.TaskStatusOnHold
+	default:
+		return model.TaskStatusNotStarted
+	}
+}
+
+// 優先度: high=1, medium=5, low=9（RFC 5545 の CUA 推奨値）
+func taskPriorityToICal(priority string) int {
+	switch priority {
+	case "high":
+		return 1
+	case "medium":
+		return 5
+	case "low":
+		return 9
+	}
+	return 0
+}
+
+func icalPriorityToTask(priority int) string {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return "high"
+	case priority == 5:
+		return "medium"
+	case priority >= 6 && priority <= 9:
+		return "low"
+	}
+	return ""
+}
+
+// taskUID - インポート元の UID があればそれを、なければノートIDから生成
+func taskUID(task model.Task) string {
+	if task.UID != "" {
+		return task.UID
+	}
+	return task.NoteID + "@ztl"
+}
+
+func icalTimestamp(dateTime string) string {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", dateTime, time.Local)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func exportTasksICal(w io.Writer, projectID string, config model.Config) (int, error) {
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	notes, _, err := store.LoadNotes(config)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	noteMap := make(map[string]model.Note)
+	for _, note := range notes {
+		noteMap[note.ID] = note
+	}
+
+	var inProject map[string]bool
+	if projectID != "" {
+		projectNotes, _, err := store.LoadProjectNotes(config)
+		if err != nil {
+			return 0, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+		}
+		inProject = make(map[string]bool)
+		for _, pn := range projectNotes {
+			if pn.ProjectID == projectID {
+				inProject[pn.NoteID] = true
+			}
+		}
+	}
+
+	var todos []util.VTodo
+	for _, task := range tasks {
+		note, exists := noteMap[task.NoteID]
+		if !exists || note.Deleted {
+			continue
+		}
+		if inProject != nil && !inProject[task.NoteID] {
+			continue
+		}
+
+		notePath := filepath.Join(config.ZettelDir, note.ID+".md")
+		if note.Archived {
+			notePath = filepath.Join(config.ArchiveDir, note.ID+".md")
+		}
+		var tags []string
+		body := note.Content
+		if content, err := os.ReadFile(notePath); err == nil {
+			if frontMatter, b, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content)); err == nil {
+				tags = frontMatter.Tags
+				body = b
+			}
+		}
+
+		todo := util.VTodo{
+			UID:          taskUID(task),
+			Summary:      note.Title,
+			Description:  strings.TrimSpace(body),
+			Status:       taskStatusToICal(task.Status),
+			Due:          task.DueDate,
+			Start:        task.ScheduledDate,
+			Priority:     taskPriorityToICal(task.Priority),
+			Categories:   tags,
+			RRule:        task.Recurrence,
+			Created:      icalTimestamp(note.CreatedAt),
+			LastModified: icalTimestamp(note.UpdatedAt),
+			Extra:        map[string]string{icalStatusExtra: task.Status},
+		}
+		todos = append(todos, todo)
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	if err := util.WriteICalendar(w, todos, stamp); err != nil {
+		return 0, fmt.Errorf("❌ Failed to write iCalendar: %w", err)
+	}
+	return len(todos), nil
+}
+
+// importTaskFromICal - VTODO を 1 件取り込む。UID が一致するタスクがあれば更新、なければ作成
+func importTaskFromICal(todo util.VTodo, config model.Config) (string, error) {
+	tasks, _, err := store.LoadTasks(config)
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+
+	recurrence := todo.RRule
+	if recurrence != "" {
+		rule, ignored, err := util.ParseRecurrenceLenient(recurrence)
+		if err != nil {
+			return "", fmt.Errorf("❌ %s: recurrence: %w", todo.UID, err)
+		}
+		if len(ignored) > 0 {
+			log.Printf("⚠️ %s: Ignored unsupported RRULE parts: %s", todo.UID, strings.Join(ignored, ";"))
+		}
+		recurrence = rule.String()
+	}
+
+	fields := model.TaskFrontMatter{
+		Title:         todo.Summary,
+		Tags:          todo.Categories,
+		Status:        icalStatusToTask(todo),
+		DueDate:       todo.Due,
+		ScheduledDate: todo.Start,
+		Priority:      icalPriorityToTask(todo.Priority),
+		Recurrence:    recurrence,
+		UID:           todo.UID,
+	}
+	if err := validateTaskFields("", &fields, tasks); err != nil {
+		return "", fmt.Errorf("❌ %s: %w", todo.UID, err)
+	}
+
+	for _, task := range tasks {
+		if taskUID(task) != todo.UID {
+			continue
+		}
+
+		notes, _, err := store.LoadNotes(config)
+		if err != nil {
+			return "", fmt.Errorf("❌ Failed to load notes.json: %w", err)
+		}
+		note, _ := findNoteByRef(notes, task.NoteID)
+		notePath := filepath.Join(config.ZettelDir, note.ID+".md")
+		originalContent, err := os.ReadFile(notePath)
+		if err != nil {
+			return "", fmt.Errorf("❌ Failed to read task note: %w", err)
+		}
+		frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](string(originalContent))
+		if err != nil {
+			return "", fmt.Errorf("❌ Error parsing front matter: %w", err)
+		}
+
+		// DESCRIPTION がない場合は作成時と同じく本文を残す
+		description := strings.TrimSpace(body)
+		if todo.Description != "" {
+			description = strings.TrimSpace(todo.Description)
+		}
+
+		if note.Title == fields.Title && task.Status == fields.Status && task.DueDate == fields.DueDate &&
+			task.ScheduledDate == fields.ScheduledDate && task.Priority == fields.Priority && task.Recurrence == fields.Recurrence &&
+			slices.Equal(frontMatter.Tags, fields.Tags) && strings.TrimSpace(body) == description {
+			return "unchanged", nil
+		}
+
+		_, err = updateTaskNote(task.ID, func(frontMatter *model.TaskFrontMatter, body *string) {
+			frontMatter.Title = todo.Summary
+			frontMatter.Tags = fields.Tags
+			frontMatter.Status = fields.Status
+			frontMatter.DueDate = fields.DueDate
+			frontMatter.ScheduledDate = fields.ScheduledDate
+			frontMatter.Priority = fields.Priority
+			frontMatter.Recurrence = fields.Recurrence
+			if todo.Description != "" {
+				*body = todo.Description
+			}
+		}, config)
+		if err != nil {
+			return "", err
+		}
+
+		updatedContent, err := os.ReadFile(notePath)
+		if err != nil {
+			return "", fmt.Errorf("❌ Failed to read task note: %w", err)
+		}
+		if err := syncNoteRelations(note.ID, string(originalContent), string(updatedContent), config); err != nil {
+			log.Printf("⚠️ Failed to sync tags and links: %v", err)
+		}
+		return "updated", nil
+	}
+
+	if todo.Summary == "" {
+		return "", fmt.Errorf("❌ %s: VTODO has no SUMMARY", todo.UID)
+	}
+
+	filePath, note, err := createNewTask(todo.Summary, fields, config)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to read task note: %w", err)
+	}
+	if todo.Description != "" {
+		frontMatter, _, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content))
+		if err != nil {
+			return "", fmt.Errorf("❌ Error parsing front matter: %w", err)
+		}
+		content = []byte(store.UpdateFrontMatter(&frontMatter, todo.Description))
+		if err := os.WriteFile(filePath, content, 0644); err != nil {
+			return "", fmt.Errorf("❌ Error writing updated note file: %w", err)
+		}
+	}
+	if err := syncNoteRelations(note.ID, "", string(content), config); err != nil {
+		log.Printf("⚠️ Failed to sync tags and links: %v", err)
+	}
+
+	return "created", nil
+}
+
+var exportTaskCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tasks (iCalendar VTODO)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if taskExportFormat != "ics" {
+			log.Fatalf("❌ Unsupported format: %s. Must be 'ics'", taskExportFormat)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		w := io.Writer(os.Stdout)
+		if taskExportFile != "" {
+			f, err := os.Create(taskExportFile)
+			if err != nil {
+				log.Fatalf("❌ Failed to create %s: %v", taskExportFile, err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		count, err := exportTasksICal(w, taskExportProject, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if taskExportFile != "" {
+			fmt.Printf("✅ Exported %d tasks to %s\n", count, taskExportFile)
+		}
+	},
+}
+
+var importTaskCmd = &cobra.Command{
+	Use:   "import [file.ics]",
+	Short: "Import tasks from an iCalendar file",
+	Long: `Import VTODO entries from an iCalendar file as task notes.
+
+Tasks are matched by UID, so importing the same file again updates
+the existing tasks (title, body, tags, dates and recurrence) instead
+of creating duplicates. RRULE parts that ztl does not support (WKST,
+BYSETPOS, ...) are ignored with a warning.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.Fatalf("❌ Failed to open %s: %v", args[0], err)
+		}
+		defer f.Close()
+
+		todos, err := util.ParseICalendar(f)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		counts := make(map[string]int)
+		failed := 0
+		for _, todo := range todos {
+			if todo.UID == "" {
+				log.Printf("⚠️ Skipping VTODO without UID: %s", todo.Summary)
+				failed++
+				continue
+			}
+			result, err := importTaskFromICal(todo, *config)
+			if err != nil {
+				log.Printf("%v", err)
+				failed++
+				continue
+			}
+			counts[result]++
+		}
+
+		fmt.Printf("✅ Imported %d VTODOs: %d created, %d updated, %d unchanged, %d failed\n",
+			len(todos), counts["created"], counts["updated"], counts["unchanged"], failed)
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(exportTaskCmd)
+	taskCmd.AddCommand(importTaskCmd)
+	exportTaskCmd.Flags().StringVar(&taskExportFormat, "format", "ics", "Export format (ics)")
+	exportTaskCmd.Flags().StringVarP(&taskExportFile, "file", "f", "", "Write to a file instead of stdout")
+	exportTaskCmd.Flags().StringVar(&taskExportProject, "project", "", "Export only tasks in the project (e.g. p001)")
+}
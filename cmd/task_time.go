@@ -0,0 +1,467 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+	"github.com/nakachan-ing/ztl-cli/internal/store"
+	"github.com/nakachan-ing/ztl-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var timeEntryNote string
+var timeLogTask string
+var timeLogProjects []string
+var timeLogFrom string
+var timeLogTo string
+
+const timeLogHeading = "## Time log"
+
+func parseTimestamp(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+}
+
+// entryDuration - エントリの経過時間。計測中のものは now までを数える
+func entryDuration(entry model.TimeEntry, now time.Time) time.Duration {
+	start, err := parseTimestamp(entry.StartAt)
+	if err != nil {
+		return 0
+	}
+	end := now
+	if entry.EndAt != "" {
+		if end, err = parseTimestamp(entry.EndAt); err != nil {
+			return 0
+		}
+	}
+	return end.Sub(start)
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
+
+func isoWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// appendTimeLog - タスクノートの `## Time log` セクションに記録を追記（なければ作成）
+func appendTimeLog(task model.Task, entry model.TimeEntry, config model.Config) error {
+	notePath := filepath.Join(config.ZettelDir, task.NoteID+".md")
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read task note: %w", err)
+	}
+	frontMatter, body, err := store.ParseFrontMatter[model.TaskFrontMatter](string(content))
+	if err != nil {
+		return fmt.Errorf("❌ Error parsing front matter: %w", err)
+	}
+
+	start, _ := parseTimestamp(entry.StartAt)
+	end, _ := parseTimestamp(entry.EndAt)
+	endLabel := end.Format("15:04")
+	if end.Format("2006-01-02") != start.Format("2006-01-02") {
+		endLabel = end.Format("2006-01-02 15:04")
+	}
+	line := fmt.Sprintf("- %s %s–%s (%s)", start.Format("2006-01-02"), start.Format("15:04"), endLabel, formatDuration(end.Sub(start)))
+	if entry.Note != "" {
+		line += " " + entry.Note
+	}
+
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	sectionStart := -1
+	for i, l := range lines {
+		if strings.TrimSpace(l) == timeLogHeading {
+			sectionStart = i
+			break
+		}
+	}
+
+	if sectionStart == -1 {
+		lines = append(lines, "", timeLogHeading, "", line)
+	} else {
+		// セクションの終わり（次の見出しの直前の空行を除いた位置）に挿入
+		insertAt := len(lines)
+		for i := sectionStart + 1; i < len(lines); i++ {
+			if strings.HasPrefix(lines[i], "#") {
+				insertAt = i
+				break
+			}
+		}
+		for insertAt > sectionStart+1 && strings.TrimSpace(lines[insertAt-1]) == "" {
+			insertAt--
+		}
+		lines = append(lines[:insertAt], append([]string{line}, lines[insertAt:]...)...)
+	}
+	body = strings.Join(lines, "\n")
+
+	frontMatter.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	if err := os.WriteFile(notePath, []byte(store.UpdateFrontMatter(&frontMatter, body)), 0644); err != nil {
+		return fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+
+	notes, notesJsonPath, err := store.LoadNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	for i := range notes {
+		if notes[i].ID == task.NoteID {
+			notes[i].Content = body
+			notes[i].UpdatedAt = frontMatter.UpdatedAt
+			break
+		}
+	}
+	return store.SaveUpdatedJson(notes, notesJsonPath)
+}
+
+// taskProjectNames - ノートID → 所属プロジェクト名
+func taskProjectNames(notes []model.Note, config model.Config) (map[string][]string, error) {
+	projects, _, err := store.LoadProjects(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load projects.json: %w", err)
+	}
+	projectNotes, _, err := store.LoadProjectNotes(config)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+
+	projectName := make(map[string]string)
+	for _, p := range projects {
+		projectName[p.ProjectID] = p.Name
+	}
+
+	result := make(map[string][]string)
+	for _, pn := range projectNotes {
+		if name, ok := projectName[pn.ProjectID]; ok && !slices.Contains(result[pn.NoteID], name) {
+			result[pn.NoteID] = append(result[pn.NoteID], name)
+		}
+	}
+	for _, note := range notes {
+		for _, name := range note.Projects {
+			if !slices.Contains(result[note.ID], name) {
+				result[note.ID] = append(result[note.ID], name)
+			}
+		}
+	}
+	return result, nil
+}
+
+type timeTotal struct {
+	Key      string        `json:"key"`
+	Label    string        `json:"label,omitempty"`
+	Duration time.Duration `json:"-"`
+	Minutes  int           `json:"minutes"`
+}
+
+func sortedTotals(totals map[string]time.Duration, labels map[string]string, byKey bool) []timeTotal {
+	var result []timeTotal
+	for key, d := range totals {
+		result = append(result, timeTotal{Key: key, Label: labels[key], Duration: d, Minutes: int(d.Round(time.Minute).Minutes())})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if byKey || result[i].Duration == result[j].Duration {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Duration > result[j].Duration
+	})
+	return result
+}
+
+func renderTotals(title string, totals []timeTotal) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleLight)
+	t.SetTitle(title)
+	t.Style().Format.Footer = text.FormatDefault
+	var sum time.Duration
+	for _, total := range totals {
+		key := total.Key
+		if total.Label != "" {
+			key += " " + total.Label
+		}
+		t.AppendRow(table.Row{key, formatDuration(total.Duration)})
+		sum += total.Duration
+	}
+	t.AppendFooter(table.Row{"Total", formatDuration(sum)})
+	t.Render()
+}
+
+var startTaskCmd = &cobra.Command{
+	Use:   "start [taskID]",
+	Short: "Start a timer for a task",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		tasks, _, err := store.LoadTasks(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load tasks.json: %v", err)
+		}
+		task, found := findTask(tasks, taskID)
+		if !found {
+			log.Fatalf("❌ Task with ID %s not found", taskID)
+		}
+
+		entries, entriesJsonPath, err := store.LoadTimeEntries(*config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if i, running := store.RunningTimeEntry(entries); running {
+			log.Fatalf("❌ A timer is already running for %s (since %s). Run 'ztl task stop' first", entries[i].TaskID, entries[i].StartAt)
+		}
+
+		entry := model.TimeEntry{
+			ID:      store.GetNextTimeEntryID(entries),
+			TaskID:  taskID,
+			StartAt: time.Now().Format("2006-01-02 15:04:05"),
+			Note:    timeEntryNote,
+		}
+		entries = append(entries, entry)
+		if err := store.SaveUpdatedJson(entries, entriesJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update time_entries.json: %v", err)
+		}
+
+		// 未着手のタスクは In progress にする
+		if model.NormalizeTaskStatus(task.Status) == model.TaskStatusNotStarted || task.Status == "" {
+			if _, err := updateTaskStatus(taskID, model.TaskStatusInProgress, *config); err != nil {
+				log.Printf("⚠️ Failed to update task status: %v", err)
+			}
+		}
+
+		fmt.Printf("⏱️ Timer started for %s at %s\n", taskID, entry.StartAt[11:16])
+	},
+}
+
+var stopTaskCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		entries, entriesJsonPath, err := store.LoadTimeEntries(*config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		i, running := store.RunningTimeEntry(entries)
+		if !running {
+			log.Fatalf("❌ No timer is running")
+		}
+
+		entries[i].EndAt = time.Now().Format("2006-01-02 15:04:05")
+		if timeEntryNote != "" {
+			entries[i].Note = strings.TrimSpace(entries[i].Note + " " + timeEntryNote)
+		}
+		if err := store.SaveUpdatedJson(entries, entriesJsonPath); err != nil {
+			log.Fatalf("❌ Failed to update time_entries.json: %v", err)
+		}
+
+		tasks, _, err := store.LoadTasks(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load tasks.json: %v", err)
+		}
+		if task, found := findTask(tasks, entries[i].TaskID); found {
+			if err := appendTimeLog(task, entries[i], *config); err != nil {
+				log.Printf("⚠️ Failed to append time log to task note: %v", err)
+			}
+		}
+
+		fmt.Printf("⏹️ Timer stopped for %s: %s\n", entries[i].TaskID, formatDuration(entryDuration(entries[i], time.Now())))
+	},
+}
+
+var logTaskCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show time entries with totals per task, project and week",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateOutputFormat(); err != nil {
+			fatal(err)
+		}
+
+		config, err := store.LoadConfig()
+		if err != nil {
+			log.Printf("❌ Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		from, err := util.ParseDate(timeLogFrom)
+		if err != nil {
+			log.Fatalf("❌ --from: %v", err)
+		}
+		to, err := util.ParseDate(timeLogTo)
+		if err != nil {
+			log.Fatalf("❌ --to: %v", err)
+		}
+
+		entries, _, err := store.LoadTimeEntries(*config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		tasks, _, err := store.LoadTasks(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load tasks.json: %v", err)
+		}
+		notes, _, err := store.LoadNotes(*config)
+		if err != nil {
+			log.Fatalf("❌ Failed to load notes.json: %v", err)
+		}
+		projectNames, err := taskProjectNames(notes, *config)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		inProjects, err := projectFilterIDs(timeLogProjects, notes, *config)
+		if err != nil {
+			fatal(err)
+		}
+
+		noteTitle := make(map[string]string)
+		for _, note := range notes {
+			noteTitle[note.ID] = note.Title
+		}
+		taskNote := make(map[string]string)
+		for _, task := range tasks {
+			taskNote[task.ID] = task.NoteID
+		}
+
+		now := time.Now()
+		byTask := make(map[string]time.Duration)
+		byProject := make(map[string]time.Duration)
+		byWeek := make(map[string]time.Duration)
+		taskLabels := make(map[string]string)
+		var shown []model.TimeEntry
+
+		for _, entry := range entries {
+			if timeLogTask != "" && entry.TaskID != timeLogTask {
+				continue
+			}
+			day := entry.StartAt[:min(10, len(entry.StartAt))]
+			if !util.IsWithinDateRange(day, from, to) {
+				continue
+			}
+			if inProjects != nil && !inProjects[taskNote[entry.TaskID]] {
+				continue
+			}
+			projects := projectNames[taskNote[entry.TaskID]]
+
+			d := entryDuration(entry, now)
+			byTask[entry.TaskID] += d
+			taskLabels[entry.TaskID] = noteTitle[taskNote[entry.TaskID]]
+			if len(projects) == 0 {
+				projects = []string{"(no project)"}
+			}
+			for _, p := range projects {
+				byProject[p] += d
+			}
+			if start, err := parseTimestamp(entry.StartAt); err == nil {
+				byWeek[isoWeek(start)] += d
+			}
+			shown = append(shown, entry)
+		}
+
+		taskTotals := sortedTotals(byTask, taskLabels, false)
+		projectTotals := sortedTotals(byProject, nil, false)
+		weekTotals := sortedTotals(byWeek, nil, true)
+
+		switch outputFormat {
+		case "csv":
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"id", "task_id", "title", "start_at", "end_at", "minutes", "note"})
+			for _, entry := range shown {
+				minutes := int(entryDuration(entry, now).Round(time.Minute).Minutes())
+				w.Write([]string{entry.ID, entry.TaskID, noteTitle[taskNote[entry.TaskID]],
+					entry.StartAt, entry.EndAt, fmt.Sprint(minutes), entry.Note})
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				fatal(err)
+			}
+			return
+		case "json":
+			report := struct {
+				Entries  []model.TimeEntry `json:"entries"`
+				Tasks    []timeTotal       `json:"tasks"`
+				Projects []timeTotal       `json:"projects"`
+				Weeks    []timeTotal       `json:"weeks"`
+			}{shown, taskTotals, projectTotals, weekTotals}
+			jsonBytes, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				log.Fatalf("❌ Failed to convert to JSON: %v", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return
+		}
+
+		fmt.Println(strings.Repeat("=", 30))
+		fmt.Printf("Time log: %v entries shown\n", len(shown))
+		fmt.Println(strings.Repeat("=", 30))
+		if len(shown) == 0 {
+			return
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleDouble)
+		t.AppendHeader(table.Row{
+			text.FgGreen.Sprintf("Entry"), text.FgGreen.Sprintf("Task"), text.FgGreen.Sprintf("%s", text.Bold.Sprintf("Title")),
+			text.FgGreen.Sprintf("Start"), text.FgGreen.Sprintf("End"), text.FgGreen.Sprintf("Duration"), text.FgGreen.Sprintf("Note"),
+		})
+		for _, entry := range shown {
+			end := entry.EndAt
+			if end == "" {
+				end = text.FgHiYellow.Sprint("running")
+			}
+			t.AppendRow(table.Row{
+				entry.ID, entry.TaskID, noteTitle[taskNote[entry.TaskID]],
+				entry.StartAt, end, formatDuration(entryDuration(entry, now)), entry.Note,
+			})
+		}
+		t.Render()
+
+		renderTotals("By task", taskTotals)
+		renderTotals("By project", projectTotals)
+		renderTotals("By week", weekTotals)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(startTaskCmd)
+	taskCmd.AddCommand(stopTaskCmd)
+	taskCmd.AddCommand(logTaskCmd)
+	startTaskCmd.Flags().StringVarP(&timeEntryNote, "message", "m", "", "Note for the time entry")
+	stopTaskCmd.Flags().StringVarP(&timeEntryNote, "message", "m", "", "Note for the time entry")
+	logTaskCmd.Flags().StringVar(&timeLogTask, "task", "", "Show only entries for the task")
+	logTaskCmd.Flags().StringSliceVar(&timeLogProjects, "project", []string{}, "Show only entries for tasks in the project ID or name (repeatable, matches any)")
+	logTaskCmd.Flags().StringVar(&timeLogFrom, "from", "", "Show only entries started on or after the date (YYYY-MM-DD)")
+	logTaskCmd.Flags().StringVar(&timeLogTo, "to", "", "Show only entries started on or before the date (YYYY-MM-DD)")
+}
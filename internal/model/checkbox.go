@@ -0,0 +1,9 @@
+package model
+
+// Checkbox はノート本文中の `- [ ]` / `- [x]` 形式のチェックボックス
+type Checkbox struct {
+	NoteID string `json:"note_id"` // yyyymmddhhmmss
+	Line   int    `json:"line"`    // ファイル先頭からの行番号（1始まり）
+	Text   string `json:"text"`
+	Done   bool   `json:"done"`
+}
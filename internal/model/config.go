@@ -1,11 +1,12 @@
 package model
 
 type Config struct {
-	ZettelDir   string `yaml:"zettel_dir"`
-	Editor      string `yaml:"editor"`
-	JsonDataDir string `yaml:"json_data_dir"`
-	ArchiveDir  string `yaml:"archive_dir"`
-	Backup      struct {
+	ZettelDir     string `yaml:"zettel_dir"`
+	Editor        string `yaml:"editor"`
+	JsonDataDir   string `yaml:"json_data_dir"`
+	ArchiveDir    string `yaml:"archive_dir"`
+	CitationStyle string `yaml:"citation_style"` // apa, chicago, ieee
+	Backup        struct {
 		Enable    bool   `yaml:"enable"`
 		Frequency int    `yaml:"frequency"`
 		Retention int    `yaml:"retention"`
@@ -18,21 +19,28 @@ type Config struct {
 	}
 	Sync struct {
 		Enable     bool     `yaml:"enable"`
-		Platform   string   `yaml:"platform"`
+		Platform   string   `yaml:"platform"` // s3, minio, local
 		Bucket     string   `yaml:"bucket"`
 		AWSProfile string   `yaml:"aws_profile"`
 		AWSRegion  string   `yaml:"aws_region"`
-		Include    []string `yaml:"include"`
-		Exclude    []string `yaml:"exclude"`
+		Endpoint   string   `yaml:"endpoint"`   // MinIO など S3 互換ストレージの URL
+		PathStyle  bool     `yaml:"path_style"` // バケットをホスト名ではなくパスで指定する（MinIO）
+		Path       string   `yaml:"path"`       // platform: local の同期先ディレクトリ（NFS のマウント先など）
+		Encryption string   `yaml:"encryption"` // aes-gcm: アップロード前に暗号化する
+		KeyFile    string   `yaml:"key_file"`   // 暗号化の鍵ファイル（未指定ならパスフレーズ）
+		Workers    int      `yaml:"workers"`    // 並列に転送するファイル数（既定 4）
+		Include    []string `yaml:"include"`    // 同期するファイルの glob（notes/**/*.md、*.json など。空なら全て）
+		Exclude    []string `yaml:"exclude"`    // 同期しないファイルの glob（include より優先）
 	}
 }
 
 func DefaultConfig() Config {
 	return Config{
-		ZettelDir:   "~/Zettelkasten",
-		Editor:      "vim",
-		JsonDataDir: "~/.config/ztl/data",
-		ArchiveDir:  "~/.config/ztl/archive",
+		ZettelDir:     "~/Zettelkasten",
+		Editor:        "vim",
+		JsonDataDir:   "~/.config/ztl/data",
+		ArchiveDir:    "~/.config/ztl/archive",
+		CitationStyle: "apa",
 		Backup: struct {
 			Enable    bool   `yaml:"enable"`
 			Frequency int    `yaml:"frequency"`
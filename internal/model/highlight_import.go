@@ -0,0 +1,10 @@
+package model
+
+// HighlightImport は取り込んだハイライト（章単位の場合は章）とノートの対応。再取り込み時の重複防止に使う
+type HighlightImport struct {
+	Key        string `json:"key"`       // ソース ID・位置・本文（章単位は章名）から作るハッシュ
+	SourceID   string `json:"source_id"` // s001...
+	NoteID     string `json:"note_id"`   // yyyymmddhhmmss
+	Hash       string `json:"hash"`      // 最後に書き込んだハイライト部分のハッシュ（変更検出用）
+	ImportedAt string `json:"imported_at"`
+}
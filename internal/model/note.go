@@ -1,16 +1,46 @@
 package model
 
+// ProjectMigratable は旧形式の project_name を projects に移行できる型
+type ProjectMigratable interface {
+	MigrateProjects() bool
+}
+
+// migrateProjectName は projectName を projects に追加し、空にした値を返す
+func migrateProjectName(projects []string, projectName string) ([]string, string, bool) {
+	if projectName == "" {
+		return projects, "", false
+	}
+	for _, p := range projects {
+		if p == projectName {
+			return projects, "", true
+		}
+	}
+	return append(projects, projectName), "", true
+}
+
+// HasProject はノートがプロジェクトに属しているか判定
+func HasProject(projects []string, name string) bool {
+	for _, p := range projects {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
 type Note struct {
-	ID          string `json:"id"`     // yyyymmddhhmmss
-	SeqID       string `json:"seq_id"` // n001...
-	Title       string `json:"title"`
-	NoteType    string `json:"note_type"` // fleeting, permanent, literature
-	ProjectName string `json:"project_name"`
-	Content     string `json:"content"`
-	CreatedAt   string `json:"created_at"` // yyyy-mm-dd hh:mm:ss
-	UpdatedAt   string `json:"updated_at"` // yyyy-mm-dd hh:mm:ss
-	Archived    bool   `json:"archived"`
-	Deleted     bool   `json:"deleted"`
+	ID          string   `json:"id"`     // yyyymmddhhmmss
+	SeqID       string   `json:"seq_id"` // n001...
+	Title       string   `json:"title"`
+	NoteType    string   `json:"note_type"` // fleeting, permanent, literature
+	Projects    []string `json:"projects"`
+	ProjectName string   `json:"project_name,omitempty"` // 旧形式（読み込み時に Projects へ移行）
+	Content     string   `json:"content"`
+	CreatedAt   string   `json:"created_at"` // yyyy-mm-dd hh:mm:ss
+	UpdatedAt   string   `json:"updated_at"` // yyyy-mm-dd hh:mm:ss
+	Archived    bool     `json:"archived"`
+	Deleted     bool     `json:"deleted"`
+	Processed   bool     `json:"processed"` // fleeting ノートを inbox で処理済みか
 }
 
 type NoteFrontMatter struct {
@@ -19,7 +49,8 @@ type NoteFrontMatter struct {
 	NoteType    string   `yaml:"note_type"`
 	Tags        []string `yaml:"tags"`
 	Links       []string `yaml:"links"`
-	ProjectName string   `yaml:"project_name"`
+	Projects    []string `yaml:"projects"`
+	ProjectName string   `yaml:"project_name,omitempty"` // 旧形式（読み込み時に projects へ移行）
 	CreatedAt   string   `yaml:"created_at"`
 	UpdatedAt   string   `yaml:"updated_at"`
 	Archived    bool     `yaml:"archived"`
@@ -41,3 +72,16 @@ func (n *NoteFrontMatter) SetArchived() {
 func (n *NoteFrontMatter) ResetArchived() {
 	n.Archived = false
 }
+
+// MigrateProjects は旧形式の project_name を projects に移す。移行した場合 true を返す
+func (n *Note) MigrateProjects() bool {
+	var migrated bool
+	n.Projects, n.ProjectName, migrated = migrateProjectName(n.Projects, n.ProjectName)
+	return migrated
+}
+
+func (n *NoteFrontMatter) MigrateProjects() bool {
+	var migrated bool
+	n.Projects, n.ProjectName, migrated = migrateProjectName(n.Projects, n.ProjectName)
+	return migrated
+}
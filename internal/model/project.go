@@ -1,6 +1,33 @@
 package model
 
+import "strings"
+
+const (
+	ProjectStatusActive   = "active"
+	ProjectStatusOnHold   = "on-hold"
+	ProjectStatusDone     = "done"
+	ProjectStatusArchived = "archived"
+)
+
+var ProjectStatuses = []string{ProjectStatusActive, ProjectStatusOnHold, ProjectStatusDone, ProjectStatusArchived}
+
 type Project struct {
-	ProjectID string `json:"project_id"` // p001...
-	Name      string `json:"name"`
+	ProjectID   string `json:"project_id"` // p001...
+	Name        string `json:"name"`
+	Status      string `json:"status"` // active, on-hold, done, archived
+	Description string `json:"description"`
+	DueDate     string `json:"due_date"`   // yyyy-mm-dd
+	CreatedAt   string `json:"created_at"` // yyyy-mm-dd hh:mm:ss
+	UpdatedAt   string `json:"updated_at"` // yyyy-mm-dd hh:mm:ss
+}
+
+// NormalizeProjectStatus は "On hold" などの表記揺れを正規の表記に揃える。不正な値は空文字を返す
+func NormalizeProjectStatus(status string) string {
+	status = strings.ReplaceAll(strings.TrimSpace(status), " ", "-")
+	for _, s := range ProjectStatuses {
+		if strings.EqualFold(s, status) {
+			return s
+		}
+	}
+	return ""
 }
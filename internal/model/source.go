@@ -1,12 +1,145 @@
 package model
 
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	SourceStatusToRead  = "to-read"
+	SourceStatusReading = "reading"
+	SourceStatusRead    = "read"
+)
+
+var SourceStatuses = []string{SourceStatusToRead, SourceStatusReading, SourceStatusRead}
+
+// Person は著者・編者。団体名は姓と名に分けず Literal に入れる（旧形式の Family のみの団体名も読める）
+type Person struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"` // World Health Organization など
+}
+
 type Source struct {
-	SourceID   string `json:"source_id"`   // s001...
-	SourceType string `json:"source_type"` // book, web, paper, video
-	Title      string `json:"title"`
-	Author     string `json:"author"`
-	Publisher  string `json:"publisher"`
-	Year       int    `json:"year"`
-	ISBN       string `json:"isbn"`
-	URL        string `json:"url"`
+	SourceID    string   `json:"source_id"`   // s001...
+	SourceType  string   `json:"source_type"` // book, web, paper, video
+	Title       string   `json:"title"`
+	Authors     []Person `json:"authors"`
+	Author      string   `json:"author,omitempty"` // 旧形式（"Family, Given; Family, Given"）。読み込み時に authors へ移行
+	Editors     []Person `json:"editors"`
+	Publisher   string   `json:"publisher"`
+	Journal     string   `json:"journal"` // 掲載誌・書名（論文・章の場合）
+	Volume      string   `json:"volume"`
+	Issue       string   `json:"issue"`
+	Pages       string   `json:"pages"` // 123-145
+	Year        int      `json:"year"`
+	ISBN        string   `json:"isbn"`
+	DOI         string   `json:"doi"`
+	ArXiv       string   `json:"arxiv"` // 2101.00001
+	URL         string   `json:"url"`
+	CitationKey string   `json:"citation_key"` // smith2020deep...
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status"`      // to-read, reading, read
+	Rating      int      `json:"rating"`      // 0 (未評価) - 5
+	StartedAt   string   `json:"started_at"`  // yyyy-mm-dd
+	FinishedAt  string   `json:"finished_at"` // yyyy-mm-dd
+	CreatedAt   string   `json:"created_at"`  // yyyy-mm-dd hh:mm:ss
+}
+
+// LiteralName は "{World Health Organization}" のように波括弧で囲まれた名前（団体名）なら中身を返す
+func LiteralName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if len(name) < 2 || name[0] != '{' || name[len(name)-1] != '}' || strings.ContainsAny(name[1:len(name)-1], "{}") {
+		return "", false
+	}
+	return strings.TrimSpace(name[1 : len(name)-1]), true
+}
+
+// ParsePerson は "Family, Given"、"Given Family" または団体名 "{Name}" を Person に変換する
+func ParsePerson(name string) Person {
+	name = strings.Join(strings.Fields(name), " ")
+	if literal, ok := LiteralName(name); ok {
+		return Person{Literal: literal}
+	}
+	if family, given, ok := strings.Cut(name, ","); ok {
+		return Person{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+	}
+	parts := strings.Fields(name)
+	if len(parts) <= 1 {
+		return Person{Family: name}
+	}
+	return Person{Family: parts[len(parts)-1], Given: strings.Join(parts[:len(parts)-1], " ")}
+}
+
+// ParsePeople は "Family, Given; Family, Given" を Person の一覧に変換する
+func ParsePeople(names string) []Person {
+	var people []Person
+	for _, name := range strings.Split(names, ";") {
+		if strings.TrimSpace(name) != "" {
+			people = append(people, ParsePerson(name))
+		}
+	}
+	return people
+}
+
+// String は "Family, Given"（団体名は "{Name}"）を返す。ParsePerson で元に戻せる
+func (p Person) String() string {
+	if p.Literal != "" {
+		return "{" + p.Literal + "}"
+	}
+	if p.Given == "" {
+		return p.Family
+	}
+	return p.Family + ", " + p.Given
+}
+
+// Surname は一覧表示や引用キーに使う姓（団体名はその名前）
+func (p Person) Surname() string {
+	if p.Literal != "" {
+		return p.Literal
+	}
+	return p.Family
+}
+
+// UnmarshalJSON は旧形式の文字列 "Family, Given" も受け付ける
+func (p *Person) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*p = ParsePerson(name)
+		return nil
+	}
+	type person Person
+	return json.Unmarshal(data, (*person)(p))
+}
+
+// JoinPeople は著者一覧を "Family, Given; Family, Given" の形式で返す
+func JoinPeople(people []Person) string {
+	var names []string
+	for _, p := range people {
+		names = append(names, p.String())
+	}
+	return strings.Join(names, "; ")
+}
+
+// MigrateAuthors は旧形式の author を authors に移す。移行した場合 true を返す
+func (s *Source) MigrateAuthors() bool {
+	if s.Author == "" {
+		return false
+	}
+	if len(s.Authors) == 0 {
+		s.Authors = ParsePeople(s.Author)
+	}
+	s.Author = ""
+	return true
+}
+
+// NormalizeSourceStatus は "To read" などの表記揺れを正規の表記に揃える。不正な値は空文字を返す
+func NormalizeSourceStatus(status string) string {
+	status = strings.ReplaceAll(strings.TrimSpace(status), " ", "-")
+	for _, s := range SourceStatuses {
+		if strings.EqualFold(s, status) {
+			return s
+		}
+	}
+	return ""
 }
@@ -1,6 +1,91 @@
 package model
 
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 type SourceNote struct {
-	SourceID string `json:"source_id"` // s001...
-	NoteID   string `json:"note_id"`   // yyyymmddhhmmss
+	SourceID string `json:"source_id"`         // s001...
+	NoteID   string `json:"note_id"`           // yyyymmddhhmmss
+	Locator  string `json:"locator,omitempty"` // p. 42, pp. 42-45, ch. 3, loc. 1234, 1:02:03
+}
+
+var (
+	pagePattern = regexp.MustCompile(`^\d+(\s*[-–]\s*\d+)?$`)
+	timePattern = regexp.MustCompile(`^\d+(:\d{1,2}){1,2}$`)
+	numPattern  = regexp.MustCompile(`\d+`)
+)
+
+// PageLocator は "42" / "42-45" を "p. 42" / "pp. 42-45" に変換する
+func PageLocator(page string) (string, error) {
+	page = strings.TrimSpace(page)
+	if !pagePattern.MatchString(page) {
+		return "", fmt.Errorf("invalid page: %q (use 42 or 42-45)", page)
+	}
+	if nums := numPattern.FindAllString(page, -1); len(nums) == 2 {
+		return fmt.Sprintf("pp. %s-%s", nums[0], nums[1]), nil
+	}
+	return "p. " + page, nil
+}
+
+// ChapterLocator は "3" を "ch. 3" に変換する
+func ChapterLocator(chapter string) string {
+	return "ch. " + strings.TrimSpace(chapter)
+}
+
+// LocationLocator は Kindle の位置番号を "loc. 1234" に変換する
+func LocationLocator(location string) string {
+	return "loc. " + strings.TrimSpace(location)
+}
+
+// TimestampLocator は動画の再生位置（mm:ss / h:mm:ss）を検証してそのまま返す
+func TimestampLocator(ts string) (string, error) {
+	ts = strings.TrimSpace(ts)
+	if !timePattern.MatchString(ts) {
+		return "", fmt.Errorf("invalid timestamp: %q (use mm:ss or h:mm:ss)", ts)
+	}
+	return ts, nil
+}
+
+// locatorKey は並び替え用に (種類の順位, 数値) を返す。章 → ページ → 位置 → 時刻 → その他の順
+func locatorKey(locator string) (int, int) {
+	locator = strings.TrimSpace(locator)
+	first := func() int {
+		n, _ := strconv.Atoi(numPattern.FindString(locator))
+		return n
+	}
+	switch {
+	case locator == "":
+		return 5, 0
+	case strings.HasPrefix(locator, "ch."):
+		return 0, first()
+	case strings.HasPrefix(locator, "p.") || strings.HasPrefix(locator, "pp."):
+		return 1, first()
+	case strings.HasPrefix(locator, "loc."):
+		return 2, first()
+	case timePattern.MatchString(locator):
+		seconds := 0
+		for _, part := range strings.Split(locator, ":") {
+			n, _ := strconv.Atoi(part)
+			seconds = seconds*60 + n
+		}
+		return 3, seconds
+	}
+	return 4, first()
+}
+
+// LocatorLess はロケーターの並び順を比較する（未設定は最後）
+func LocatorLess(a, b string) bool {
+	ka, na := locatorKey(a)
+	kb, nb := locatorKey(b)
+	if ka != kb {
+		return ka < kb
+	}
+	if na != nb {
+		return na < nb
+	}
+	return a < b
 }
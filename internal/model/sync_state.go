@@ -0,0 +1,20 @@
+package model
+
+// SyncState は前回同期した時点の状態。三方向の比較（ローカル・同期先・前回）に使う
+type SyncState struct {
+	Target    string                   `json:"target"` // 同期先（SyncBackend.Name()）
+	Files     map[string]SyncFileState `json:"files"`  // キーは notes/xxx.md, json/notes.json など
+	Conflicts []SyncConflict           `json:"conflicts"`
+}
+
+type SyncFileState struct {
+	Hash    string `json:"hash"`    // 前回同期した内容の SHA-256
+	Version string `json:"version"` // 前回同期した時点の同期先の manifest の値
+}
+
+// SyncConflict は未解決の競合。競合コピーを削除すると解決済みになる
+type SyncConflict struct {
+	Key        string `json:"key"`
+	Copy       string `json:"copy"` // 同期先の内容を保存した競合コピーのパス
+	DetectedAt string `json:"detected_at"`
+}
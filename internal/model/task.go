@@ -1,23 +1,85 @@
 package model
 
+import "strings"
+
+const (
+	TaskStatusNotStarted = "Not started"
+	TaskStatusInProgress = "In progress"
+	TaskStatusWaiting    = "Waiting"
+	TaskStatusOnHold     = "On hold"
+	TaskStatusDone       = "Done"
+)
+
+// TaskStatuses はタスクが取り得るステータス（表示順）
+var TaskStatuses = []string{
+	TaskStatusNotStarted,
+	TaskStatusInProgress,
+	TaskStatusWaiting,
+	TaskStatusOnHold,
+	TaskStatusDone,
+}
+
+// TaskPriorities はタスクの優先度（高い順）
+var TaskPriorities = []string{"high", "medium", "low"}
+
 type Task struct {
-	ID     string `json:"id"`      // task-001...
-	NoteID string `json:"note_id"` // yyyymmddhhmmss
-	Status string `json:"status"`  // Not started, In progress, Waiting, On hold, Done
+	ID            string   `json:"id"`             // task-001...
+	NoteID        string   `json:"note_id"`        // yyyymmddhhmmss
+	Status        string   `json:"status"`         // Not started, In progress, Waiting, On hold, Done
+	DueDate       string   `json:"due_date"`       // yyyy-mm-dd
+	ScheduledDate string   `json:"scheduled_date"` // yyyy-mm-dd
+	Priority      string   `json:"priority"`       // high, medium, low
+	Estimate      string   `json:"estimate"`       // 30m, 1h30m...
+	Recurrence    string   `json:"recurrence"`     // FREQ=WEEKLY;INTERVAL=1;BYDAY=MO
+	BlockedBy     []string `json:"blocked_by"`     // task-001...
+	UID           string   `json:"uid"`            // iCalendar UID（インポートしたタスクのみ）
 }
 
 type TaskFrontMatter struct {
-	ID          string   `yaml:"id"`
-	Title       string   `yaml:"title"`
-	NoteType    string   `yaml:"note_type"` // fleeting, permanent, literature
-	Tags        []string `yaml:"tags"`
-	Links       []string `yaml:"links"`
-	ProjectName string   `yaml:"project_name"`
-	Status      string   `yaml:"status"`
-	CreatedAt   string   `yaml:"created_at"`
-	UpdatedAt   string   `yaml:"updated_at"`
-	Archived    bool     `yaml:"archived"`
-	Deleted     bool     `yaml:"deleted"`
+	ID            string   `yaml:"id"`
+	Title         string   `yaml:"title"`
+	NoteType      string   `yaml:"note_type"` // fleeting, permanent, literature
+	Tags          []string `yaml:"tags"`
+	Links         []string `yaml:"links"`
+	Projects      []string `yaml:"projects"`
+	ProjectName   string   `yaml:"project_name,omitempty"` // 旧形式（読み込み時に projects へ移行）
+	Status        string   `yaml:"status"`
+	DueDate       string   `yaml:"due_date"`
+	ScheduledDate string   `yaml:"scheduled_date"`
+	Priority      string   `yaml:"priority"`
+	Estimate      string   `yaml:"estimate"`
+	Recurrence    string   `yaml:"recurrence"`
+	BlockedBy     []string `yaml:"blocked_by"`
+	UID           string   `yaml:"uid,omitempty"`
+	CreatedAt     string   `yaml:"created_at"`
+	UpdatedAt     string   `yaml:"updated_at"`
+	Archived      bool     `yaml:"archived"`
+	Deleted       bool     `yaml:"deleted"`
+}
+
+// IsValidTaskStatus はステータスが定義済みの値か判定（大文字小文字は区別しない）
+func IsValidTaskStatus(status string) bool {
+	return NormalizeTaskStatus(status) != ""
+}
+
+// NormalizeTaskStatus は "not started" などの表記揺れを正規の表記に揃える。不正な値は空文字を返す
+func NormalizeTaskStatus(status string) string {
+	for _, s := range TaskStatuses {
+		if strings.EqualFold(s, status) {
+			return s
+		}
+	}
+	return ""
+}
+
+// PriorityRank は優先度の並び順を返す（high=0 … 未設定は最後）
+func PriorityRank(priority string) int {
+	for i, p := range TaskPriorities {
+		if strings.EqualFold(p, priority) {
+			return i
+		}
+	}
+	return len(TaskPriorities)
 }
 
 func (t *TaskFrontMatter) SetDeleted() {
@@ -35,3 +97,9 @@ func (n *TaskFrontMatter) SetArchived() {
 func (n *TaskFrontMatter) ResetArchived() {
 	n.Archived = false
 }
+
+func (t *TaskFrontMatter) MigrateProjects() bool {
+	var migrated bool
+	t.Projects, t.ProjectName, migrated = migrateProjectName(t.Projects, t.ProjectName)
+	return migrated
+}
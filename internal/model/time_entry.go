@@ -0,0 +1,9 @@
+package model
+
+type TimeEntry struct {
+	ID      string `json:"id"`       // te-001...
+	TaskID  string `json:"task_id"`  // task-001...
+	StartAt string `json:"start_at"` // yyyy-mm-dd hh:mm:ss
+	EndAt   string `json:"end_at"`   // yyyy-mm-dd hh:mm:ss（計測中は空）
+	Note    string `json:"note"`
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+// `- [ ] text` / `* [x] text` / `1. [ ] text` にマッチ
+var checkboxPattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])(\]\s+)(.*)$`)
+
+// ParseCheckboxes - Markdown からチェックボックスを抽出（コードブロック内は除外）
+func ParseCheckboxes(noteID, content string) []model.Checkbox {
+	var checkboxes []model.Checkbox
+	inCodeBlock := false
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		match := checkboxPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		checkboxes = append(checkboxes, model.Checkbox{
+			NoteID: noteID,
+			Line:   i + 1,
+			Text:   strings.TrimSpace(match[4]),
+			Done:   match[2] != " ",
+		})
+	}
+	return checkboxes
+}
+
+// ScanCheckboxes - ZettelDir の全ノートからチェックボックスを収集し、checkboxes.json に保存
+func ScanCheckboxes(config model.Config) ([]model.Checkbox, error) {
+	files, err := filepath.Glob(filepath.Join(config.ZettelDir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to scan directory: %w", err)
+	}
+
+	checkboxes := []model.Checkbox{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to read note file: %w", err)
+		}
+		noteID := strings.TrimSuffix(filepath.Base(file), ".md")
+		checkboxes = append(checkboxes, ParseCheckboxes(noteID, string(content))...)
+	}
+
+	sort.SliceStable(checkboxes, func(i, j int) bool {
+		if checkboxes[i].NoteID != checkboxes[j].NoteID {
+			return checkboxes[i].NoteID < checkboxes[j].NoteID
+		}
+		return checkboxes[i].Line < checkboxes[j].Line
+	})
+
+	if err := os.MkdirAll(config.JsonDataDir, 0755); err != nil {
+		return nil, fmt.Errorf("❌ Failed to create json data directory: %w", err)
+	}
+	if err := SaveUpdatedJson(checkboxes, filepath.Join(config.JsonDataDir, "checkboxes.json")); err != nil {
+		return nil, fmt.Errorf("❌ Failed to update checkboxes.json: %w", err)
+	}
+
+	return checkboxes, nil
+}
+
+// ToggleCheckbox - 指定行のチェックボックスの完了状態を反転し、ファイルを書き換える
+func ToggleCheckbox(noteID string, line int, config model.Config) (model.Checkbox, string, error) {
+	notePath := filepath.Join(config.ZettelDir, noteID+".md")
+	content, err := os.ReadFile(notePath)
+	if err != nil {
+		return model.Checkbox{}, "", fmt.Errorf("❌ Failed to read note file: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if line < 1 || line > len(lines) {
+		return model.Checkbox{}, "", fmt.Errorf("❌ Line %d is out of range (1-%d)", line, len(lines))
+	}
+
+	var target *model.Checkbox
+	for _, cb := range ParseCheckboxes(noteID, string(content)) {
+		if cb.Line == line {
+			target = &cb
+			break
+		}
+	}
+	if target == nil {
+		return model.Checkbox{}, "", fmt.Errorf("❌ Line %d of %s is not a checkbox", line, noteID)
+	}
+
+	mark := "x"
+	if target.Done {
+		mark = " "
+	}
+	lines[line-1] = checkboxPattern.ReplaceAllString(lines[line-1], "${1}"+mark+"${3}${4}")
+	target.Done = !target.Done
+
+	updatedContent := strings.Join(lines, "\n")
+	if err := os.WriteFile(notePath, []byte(updatedContent), 0644); err != nil {
+		return model.Checkbox{}, "", fmt.Errorf("❌ Error writing updated note file: %w", err)
+	}
+
+	return *target, updatedContent, nil
+}
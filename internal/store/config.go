@@ -82,6 +82,8 @@ func LoadConfig() (*model.Config, error) {
 	config.JsonDataDir = expandHomeDir(config.JsonDataDir)
 	config.ArchiveDir = expandHomeDir(config.ArchiveDir)
 	config.Trash.TrashDir = expandHomeDir(config.Trash.TrashDir)
+	config.Sync.Path = expandHomeDir(config.Sync.Path)
+	config.Sync.KeyFile = expandHomeDir(config.Sync.KeyFile)
 
 	return &config, nil
 }
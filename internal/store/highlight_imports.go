@@ -0,0 +1,36 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+func LoadHighlightImports(config model.Config) ([]model.HighlightImport, string, error) {
+	highlightImportsJsonPath := filepath.Join(config.JsonDataDir, "highlight_imports.json")
+
+	// ディレクトリがない場合は作成
+	if err := os.MkdirAll(config.JsonDataDir, 0755); err != nil {
+		return nil, "", fmt.Errorf("❌ Failed to create json data directory: %w", err)
+	}
+
+	// highlight_imports.json が存在しない場合、空の JSON 配列 `[]` で初期化
+	if _, err := os.Stat(highlightImportsJsonPath); os.IsNotExist(err) {
+		if err := os.WriteFile(highlightImportsJsonPath, []byte("[]"), 0644); err != nil {
+			return nil, "", fmt.Errorf("❌ Failed to create highlight_imports.json file: %w", err)
+		}
+	} else if err != nil {
+		// ファイルの存在確認時の別のエラー（例: 権限エラー）
+		return nil, "", fmt.Errorf("❌ Failed to check highlight_imports.json: %w", err)
+	}
+
+	// JSON をロード
+	var highlightImports []model.HighlightImport
+	if err := LoadJson(highlightImportsJsonPath, &highlightImports); err != nil {
+		return nil, "", fmt.Errorf("❌ Error loading highlight imports from JSON: %w", err)
+	}
+
+	return highlightImports, highlightImportsJsonPath, nil
+}
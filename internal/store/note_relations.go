@@ -0,0 +1,469 @@
+package store
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+// diffStrings は old → new で追加された要素と削除された要素を返す
+func diffStrings(oldItems, newItems []string) ([]string, []string) {
+	oldSet := make(map[string]bool)
+	for _, item := range oldItems {
+		oldSet[item] = true
+	}
+	newSet := make(map[string]bool)
+	for _, item := range newItems {
+		newSet[item] = true
+	}
+
+	var added, removed []string
+	for _, item := range newItems {
+		if !oldSet[item] {
+			added = append(added, item)
+			oldSet[item] = true // 重複を防ぐ
+		}
+	}
+	for _, item := range oldItems {
+		if !newSet[item] {
+			removed = append(removed, item)
+			newSet[item] = true // 重複を防ぐ
+		}
+	}
+	return added, removed
+}
+
+// SyncNoteTags reconciles tags.json and note_tags.json with the tag list of a
+// note's front matter. Missing tags are created, removed pairs are dropped and
+// tags that are no longer used by any note are garbage-collected.
+func SyncNoteTags(noteID string, oldTags, newTags []string, config model.Config) error {
+	added, removed := diffStrings(oldTags, newTags)
+
+	tags, tagsJsonPath, err := LoadTags(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load tags.json: %w", err)
+	}
+
+	noteTags, noteTagsJsonPath, err := LoadNoteTags(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load note_tags.json: %w", err)
+	}
+
+	// タグ名 → タグID のマッピング
+	tagIDMap := make(map[string]string)
+	for _, tag := range tags {
+		tagIDMap[tag.Name] = tag.ID
+	}
+
+	// 新しいタグは `tags.json` に追加（編集前から付いていたタグも欠けていれば補完）
+	for _, tagName := range newTags {
+		if tagName == "" {
+			continue
+		}
+		if _, exists := tagIDMap[tagName]; !exists {
+			newTag := model.Tag{ID: GetNextTagID(tags), Name: tagName}
+			tags = append(tags, newTag)
+			tagIDMap[tagName] = newTag.ID
+			log.Printf("✅ Tag '%s' created (%s)", tagName, newTag.ID)
+		}
+	}
+
+	// 既存の Note-Tag ペアを確認
+	pairExists := make(map[string]bool)
+	for _, nt := range noteTags {
+		if nt.NoteID == noteID {
+			pairExists[nt.TagID] = true
+		}
+	}
+
+	for _, tagName := range newTags {
+		tagID, exists := tagIDMap[tagName]
+		if !exists || pairExists[tagID] {
+			continue
+		}
+		noteTags = append(noteTags, model.NoteTag{NoteID: noteID, TagID: tagID})
+		pairExists[tagID] = true
+	}
+
+	// 削除されたタグの Note-Tag ペアを削除
+	removedIDs := make(map[string]bool)
+	for _, tagName := range removed {
+		if tagID, exists := tagIDMap[tagName]; exists {
+			removedIDs[tagID] = true
+		}
+	}
+
+	updatedNoteTags := []model.NoteTag{}
+	for _, nt := range noteTags {
+		if nt.NoteID == noteID && removedIDs[nt.TagID] {
+			continue
+		}
+		updatedNoteTags = append(updatedNoteTags, nt)
+	}
+
+	// どのノートにも使われなくなったタグを `tags.json` から削除
+	inUse := make(map[string]bool)
+	for _, nt := range updatedNoteTags {
+		inUse[nt.TagID] = true
+	}
+
+	updatedTags := []model.Tag{}
+	for _, tag := range tags {
+		if removedIDs[tag.ID] && !inUse[tag.ID] {
+			log.Printf("🗑️ Tag '%s' is no longer used, removing it", tag.Name)
+			continue
+		}
+		updatedTags = append(updatedTags, tag)
+	}
+
+	if err := SaveUpdatedJson(updatedTags, tagsJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update tags.json: %w", err)
+	}
+	if err := SaveUpdatedJson(updatedNoteTags, noteTagsJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update note_tags.json: %w", err)
+	}
+
+	if len(added) > 0 || len(removed) > 0 {
+		log.Printf("✅ Tags of note %s synced (added: %v, removed: %v)", noteID, added, removed)
+	}
+	return nil
+}
+
+// SyncNoteLinks reconciles links.json with the outgoing links of a note.
+// oldLinks and newLinks hold the target note IDs before and after the edit.
+func SyncNoteLinks(noteID string, oldLinks, newLinks []string, config model.Config) error {
+	added, removed := diffStrings(oldLinks, newLinks)
+
+	links, linksJsonPath, err := LoadLinks(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load links.json: %w", err)
+	}
+
+	removedSet := make(map[string]bool)
+	for _, targetID := range removed {
+		removedSet[targetID] = true
+	}
+
+	linkExists := make(map[string]bool)
+	updatedLinks := []model.Link{}
+	for _, link := range links {
+		if link.SourceNoteID == noteID {
+			if removedSet[link.TargetNoteID] || linkExists[link.TargetNoteID] {
+				continue
+			}
+			linkExists[link.TargetNoteID] = true
+		}
+		updatedLinks = append(updatedLinks, link)
+	}
+
+	for _, targetID := range newLinks {
+		if targetID == "" || targetID == noteID || linkExists[targetID] {
+			continue
+		}
+		updatedLinks = append(updatedLinks, model.Link{SourceNoteID: noteID, TargetNoteID: targetID})
+		linkExists[targetID] = true
+	}
+
+	if err := SaveUpdatedJson(updatedLinks, linksJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update links.json: %w", err)
+	}
+
+	if len(added) > 0 || len(removed) > 0 {
+		log.Printf("✅ Links of note %s synced (added: %v, removed: %v)", noteID, added, removed)
+	}
+	return nil
+}
+
+// ReassignNote moves every relation of fromID (note_tags, links, project_notes,
+// source_notes, highlight_imports and tasks) over to toID. Duplicate pairs and
+// self links that appear as a result are dropped. When both notes are tasks,
+// the task row of fromID is removed and its blockers and time entries move to
+// the task of toID; a task note cannot be reassigned to a note without a task row.
+func ReassignNote(fromID, toID string, config model.Config) error {
+	tasks, tasksJsonPath, err := LoadTasks(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	var fromTask, toTask model.Task
+	for _, task := range tasks {
+		switch task.NoteID {
+		case fromID:
+			fromTask = task
+		case toID:
+			toTask = task
+		}
+	}
+	if fromTask.ID != "" && toTask.ID == "" {
+		return fmt.Errorf("❌ Cannot move task %s to note %s, which is not a task", fromTask.ID, toID)
+	}
+
+	// `note_tags.json`
+	noteTags, noteTagsJsonPath, err := LoadNoteTags(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load note_tags.json: %w", err)
+	}
+	seenNoteTags := make(map[model.NoteTag]bool)
+	updatedNoteTags := []model.NoteTag{}
+	for _, nt := range noteTags {
+		if nt.NoteID == fromID {
+			nt.NoteID = toID
+		}
+		if seenNoteTags[nt] {
+			continue
+		}
+		seenNoteTags[nt] = true
+		updatedNoteTags = append(updatedNoteTags, nt)
+	}
+	if err := SaveUpdatedJson(updatedNoteTags, noteTagsJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update note_tags.json: %w", err)
+	}
+
+	// `links.json`
+	links, linksJsonPath, err := LoadLinks(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load links.json: %w", err)
+	}
+	seenLinks := make(map[model.Link]bool)
+	updatedLinks := []model.Link{}
+	for _, link := range links {
+		if link.SourceNoteID == fromID {
+			link.SourceNoteID = toID
+		}
+		if link.TargetNoteID == fromID {
+			link.TargetNoteID = toID
+		}
+		if link.SourceNoteID == link.TargetNoteID || seenLinks[link] {
+			continue
+		}
+		seenLinks[link] = true
+		updatedLinks = append(updatedLinks, link)
+	}
+	if err := SaveUpdatedJson(updatedLinks, linksJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update links.json: %w", err)
+	}
+
+	// `project_notes.json`
+	projectNotes, projectNotesJsonPath, err := LoadProjectNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+	seenProjectNotes := make(map[model.ProjectNote]bool)
+	updatedProjectNotes := []model.ProjectNote{}
+	for _, pn := range projectNotes {
+		if pn.NoteID == fromID {
+			pn.NoteID = toID
+		}
+		if seenProjectNotes[pn] {
+			continue
+		}
+		seenProjectNotes[pn] = true
+		updatedProjectNotes = append(updatedProjectNotes, pn)
+	}
+	if err := SaveUpdatedJson(updatedProjectNotes, projectNotesJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update project_notes.json: %w", err)
+	}
+
+	// `source_notes.json`
+	sourceNotes, sourceNotesJsonPath, err := LoadSourceNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+	seenSourceNotes := make(map[model.SourceNote]bool)
+	updatedSourceNotes := []model.SourceNote{}
+	for _, sn := range sourceNotes {
+		if sn.NoteID == fromID {
+			sn.NoteID = toID
+		}
+		if seenSourceNotes[sn] {
+			continue
+		}
+		seenSourceNotes[sn] = true
+		updatedSourceNotes = append(updatedSourceNotes, sn)
+	}
+	if err := SaveUpdatedJson(updatedSourceNotes, sourceNotesJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update source_notes.json: %w", err)
+	}
+
+	// `highlight_imports.json`（再取り込みで統合先のノートを更新する）
+	highlightImports, highlightImportsJsonPath, err := LoadHighlightImports(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load highlight_imports.json: %w", err)
+	}
+	for i := range highlightImports {
+		if highlightImports[i].NoteID == fromID {
+			highlightImports[i].NoteID = toID
+		}
+	}
+	if err := SaveUpdatedJson(highlightImports, highlightImportsJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update highlight_imports.json: %w", err)
+	}
+
+	// `tasks.json`（両方がタスクなら統合される側の行を削除し、blocked_by / time_entries を残る側のタスクへ）
+	if fromTask.ID == "" {
+		return nil
+	}
+	updatedTasks := []model.Task{}
+	for _, task := range tasks {
+		if task.ID == fromTask.ID {
+			continue
+		}
+		blockedBy := task.BlockedBy
+		if task.ID == toTask.ID {
+			blockedBy = append(append([]string{}, blockedBy...), fromTask.BlockedBy...)
+		}
+		var updatedBlockedBy []string
+		for _, blocker := range blockedBy {
+			if blocker == fromTask.ID {
+				blocker = toTask.ID
+			}
+			if blocker == task.ID || slices.Contains(updatedBlockedBy, blocker) {
+				continue
+			}
+			updatedBlockedBy = append(updatedBlockedBy, blocker)
+		}
+		task.BlockedBy = updatedBlockedBy
+		updatedTasks = append(updatedTasks, task)
+	}
+	if err := SaveUpdatedJson(updatedTasks, tasksJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update tasks.json: %w", err)
+	}
+
+	// `time_entries.json`
+	entries, timeEntriesJsonPath, err := LoadTimeEntries(config)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].TaskID == fromTask.ID {
+			entries[i].TaskID = toTask.ID
+		}
+	}
+	if err := SaveUpdatedJson(entries, timeEntriesJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update time_entries.json: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeNote removes a note from notes.json together with every relation that
+// refers to it (note_tags, links, project_notes, source_notes, highlight_imports
+// and tasks).
+// The note file itself is left to the caller.
+func PurgeNote(noteID string, config model.Config) error {
+	notes, notesJsonPath, err := LoadNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load notes.json: %w", err)
+	}
+	updatedNotes := []model.Note{}
+	for _, note := range notes {
+		if note.ID != noteID {
+			updatedNotes = append(updatedNotes, note)
+		}
+	}
+	if err := SaveUpdatedJson(updatedNotes, notesJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update notes.json: %w", err)
+	}
+
+	// `note_tags.json`（使われなくなったタグも削除）
+	var tagNames []string
+	tags, _, err := LoadTags(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load tags.json: %w", err)
+	}
+	noteTags, _, err := LoadNoteTags(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load note_tags.json: %w", err)
+	}
+	for _, nt := range noteTags {
+		if nt.NoteID != noteID {
+			continue
+		}
+		for _, tag := range tags {
+			if tag.ID == nt.TagID {
+				tagNames = append(tagNames, tag.Name)
+			}
+		}
+	}
+	if err := SyncNoteTags(noteID, tagNames, nil, config); err != nil {
+		return err
+	}
+
+	// `links.json`（リンク元・リンク先の両方）
+	links, linksJsonPath, err := LoadLinks(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load links.json: %w", err)
+	}
+	updatedLinks := []model.Link{}
+	for _, link := range links {
+		if link.SourceNoteID != noteID && link.TargetNoteID != noteID {
+			updatedLinks = append(updatedLinks, link)
+		}
+	}
+	if err := SaveUpdatedJson(updatedLinks, linksJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update links.json: %w", err)
+	}
+
+	// `project_notes.json`
+	projectNotes, projectNotesJsonPath, err := LoadProjectNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+	updatedProjectNotes := []model.ProjectNote{}
+	for _, pn := range projectNotes {
+		if pn.NoteID != noteID {
+			updatedProjectNotes = append(updatedProjectNotes, pn)
+		}
+	}
+	if err := SaveUpdatedJson(updatedProjectNotes, projectNotesJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update project_notes.json: %w", err)
+	}
+
+	// `source_notes.json`
+	sourceNotes, sourceNotesJsonPath, err := LoadSourceNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+	updatedSourceNotes := []model.SourceNote{}
+	for _, sn := range sourceNotes {
+		if sn.NoteID != noteID {
+			updatedSourceNotes = append(updatedSourceNotes, sn)
+		}
+	}
+	if err := SaveUpdatedJson(updatedSourceNotes, sourceNotesJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update source_notes.json: %w", err)
+	}
+
+	// `highlight_imports.json`（次の取り込みで新しいノートとして作り直す）
+	highlightImports, highlightImportsJsonPath, err := LoadHighlightImports(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load highlight_imports.json: %w", err)
+	}
+	updatedHighlightImports := []model.HighlightImport{}
+	for _, imp := range highlightImports {
+		if imp.NoteID != noteID {
+			updatedHighlightImports = append(updatedHighlightImports, imp)
+		}
+	}
+	if err := SaveUpdatedJson(updatedHighlightImports, highlightImportsJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update highlight_imports.json: %w", err)
+	}
+
+	// `tasks.json`
+	tasks, tasksJsonPath, err := LoadTasks(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load tasks.json: %w", err)
+	}
+	updatedTasks := []model.Task{}
+	for _, task := range tasks {
+		if task.NoteID != noteID {
+			updatedTasks = append(updatedTasks, task)
+		}
+	}
+	if err := SaveUpdatedJson(updatedTasks, tasksJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update tasks.json: %w", err)
+	}
+
+	return nil
+}
@@ -32,5 +32,27 @@ func LoadNotes(config model.Config) ([]model.Note, string, error) {
 		return nil, "", fmt.Errorf("❌ Error loading notes from JSON: %w", err)
 	}
 
+	// 旧形式の project_name は projects に移行（次回保存時に反映）
+	for i := range notes {
+		notes[i].MigrateProjects()
+	}
+
 	return notes, noteJsonPath, nil
 }
+
+// MarkNoteProcessed は inbox で処理したノートに `processed` を立てる
+func MarkNoteProcessed(noteID string, config model.Config) error {
+	notes, noteJsonPath, err := LoadNotes(config)
+	if err != nil {
+		return err
+	}
+
+	for i := range notes {
+		if notes[i].ID == noteID {
+			notes[i].Processed = true
+			return SaveUpdatedJson(notes, noteJsonPath)
+		}
+	}
+
+	return fmt.Errorf("❌ Note with ID %s not found", noteID)
+}
@@ -79,3 +79,27 @@ func InsertProjectNoteToJson(projectNote model.ProjectNote, config model.Config)
 	return nil
 
 }
+
+// RemoveProjectNotes は projectID に紐づく Project-Note ペアを削除する。
+// noteID が空の場合はプロジェクトのすべてのペアを削除し、削除した件数を返す
+func RemoveProjectNotes(projectID, noteID string, config model.Config) (int, error) {
+	projectNotes, projectNotesJsonPath, err := LoadProjectNotes(config)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Failed to load project_notes.json: %w", err)
+	}
+
+	removed := 0
+	updatedProjectNotes := []model.ProjectNote{}
+	for _, pn := range projectNotes {
+		if pn.ProjectID == projectID && (noteID == "" || pn.NoteID == noteID) {
+			removed++
+			continue
+		}
+		updatedProjectNotes = append(updatedProjectNotes, pn)
+	}
+
+	if err := SaveUpdatedJson(updatedProjectNotes, projectNotesJsonPath); err != nil {
+		return 0, fmt.Errorf("❌ Failed to update project_notes.json: %w", err)
+	}
+	return removed, nil
+}
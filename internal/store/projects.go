@@ -36,6 +36,13 @@ func LoadProjects(config model.Config) ([]model.Project, string, error) {
 		return nil, "", fmt.Errorf("❌ Error loading projects from JSON: %w", err)
 	}
 
+	// ステータス導入前のプロジェクトは active として扱う
+	for i := range projects {
+		if projects[i].Status == "" {
+			projects[i].Status = model.ProjectStatusActive
+		}
+	}
+
 	return projects, projectsJsonPath, nil
 }
 
@@ -99,3 +106,18 @@ func GetNextProjectID(projects []model.Project) string {
 	}
 	return fmt.Sprintf("p%d", newSeqID) // 1000以上はゼロ埋めなし
 }
+
+// FindProject はプロジェクトID (p001) またはプロジェクト名からプロジェクトを探す
+func FindProject(projects []model.Project, ref string) (int, bool) {
+	for i, project := range projects {
+		if project.ProjectID == ref {
+			return i, true
+		}
+	}
+	for i, project := range projects {
+		if project.Name == ref {
+			return i, true
+		}
+	}
+	return -1, false
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 
@@ -34,3 +35,54 @@ func LoadSourceNotes(config model.Config) ([]model.SourceNote, string, error) {
 
 	return sourceNotes, sourceNotesJsonPath, nil
 }
+
+func InsertSourceNoteToJson(sourceNote model.SourceNote, config model.Config) error {
+	sourceNotes, sourceNotesJsonPath, err := LoadSourceNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+
+	for _, sn := range sourceNotes {
+		if sn.SourceID == sourceNote.SourceID && sn.NoteID == sourceNote.NoteID {
+			log.Printf("⚠️  Skip: Source-Note pair (%s, %s) already exists.", sourceNote.SourceID, sourceNote.NoteID)
+			return nil
+		}
+	}
+
+	sourceNotes = append(sourceNotes, sourceNote)
+
+	if err := SaveUpdatedJson(sourceNotes, sourceNotesJsonPath); err != nil {
+		return fmt.Errorf("❌ Failed to update source_notes.json: %w", err)
+	}
+
+	return nil
+}
+
+// LinkSourceNote はソースとノートを紐づける。既に紐づいている場合はロケーターだけ更新する。
+// 追加した場合は true を返す
+func LinkSourceNote(sourceNote model.SourceNote, config model.Config) (bool, error) {
+	sourceNotes, sourceNotesJsonPath, err := LoadSourceNotes(config)
+	if err != nil {
+		return false, fmt.Errorf("❌ Failed to load source_notes.json: %w", err)
+	}
+
+	added := true
+	for i, sn := range sourceNotes {
+		if sn.SourceID == sourceNote.SourceID && sn.NoteID == sourceNote.NoteID {
+			if sourceNote.Locator == "" || sn.Locator == sourceNote.Locator {
+				return false, nil
+			}
+			sourceNotes[i].Locator = sourceNote.Locator
+			added = false
+			break
+		}
+	}
+	if added {
+		sourceNotes = append(sourceNotes, sourceNote)
+	}
+
+	if err := SaveUpdatedJson(sourceNotes, sourceNotesJsonPath); err != nil {
+		return false, fmt.Errorf("❌ Failed to update source_notes.json: %w", err)
+	}
+	return added, nil
+}
@@ -36,6 +36,14 @@ func LoadSources(config model.Config) ([]model.Source, string, error) {
 		return nil, "", fmt.Errorf("❌ Error loading notes from JSON: %w", err)
 	}
 
+	// 旧形式の author は authors に移行し、未設定の読書ステータスは to-read とする（次回保存時に反映）
+	for i := range sources {
+		sources[i].MigrateAuthors()
+		if sources[i].Status == "" {
+			sources[i].Status = model.SourceStatusToRead
+		}
+	}
+
 	return sources, sourcesJsonPath, nil
 }
 
@@ -98,3 +106,32 @@ func GetNextSourceID(sources []model.Source) string {
 	}
 	return fmt.Sprintf("s%d", newSeqID) // 1000以上はゼロ埋めなし
 }
+
+// MigrateSources は旧形式の sources.json（author 文字列、ステータスなし）を新形式で保存し直す。
+// 移行したソースの件数を返す
+func MigrateSources(config model.Config) (int, error) {
+	sourcesJsonPath := filepath.Join(config.JsonDataDir, "sources.json")
+
+	var sources []model.Source
+	if err := LoadJson(sourcesJsonPath, &sources); err != nil {
+		return 0, fmt.Errorf("❌ Error loading sources from JSON: %w", err)
+	}
+
+	migrated := 0
+	for i := range sources {
+		changed := sources[i].MigrateAuthors()
+		if sources[i].Status == "" {
+			sources[i].Status = model.SourceStatusToRead
+			changed = true
+		}
+		if changed {
+			migrated++
+		}
+	}
+
+	// 旧形式の editors（文字列の配列）も書き直すため、件数に関わらず保存する
+	if err := SaveUpdatedJson(sources, sourcesJsonPath); err != nil {
+		return 0, fmt.Errorf("❌ Failed to update sources.json: %w", err)
+	}
+	return migrated, nil
+}
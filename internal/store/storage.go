@@ -114,6 +114,11 @@ func ParseFrontMatter[T any](content string) (T, string, error) {
 		return frontMatter, content, fmt.Errorf("❌ Failed to parse front matter: %w", err)
 	}
 
+	// 旧形式の project_name は projects に移行（書き戻し時に project_name は消える）
+	if m, ok := any(&frontMatter).(model.ProjectMigratable); ok {
+		m.MigrateProjects()
+	}
+
 	return frontMatter, body, nil
 }
 
@@ -225,6 +230,64 @@ func MoveNoteToTrash(noteID string, config model.Config) error {
 	return nil
 }
 
+func ArchiveNote(noteID string, config model.Config) error {
+	notes, notesJsonPath, err := LoadNotes(config)
+	if err != nil {
+		return fmt.Errorf("❌ Error loading notes from JSON: %w", err)
+	}
+
+	for i := range notes {
+		if noteID != notes[i].SeqID {
+			continue
+		}
+
+		originalPath := filepath.Join(config.ZettelDir, notes[i].ID+".md")
+		archivedPath := filepath.Join(config.ArchiveDir, notes[i].ID+".md")
+
+		note, err := os.ReadFile(originalPath)
+		if err != nil {
+			return fmt.Errorf("❌ Error reading note file: %v", err)
+		}
+
+		// Parse front matter
+		frontMatter, body, err := ParseFrontMatter[model.NoteFrontMatter](string(note))
+		if err != nil {
+			return fmt.Errorf("❌ Error parsing front matter: %v", err)
+		}
+
+		// Update `archived:` field
+		updatedFrontMatter := UpdateArchivedToFrontMatter(&frontMatter)
+		updatedContent := UpdateFrontMatter(updatedFrontMatter, body)
+
+		// Write back to file
+		err = os.WriteFile(originalPath, []byte(updatedContent), 0644)
+		if err != nil {
+			return fmt.Errorf("❌ Error writing updated note file: %v", err)
+		}
+
+		if err := os.MkdirAll(config.ArchiveDir, 0755); err != nil {
+			return fmt.Errorf("❌ Failed to create archive directory: %v", err)
+		}
+
+		err = os.Rename(originalPath, archivedPath)
+		if err != nil {
+			return fmt.Errorf("❌ Error moving note to archive: %v", err)
+		}
+
+		notes[i].Archived = true
+
+		err = SaveUpdatedJson(notes, notesJsonPath)
+		if err != nil {
+			return fmt.Errorf("❌ Error updating JSON file: %v", err)
+		}
+
+		log.Printf("✅ Note %s moved to archive: %s", notes[i].ID, archivedPath)
+		return nil
+	}
+
+	return fmt.Errorf("❌ Note with ID %s not found", noteID)
+}
+
 func DeleteNotePermanently(noteID string, config model.Config) error {
 	notes, notesJsonPath, err := LoadNotes(config)
 	if err != nil {
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+// SyncStateDir は同期先ごとの同期状態を保存するディレクトリ（設定ファイルと同じ場所の sync/<同期先の ID>/）。
+// 同期対象のディレクトリの外に置き、同期状態そのものが同期されないようにする。
+// 同期先を切り替えても、別の同期先の状態を前回の同期として使わないよう同期先ごとに分ける
+func SyncStateDir(target string) (string, error) {
+	root, err := syncStateRoot()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(target))
+	return filepath.Join(root, hex.EncodeToString(sum[:8])), nil
+}
+
+func syncStateRoot() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to get config path: %w", err)
+	}
+	return filepath.Join(filepath.Dir(configPath), "sync"), nil
+}
+
+// LoadSyncState - 同期先 target（SyncBackend.Name()）との前回の同期状態を読み込む
+func LoadSyncState(target string) (model.SyncState, string, error) {
+	stateDir, err := SyncStateDir(target)
+	if err != nil {
+		return model.SyncState{}, "", err
+	}
+	statePath := filepath.Join(stateDir, "state.json")
+
+	state := model.SyncState{Target: target, Files: make(map[string]model.SyncFileState)}
+	data, err := os.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return state, statePath, nil
+	} else if err != nil {
+		return state, "", fmt.Errorf("❌ Failed to read %s: %w", statePath, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, "", fmt.Errorf("❌ Failed to parse %s: %w", statePath, err)
+	}
+	if state.Files == nil {
+		state.Files = make(map[string]model.SyncFileState)
+	}
+	return state, statePath, nil
+}
+
+func SaveSyncState(state model.SyncState, statePath string) error {
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return fmt.Errorf("❌ Failed to create sync state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to convert sync state to JSON: %w", err)
+	}
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write %s: %w", statePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+func LoadTimeEntries(config model.Config) ([]model.TimeEntry, string, error) {
+	timeEntriesJsonPath := filepath.Join(config.JsonDataDir, "time_entries.json")
+
+	// ディレクトリがない場合は作成
+	if err := os.MkdirAll(config.JsonDataDir, 0755); err != nil {
+		return nil, "", fmt.Errorf("❌ Failed to create json data directory: %w", err)
+	}
+
+	// time_entries.json が存在しない場合、空の JSON 配列 `[]` で初期化
+	if _, err := os.Stat(timeEntriesJsonPath); os.IsNotExist(err) {
+		if err := os.WriteFile(timeEntriesJsonPath, []byte("[]"), 0644); err != nil {
+			return nil, "", fmt.Errorf("❌ Failed to create time_entries.json file: %w", err)
+		}
+	} else if err != nil {
+		return nil, "", fmt.Errorf("❌ Failed to check time_entries.json: %w", err)
+	}
+
+	var entries []model.TimeEntry
+	if err := LoadJson(timeEntriesJsonPath, &entries); err != nil {
+		return nil, "", fmt.Errorf("❌ Error loading time entries from JSON: %w", err)
+	}
+
+	return entries, timeEntriesJsonPath, nil
+}
+
+// RunningTimeEntry - 計測中のエントリ（EndAt が空）を返す
+func RunningTimeEntry(entries []model.TimeEntry) (int, bool) {
+	for i, entry := range entries {
+		if entry.EndAt == "" {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func GetNextTimeEntryID(entries []model.TimeEntry) string {
+	maxSeqID := 0
+	re := regexp.MustCompile(`te-(\d+)`)
+
+	for _, entry := range entries {
+		match := re.FindStringSubmatch(entry.ID)
+		if match != nil {
+			seq, err := strconv.Atoi(match[1])
+			if err == nil && seq > maxSeqID {
+				maxSeqID = seq
+			}
+		}
+	}
+
+	newSeqID := maxSeqID + 1
+	if newSeqID < 1000 {
+		return fmt.Sprintf("te-%03d", newSeqID)
+	}
+	return fmt.Sprintf("te-%d", newSeqID)
+}
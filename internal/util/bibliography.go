@@ -0,0 +1,781 @@
+package util
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+// Reference - BibTeX / RIS / CSL-JSON に共通の書誌情報
+type Reference struct {
+	Type        string // 読み込み時は元フォーマットでの種別（article, JOUR...）、書き出し時は book / web / paper / video
+	CitationKey string
+	Title       string
+	Authors     []string // "Family, Given"（団体名は "{World Health Organization}"）
+	Editors     []string
+	Year        int
+	Publisher   string
+	Journal     string
+	Volume      string
+	Issue       string
+	Pages       string
+	DOI         string
+	ArXiv       string // 2101.00001
+	ISBN        string
+	URL         string
+}
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// latexCommand - アクセント以外の LaTeX コマンド（\TeX, \& など）
+var latexCommand = regexp.MustCompile(`\\([A-Za-z]+|[&%$#_])`)
+
+func parseYear(value string) int {
+	year, _ := strconv.Atoi(yearPattern.FindString(value))
+	return year
+}
+
+var arxivDOIPattern = regexp.MustCompile(`(?i)^10\.48550/arxiv\.(.+)$`)
+
+// ArXivFromDOI - arXiv の DOI（10.48550/arXiv.2101.00001）から arXiv ID を取り出す
+func ArXivFromDOI(doi string) string {
+	if m := arxivDOIPattern.FindStringSubmatch(doi); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// NormalizeDOI - "https://doi.org/10.1000/xyz" や "doi:10.1000/xyz" を "10.1000/xyz" に揃える
+func NormalizeDOI(doi string) string {
+	doi = strings.TrimSpace(doi)
+	lower := strings.ToLower(doi)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		if strings.HasPrefix(lower, prefix) {
+			return strings.TrimSpace(doi[len(prefix):])
+		}
+	}
+	return doi
+}
+
+// normalizePages - "123--145" / "123 – 145" を "123-145" に揃える
+func normalizePages(pages string) string {
+	pages = strings.NewReplacer("--", "-", "–", "-", "—", "-").Replace(pages)
+	return strings.ReplaceAll(pages, " - ", "-")
+}
+
+// normalizeName - "Given Family" を "Family, Given" に揃える（すでにカンマ区切りならそのまま）
+func normalizeName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || strings.Contains(name, ",") {
+		return name
+	}
+	parts := strings.Fields(name)
+	if len(parts) == 1 {
+		return name
+	}
+	return parts[len(parts)-1] + ", " + strings.Join(parts[:len(parts)-1], " ")
+}
+
+// literalName - 姓と名に分けない名前（団体名）を "{Name}" の形にする
+func literalName(name string) string {
+	return model.Person{Literal: name}.String()
+}
+
+// risName - RIS の名前は "Family, Given" なので、カンマのない複数語の名前は団体名として扱う
+func risName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if !strings.Contains(name, ",") && strings.Contains(name, " ") {
+		return literalName(name)
+	}
+	return name
+}
+
+// ---- BibTeX ----
+
+var latexAccents = strings.NewReplacer(
+	`\"a`, "ä", `\"o`, "ö", `\"u`, "ü", `\"A`, "Ä", `\"O`, "Ö", `\"U`, "Ü", `\"e`, "ë", `\"i`, "ï",
+	`\'a`, "á", `\'e`, "é", `\'i`, "í", `\'o`, "ó", `\'u`, "ú", `\'E`, "É", `\'c`, "ć", `\'n`, "ń",
+	"\\`a", "à", "\\`e", "è", "\\`i", "ì", "\\`o", "ò", "\\`u", "ù",
+	`\^a`, "â", `\^e`, "ê", `\^i`, "î", `\^o`, "ô", `\^u`, "û",
+	`\~n`, "ñ", `\~a`, "ã", `\~o`, "õ", `\c c`, "ç", `\c{c}`, "ç", `\ss`, "ß", `\o`, "ø", `\aa`, "å",
+	`\&`, "&", `\%`, "%", `\_`, "_", `\$`, "$", `\#`, "#", `\{`, "{", `\}`, "}",
+)
+
+// cleanBibValue - 値から波括弧と LaTeX のエスケープを取り除く
+func cleanBibValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '\\' && i+1 < len(value) && (value[i+1] == '{' || value[i+1] == '}') {
+			b.WriteByte(c)
+			b.WriteByte(value[i+1])
+			i++
+			continue
+		}
+		if c == '{' || c == '}' {
+			continue
+		}
+		b.WriteByte(c)
+	}
+	cleaned := latexAccents.Replace(b.String())
+	cleaned = latexCommand.ReplaceAllString(cleaned, "$1")
+	cleaned = strings.ReplaceAll(cleaned, "~", " ")
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+// scanBraces - s[start] の '{' に対応する '}' の位置を返す
+func scanBraces(s string, start int) (int, bool) {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// splitBibNames - "A and B and {C and D}" をネストの外側の "and" で分割
+func splitBibNames(value string) []string {
+	var names []string
+	depth, start := 0, 0
+	lower := strings.ToLower(value)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth == 0 && strings.HasPrefix(lower[i:], " and ") {
+			names = append(names, value[start:i])
+			start = i + len(" and ")
+			i = start - 1
+		}
+	}
+	names = append(names, value[start:])
+
+	var result []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		// {World Health Organization} のように全体が括弧で囲まれた名前は団体名
+		if end, ok := scanBraces(name, 0); ok && strings.HasPrefix(name, "{") && end == len(name)-1 {
+			result = append(result, literalName(cleanBibValue(name)))
+			continue
+		}
+		result = append(result, normalizeName(cleanBibValue(name)))
+	}
+	return result
+}
+
+// parseBibFields - `name = {value} # "value" # macro, ...` を読み取る（値は括弧付きの生の文字列）
+func parseBibFields(s string, macros map[string]string) (map[string]string, error) {
+	fields := make(map[string]string)
+	i := 0
+	for {
+		for i < len(s) && (unicode.IsSpace(rune(s[i])) || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return fields, nil
+		}
+		eq := strings.IndexByte(s[i:], '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("missing '=' after %q", strings.TrimSpace(s[i:]))
+		}
+		name := strings.ToLower(strings.TrimSpace(s[i : i+eq]))
+		i += eq + 1
+
+		var value strings.Builder
+		for {
+			for i < len(s) && unicode.IsSpace(rune(s[i])) {
+				i++
+			}
+			if i >= len(s) {
+				break
+			}
+			switch s[i] {
+			case '{':
+				end, ok := scanBraces(s, i)
+				if !ok {
+					return nil, fmt.Errorf("unbalanced braces in field %s", name)
+				}
+				value.WriteString(s[i+1 : end])
+				i = end + 1
+			case '"':
+				depth, end := 0, -1
+				for j := i + 1; j < len(s); j++ {
+					if s[j] == '{' {
+						depth++
+					} else if s[j] == '}' {
+						depth--
+					} else if s[j] == '"' && depth == 0 && s[j-1] != '\\' {
+						end = j
+						break
+					}
+				}
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated string in field %s", name)
+				}
+				value.WriteString(s[i+1 : end])
+				i = end + 1
+			default:
+				j := i
+				for j < len(s) && s[j] != ',' && s[j] != '#' && !unicode.IsSpace(rune(s[j])) {
+					j++
+				}
+				token := s[i:j]
+				if macro, ok := macros[strings.ToLower(token)]; ok {
+					token = macro
+				}
+				value.WriteString(token)
+				i = j
+			}
+
+			for i < len(s) && unicode.IsSpace(rune(s[i])) {
+				i++
+			}
+			if i < len(s) && s[i] == '#' {
+				i++
+				continue
+			}
+			break
+		}
+		fields[name] = value.String()
+	}
+}
+
+var bibMonths = map[string]string{
+	"jan": "January", "feb": "February", "mar": "March", "apr": "April", "may": "May", "jun": "June",
+	"jul": "July", "aug": "August", "sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+// ParseBibTeX - BibTeX のエントリを読み取る（@string マクロに対応、@comment / @preamble は無視）
+func ParseBibTeX(r io.Reader) ([]Reference, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read BibTeX: %w", err)
+	}
+	src := string(data)
+
+	macros := make(map[string]string)
+	for k, v := range bibMonths {
+		macros[k] = v
+	}
+
+	var refs []Reference
+	i := 0
+	for {
+		at := strings.IndexByte(src[i:], '@')
+		if at < 0 {
+			break
+		}
+		i += at + 1
+		j := i
+		for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+			j++
+		}
+		entryType := strings.ToLower(src[i:j])
+		k := j
+		for k < len(src) && unicode.IsSpace(rune(src[k])) {
+			k++
+		}
+		if entryType == "" || k >= len(src) || (src[k] != '{' && src[k] != '(') {
+			i = j
+			continue
+		}
+
+		var end int
+		if src[k] == '{' {
+			var ok bool
+			if end, ok = scanBraces(src, k); !ok {
+				return nil, fmt.Errorf("line %d: unbalanced braces in @%s entry", strings.Count(src[:k], "\n")+1, entryType)
+			}
+		} else {
+			// @entry( ... ) 形式: 括弧の外側にある最初の ')' まで
+			end = -1
+			depth := 0
+			for p := k + 1; p < len(src) && end < 0; p++ {
+				switch src[p] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				case ')':
+					if depth == 0 {
+						end = p
+					}
+				}
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated @%s entry", strings.Count(src[:k], "\n")+1, entryType)
+			}
+		}
+		body := src[k+1 : end]
+		line := strings.Count(src[:k], "\n") + 1
+		i = end + 1
+
+		switch entryType {
+		case "comment", "preamble":
+			continue
+		case "string":
+			fields, err := parseBibFields(body, macros)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			for name, value := range fields {
+				macros[name] = value
+			}
+			continue
+		}
+
+		comma := strings.IndexByte(body, ',')
+		if comma < 0 {
+			comma = len(body)
+		}
+		key := strings.TrimSpace(body[:comma])
+		fields, err := parseBibFields(body[comma:], macros)
+		if err != nil {
+			return nil, fmt.Errorf("line %d (%s): %w", line, key, err)
+		}
+
+		ref := Reference{
+			Type:        entryType,
+			CitationKey: key,
+			Title:       cleanBibValue(fields["title"]),
+			Publisher:   cleanBibValue(firstNonEmpty(fields["publisher"], fields["institution"], fields["school"], fields["organization"])),
+			Journal:     cleanBibValue(firstNonEmpty(fields["journal"], fields["journaltitle"], fields["booktitle"])),
+			Volume:      cleanBibValue(fields["volume"]),
+			Issue:       cleanBibValue(firstNonEmpty(fields["number"], fields["issue"])),
+			Pages:       normalizePages(cleanBibValue(fields["pages"])),
+			DOI:         NormalizeDOI(cleanBibValue(fields["doi"])),
+			ISBN:        cleanBibValue(fields["isbn"]),
+			URL:         cleanBibValue(fields["url"]),
+			Year:        parseYear(firstNonEmpty(fields["year"], fields["date"])),
+		}
+		if fields["author"] != "" {
+			ref.Authors = splitBibNames(fields["author"])
+		}
+		if fields["editor"] != "" {
+			ref.Editors = splitBibNames(fields["editor"])
+		}
+		if prefix := cleanBibValue(firstNonEmpty(fields["archiveprefix"], fields["eprinttype"])); strings.EqualFold(prefix, "arxiv") {
+			ref.ArXiv = cleanBibValue(fields["eprint"])
+		}
+		if ref.URL == "" {
+			// howpublished = {\url{https://...}}
+			if howpublished := cleanBibValue(strings.ReplaceAll(fields["howpublished"], `\url`, "")); strings.HasPrefix(howpublished, "http") {
+				ref.URL = howpublished
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ---- RIS ----
+
+var risLinePattern = regexp.MustCompile(`^([A-Z][A-Z0-9])\s{1,2}-\s?(.*)$`)
+
+// ParseRIS - RIS 形式（TY ～ ER）のレコードを読み取る
+func ParseRIS(r io.Reader) ([]Reference, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+
+	var refs []Reference
+	var current *Reference
+	var startPage, endPage string
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\uFEFF"), "\r")
+		match := risLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		tag, value := match[1], strings.TrimSpace(match[2])
+
+		if tag == "TY" {
+			current = &Reference{Type: value}
+			startPage, endPage = "", ""
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: %s outside of a TY ... ER record", lineNo, tag)
+		}
+
+		switch tag {
+		case "ER":
+			switch {
+			case startPage != "" && endPage != "":
+				current.Pages = startPage + "-" + endPage
+			case startPage != "":
+				current.Pages = normalizePages(startPage)
+			}
+			refs = append(refs, *current)
+			current = nil
+		case "ID":
+			current.CitationKey = value
+		case "TI", "T1":
+			current.Title = value
+		case "AU", "A1":
+			current.Authors = append(current.Authors, risName(value))
+		case "A2", "ED", "A3":
+			current.Editors = append(current.Editors, risName(value))
+		case "PY", "Y1", "DA":
+			if current.Year == 0 {
+				current.Year = parseYear(value)
+			}
+		case "PB":
+			current.Publisher = value
+		case "T2", "JO", "JF", "JA", "BT":
+			if current.Journal == "" {
+				current.Journal = value
+			}
+		case "VL":
+			current.Volume = value
+		case "IS":
+			current.Issue = value
+		case "SP":
+			startPage = value
+		case "EP":
+			endPage = value
+		case "DO":
+			current.DOI = NormalizeDOI(value)
+		case "SN":
+			current.ISBN = value
+		case "UR":
+			if current.URL == "" {
+				current.URL = value
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read RIS: %w", err)
+	}
+	if current != nil {
+		return nil, fmt.Errorf("record %q is missing ER", current.Title)
+	}
+	return refs, nil
+}
+
+// ---- CSL-JSON ----
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]any `json:"date-parts,omitempty"`
+	Literal   string  `json:"literal,omitempty"`
+	Raw       string  `json:"raw,omitempty"`
+}
+
+type cslItem struct {
+	ID             any       `json:"id"`
+	Type           string    `json:"type"`
+	CitationKey    string    `json:"citation-key,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	Author         []cslName `json:"author,omitempty"`
+	Editor         []cslName `json:"editor,omitempty"`
+	Issued         *cslDate  `json:"issued,omitempty"`
+	Publisher      string    `json:"publisher,omitempty"`
+	ContainerTitle string    `json:"container-title,omitempty"`
+	Volume         any       `json:"volume,omitempty"`
+	Issue          any       `json:"issue,omitempty"`
+	Page           any       `json:"page,omitempty"`
+	DOI            string    `json:"DOI,omitempty"`
+	ISBN           string    `json:"ISBN,omitempty"`
+	URL            string    `json:"URL,omitempty"`
+}
+
+// cslString - 数値でも文字列でもよい CSL の値を文字列にする
+func cslString(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func cslNames(names []cslName) []string {
+	var result []string
+	for _, n := range names {
+		switch {
+		case n.Literal != "":
+			result = append(result, literalName(n.Literal))
+		case n.Given != "":
+			result = append(result, n.Family+", "+n.Given)
+		case strings.Contains(n.Family, " "):
+			result = append(result, literalName(n.Family))
+		case n.Family != "":
+			result = append(result, n.Family)
+		}
+	}
+	return result
+}
+
+// ParseCSLJSON - CSL-JSON（配列または単一オブジェクト）を読み取る
+func ParseCSLJSON(r io.Reader) ([]Reference, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSL-JSON: %w", err)
+	}
+
+	var items []cslItem
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		var item cslItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, fmt.Errorf("failed to parse CSL-JSON: %w", err)
+		}
+		items = []cslItem{item}
+	} else if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse CSL-JSON: %w", err)
+	}
+
+	var refs []Reference
+	for _, item := range items {
+		ref := Reference{
+			Type:        item.Type,
+			CitationKey: firstNonEmpty(item.CitationKey, cslString(item.ID)),
+			Title:       item.Title,
+			Authors:     cslNames(item.Author),
+			Editors:     cslNames(item.Editor),
+			Publisher:   item.Publisher,
+			Journal:     item.ContainerTitle,
+			Volume:      cslString(item.Volume),
+			Issue:       cslString(item.Issue),
+			Pages:       normalizePages(cslString(item.Page)),
+			DOI:         NormalizeDOI(item.DOI),
+			ISBN:        item.ISBN,
+			URL:         item.URL,
+		}
+		if item.Issued != nil {
+			if len(item.Issued.DateParts) > 0 && len(item.Issued.DateParts[0]) > 0 {
+				ref.Year = parseYear(cslString(item.Issued.DateParts[0][0]))
+			} else {
+				ref.Year = parseYear(firstNonEmpty(item.Issued.Literal, item.Issued.Raw))
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// ---- 書き出し ----
+
+// referenceKind - 書き出し用に article / chapter / book / web / video / misc に分類
+func referenceKind(ref Reference) string {
+	switch ref.Type {
+	case "paper":
+		if ref.Journal != "" {
+			return "article"
+		}
+		return "misc"
+	case "book":
+		if ref.Journal != "" {
+			return "chapter"
+		}
+		return "book"
+	case "web", "video":
+		return ref.Type
+	}
+	return "misc"
+}
+
+// splitName - "Family, Given" を姓と名に分ける（団体名 "{Name}" は括弧を外して姓のみ）
+func splitName(name string) (family, given string) {
+	if literal, ok := model.LiteralName(name); ok {
+		return literal, ""
+	}
+	family, given, _ = strings.Cut(name, ",")
+	return strings.TrimSpace(family), strings.TrimSpace(given)
+}
+
+var bibEscaper = strings.NewReplacer("&", `\&`, "%", `\%`, "#", `\#`, "_", `\_`, "$", `\$`)
+
+// WriteBibTeX - 書誌情報を BibTeX として書き出す
+func WriteBibTeX(w io.Writer, refs []Reference) error {
+	var b strings.Builder
+	for i, ref := range refs {
+		entryType := map[string]string{
+			"article": "article", "chapter": "incollection", "book": "book", "web": "online", "video": "misc", "misc": "misc",
+		}[referenceKind(ref)]
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "@%s{%s,\n", entryType, ref.CitationKey)
+
+		field := func(name, value string) {
+			if value != "" {
+				fmt.Fprintf(&b, "  %s = {%s},\n", name, value)
+			}
+		}
+		field("author", bibEscaper.Replace(strings.Join(ref.Authors, " and ")))
+		field("editor", bibEscaper.Replace(strings.Join(ref.Editors, " and ")))
+		field("title", bibEscaper.Replace(ref.Title))
+		switch referenceKind(ref) {
+		case "article":
+			field("journal", bibEscaper.Replace(ref.Journal))
+		case "chapter":
+			field("booktitle", bibEscaper.Replace(ref.Journal))
+		}
+		if ref.Year > 0 {
+			field("year", strconv.Itoa(ref.Year))
+		}
+		field("volume", ref.Volume)
+		field("number", ref.Issue)
+		field("pages", strings.ReplaceAll(ref.Pages, "-", "--"))
+		field("publisher", bibEscaper.Replace(ref.Publisher))
+		field("isbn", ref.ISBN)
+		field("doi", ref.DOI)
+		if ref.ArXiv != "" {
+			field("eprint", ref.ArXiv)
+			field("archivePrefix", "arXiv")
+		}
+		field("url", ref.URL)
+		b.WriteString("}\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// risNameValue - RIS には団体名を括弧なしで書く
+func risNameValue(name string) string {
+	if literal, ok := model.LiteralName(name); ok {
+		return literal
+	}
+	return name
+}
+
+// WriteRIS - 書誌情報を RIS として書き出す
+func WriteRIS(w io.Writer, refs []Reference) error {
+	var b strings.Builder
+	for _, ref := range refs {
+		tag := func(name, value string) {
+			if value != "" {
+				fmt.Fprintf(&b, "%s  - %s\n", name, value)
+			}
+		}
+		tag("TY", map[string]string{
+			"article": "JOUR", "chapter": "CHAP", "book": "BOOK", "web": "ELEC", "video": "VIDEO", "misc": "GEN",
+		}[referenceKind(ref)])
+		tag("ID", ref.CitationKey)
+		for _, author := range ref.Authors {
+			tag("AU", risNameValue(author))
+		}
+		for _, editor := range ref.Editors {
+			tag("ED", risNameValue(editor))
+		}
+		tag("TI", ref.Title)
+		switch referenceKind(ref) {
+		case "article":
+			tag("JO", ref.Journal)
+		case "chapter":
+			tag("T2", ref.Journal)
+		}
+		if ref.Year > 0 {
+			tag("PY", strconv.Itoa(ref.Year))
+		}
+		tag("VL", ref.Volume)
+		tag("IS", ref.Issue)
+		if start, end, ok := strings.Cut(ref.Pages, "-"); ok {
+			tag("SP", start)
+			tag("EP", end)
+		} else {
+			tag("SP", ref.Pages)
+		}
+		tag("PB", ref.Publisher)
+		tag("SN", ref.ISBN)
+		tag("DO", ref.DOI)
+		tag("UR", ref.URL)
+		b.WriteString("ER  - \n\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func toCSLNames(names []string) []cslName {
+	var result []cslName
+	for _, name := range names {
+		family, given := splitName(name)
+		if given == "" {
+			result = append(result, cslName{Literal: family})
+			continue
+		}
+		result = append(result, cslName{Family: family, Given: given})
+	}
+	return result
+}
+
+// WriteCSLJSON - 書誌情報を CSL-JSON の配列として書き出す
+func WriteCSLJSON(w io.Writer, refs []Reference) error {
+	items := []cslItem{}
+	for _, ref := range refs {
+		item := cslItem{
+			ID: ref.CitationKey,
+			Type: map[string]string{
+				"article": "article-journal", "chapter": "chapter", "book": "book", "web": "webpage", "video": "motion_picture", "misc": "document",
+			}[referenceKind(ref)],
+			Title:          ref.Title,
+			Author:         toCSLNames(ref.Authors),
+			Editor:         toCSLNames(ref.Editors),
+			Publisher:      ref.Publisher,
+			ContainerTitle: ref.Journal,
+			DOI:            ref.DOI,
+			ISBN:           ref.ISBN,
+			URL:            ref.URL,
+		}
+		if ref.Volume != "" {
+			item.Volume = ref.Volume
+		}
+		if ref.Issue != "" {
+			item.Issue = ref.Issue
+		}
+		if ref.Pages != "" {
+			item.Page = ref.Pages
+		}
+		if ref.Year > 0 {
+			item.Issued = &cslDate{DateParts: [][]any{{ref.Year}}}
+		}
+		items = append(items, item)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
@@ -0,0 +1,299 @@
+package util
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBibTeX(t *testing.T) {
+	src := `@comment{ignored @article{nope, title = {Nope}} }
+@preamble{"\newcommand{\noop}[1]{}"}
+@string{ acm = "Communications of the " # {ACM} }
+@STRING(pub = {O'Reilly})
+
+@article{smith2020deep,
+  author    = {Smith, John and Jane Q. Doe and {World Health Organization} and M{\"u}ller, Hans},
+  title     = {Deep {Learning} for {\TeX} \& Friends},
+  journal   = acm,
+  year      = 2020,
+  month     = mar,
+  volume    = {63},
+  number    = "4",
+  pages     = {123--145},
+  doi       = {https://doi.org/10.1145/1234567},
+}
+
+@book(doe2019,
+  editor    = "Doe, Jane and {Barnes and Noble}",
+  title     = "A {"}Quoted{"} Title",
+  publisher = pub,
+  date      = {2019-05-01},
+  isbn      = {978-0-00-000000-0},
+  howpublished = {\url{https://example.com/book}}
+)
+
+@misc{vaswani2017,
+  author        = {Vaswani, Ashish},
+  title         = {Attention Is All You Need},
+  eprint        = {1706.03762},
+  archivePrefix = {arXiv},
+}
+`
+	refs, err := ParseBibTeX(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParseBibTeX() error: %v", err)
+	}
+
+	want := []Reference{
+		{
+			Type:        "article",
+			CitationKey: "smith2020deep",
+			Title:       "Deep Learning for TeX & Friends",
+			Authors:     []string{"Smith, John", "Doe, Jane Q.", "{World Health Organization}", "Müller, Hans"},
+			Year:        2020,
+			Journal:     "Communications of the ACM",
+			Volume:      "63",
+			Issue:       "4",
+			Pages:       "123-145",
+			DOI:         "10.1145/1234567",
+		},
+		{
+			Type:        "book",
+			CitationKey: "doe2019",
+			Title:       `A "Quoted" Title`,
+			Editors:     []string{"Doe, Jane", "{Barnes and Noble}"},
+			Year:        2019,
+			Publisher:   "O'Reilly",
+			ISBN:        "978-0-00-000000-0",
+			URL:         "https://example.com/book",
+		},
+		{
+			Type:        "misc",
+			CitationKey: "vaswani2017",
+			Title:       "Attention Is All You Need",
+			Authors:     []string{"Vaswani, Ashish"},
+			ArXiv:       "1706.03762",
+		},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("ParseBibTeX() =\n%#v\nwant\n%#v", refs, want)
+	}
+}
+
+func TestParseBibTeXErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unbalanced braces", "@article{key, title = {Open}"},
+		{"unterminated parentheses", "@article(key, title = {Open}"},
+		{"unterminated string", `@article{key, title = "Open}`},
+		{"missing equals", "@article{key, title {Open}}"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseBibTeX(strings.NewReader(tt.src)); err == nil {
+			t.Errorf("%s: ParseBibTeX() returned no error", tt.name)
+		}
+	}
+}
+
+func TestParseRIS(t *testing.T) {
+	src := "\uFEFFTY  - JOUR\r\n" +
+		"ID  - smith2020\r\n" +
+		"AU  - Smith, John\r\n" +
+		"A1  - World Health Organization\r\n" +
+		"AU  - UNESCO\r\n" +
+		"ED  - Doe, Jane\r\n" +
+		"T1  - Deep Learning\r\n" +
+		"JO  - Journal of Tests\r\n" +
+		"JF  - Ignored Full Title\r\n" +
+		"Y1  - 2020/03/01\r\n" +
+		"DA  - 2019\r\n" +
+		"VL  - 63\r\n" +
+		"IS  - 4\r\n" +
+		"SP  - 123\r\n" +
+		"EP  - 145\r\n" +
+		"DO  - doi:10.1145/1234567\r\n" +
+		"UR  - https://example.com/a\r\n" +
+		"UR  - https://example.com/b\r\n" +
+		"ER  - \r\n" +
+		"\r\n" +
+		"TY  - BOOK\r\n" +
+		"TI  - Only a Start Page\r\n" +
+		"SP  - 10--12\r\n" +
+		"ER  -\r\n"
+
+	refs, err := ParseRIS(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParseRIS() error: %v", err)
+	}
+	want := []Reference{
+		{
+			Type:        "JOUR",
+			CitationKey: "smith2020",
+			Title:       "Deep Learning",
+			Authors:     []string{"Smith, John", "{World Health Organization}", "UNESCO"},
+			Editors:     []string{"Doe, Jane"},
+			Year:        2020,
+			Journal:     "Journal of Tests",
+			Volume:      "63",
+			Issue:       "4",
+			Pages:       "123-145",
+			DOI:         "10.1145/1234567",
+			URL:         "https://example.com/a",
+		},
+		{
+			Type:  "BOOK",
+			Title: "Only a Start Page",
+			Pages: "10-12",
+		},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("ParseRIS() =\n%#v\nwant\n%#v", refs, want)
+	}
+
+	if _, err := ParseRIS(strings.NewReader("AU  - Smith, John\n")); err == nil {
+		t.Error("ParseRIS() accepted a tag outside of a record")
+	}
+	if _, err := ParseRIS(strings.NewReader("TY  - JOUR\nTI  - Open\n")); err == nil {
+		t.Error("ParseRIS() accepted a record without ER")
+	}
+}
+
+func TestParseCSLJSON(t *testing.T) {
+	src := `[
+  {
+    "id": "smith2020",
+    "type": "article-journal",
+    "title": "Deep Learning",
+    "author": [
+      {"family": "Smith", "given": "John"},
+      {"literal": "World Health Organization"},
+      {"family": "Barnes and Noble"},
+      {"family": "Plato"}
+    ],
+    "issued": {"date-parts": [[2020, 3, 1]]},
+    "container-title": "Journal of Tests",
+    "volume": 63,
+    "issue": "4",
+    "page": "123–145",
+    "DOI": "https://doi.org/10.1145/1234567"
+  },
+  {
+    "id": 42,
+    "citation-key": "doe2019",
+    "type": "book",
+    "title": "A Book",
+    "issued": {"raw": "May 2019"}
+  }
+]`
+	refs, err := ParseCSLJSON(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ParseCSLJSON() error: %v", err)
+	}
+	want := []Reference{
+		{
+			Type:        "article-journal",
+			CitationKey: "smith2020",
+			Title:       "Deep Learning",
+			Authors:     []string{"Smith, John", "{World Health Organization}", "{Barnes and Noble}", "Plato"},
+			Year:        2020,
+			Journal:     "Journal of Tests",
+			Volume:      "63",
+			Issue:       "4",
+			Pages:       "123-145",
+			DOI:         "10.1145/1234567",
+		},
+		{
+			Type:        "book",
+			CitationKey: "doe2019",
+			Title:       "A Book",
+			Year:        2019,
+		},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("ParseCSLJSON() =\n%#v\nwant\n%#v", refs, want)
+	}
+
+	single, err := ParseCSLJSON(strings.NewReader(`{"id": "one", "type": "webpage", "title": "Single"}`))
+	if err != nil || len(single) != 1 || single[0].CitationKey != "one" {
+		t.Errorf("ParseCSLJSON(single object) = %#v, %v", single, err)
+	}
+}
+
+// TestBibliographyRoundTrip - 書き出した内容を読み直して同じ書誌情報に戻ることを確認する
+func TestBibliographyRoundTrip(t *testing.T) {
+	refs := []Reference{
+		{
+			Type:        "paper",
+			CitationKey: "smith2020deep",
+			Title:       "Deep Learning & 100% Friends",
+			Authors:     []string{"Smith, John", "{World Health Organization}", "Müller, Hans"},
+			Year:        2020,
+			Journal:     "Journal of Tests",
+			Volume:      "63",
+			Issue:       "4",
+			Pages:       "123-145",
+			DOI:         "10.1145/1234567",
+		},
+		{
+			Type:        "book",
+			CitationKey: "doe2019",
+			Title:       "A Book",
+			Authors:     []string{"Doe, Jane"},
+			Editors:     []string{"{Barnes and Noble}", "Roe, Richard"},
+			Year:        2019,
+			Publisher:   "O'Reilly",
+			ISBN:        "978-0-00-000000-0",
+			URL:         "https://example.com/book",
+		},
+	}
+
+	formats := []struct {
+		name  string
+		write func(*bytes.Buffer, []Reference) error
+		parse func(*bytes.Buffer) ([]Reference, error)
+	}{
+		{
+			name:  "bibtex",
+			write: func(b *bytes.Buffer, refs []Reference) error { return WriteBibTeX(b, refs) },
+			parse: func(b *bytes.Buffer) ([]Reference, error) { return ParseBibTeX(b) },
+		},
+		{
+			name:  "ris",
+			write: func(b *bytes.Buffer, refs []Reference) error { return WriteRIS(b, refs) },
+			parse: func(b *bytes.Buffer) ([]Reference, error) { return ParseRIS(b) },
+		},
+		{
+			name:  "csl-json",
+			write: func(b *bytes.Buffer, refs []Reference) error { return WriteCSLJSON(b, refs) },
+			parse: func(b *bytes.Buffer) ([]Reference, error) { return ParseCSLJSON(b) },
+		},
+	}
+
+	for _, f := range formats {
+		t.Run(f.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := f.write(&buf, refs); err != nil {
+				t.Fatalf("write error: %v", err)
+			}
+			exported := buf.String()
+			got, err := f.parse(&buf)
+			if err != nil {
+				t.Fatalf("parse error: %v\n%s", err, exported)
+			}
+			if len(got) != len(refs) {
+				t.Fatalf("parsed %d references, want %d\n%s", len(got), len(refs), exported)
+			}
+			for i := range got {
+				// 種別は各フォーマットの表記（article, JOUR...）になる
+				got[i].Type = refs[i].Type
+				if !reflect.DeepEqual(got[i], refs[i]) {
+					t.Errorf("round trip =\n%#v\nwant\n%#v\n%s", got[i], refs[i], exported)
+				}
+			}
+		})
+	}
+}
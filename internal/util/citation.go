@@ -0,0 +1,468 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var CitationStyles = []string{"apa", "chicago", "ieee"}
+
+// NormalizeCitationStyle - 大文字小文字を無視してスタイル名を揃える。不正な値は空文字を返す
+func NormalizeCitationStyle(style string) string {
+	style = strings.ToLower(strings.TrimSpace(style))
+	for _, s := range CitationStyles {
+		if s == style {
+			return s
+		}
+	}
+	return ""
+}
+
+// initials - "Donald E." → "D. E."、"Jean-Paul" → "J.-P."
+func initials(given string) string {
+	var parts []string
+	for _, word := range strings.Fields(given) {
+		var hyphenated []string
+		for _, piece := range strings.Split(word, "-") {
+			if r, _ := utf8.DecodeRuneInString(piece); r != utf8.RuneError {
+				hyphenated = append(hyphenated, string(r)+".")
+			}
+		}
+		parts = append(parts, strings.Join(hyphenated, "-"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatName - スタイルごとの著者名表記
+func formatName(name string, style string, first bool) string {
+	family, given := splitName(name)
+	if given == "" {
+		return family
+	}
+	switch style {
+	case "apa":
+		return family + ", " + initials(given)
+	case "ieee":
+		return initials(given) + " " + family
+	default: // chicago: 筆頭著者のみ姓を先に
+		if first {
+			return family + ", " + given
+		}
+		return given + " " + family
+	}
+}
+
+// joinNames - "A, B, and C" の形式で連結（APA は "&"）
+func joinNames(names []string, conjunction string, serialComma bool) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		if serialComma {
+			return names[0] + ", " + conjunction + " " + names[1]
+		}
+		return names[0] + " " + conjunction + " " + names[1]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", " + conjunction + " " + names[len(names)-1]
+}
+
+func formatAuthors(names []string, style string) string {
+	var formatted []string
+	for i, name := range names {
+		formatted = append(formatted, formatName(name, style, i == 0))
+	}
+	switch style {
+	case "apa":
+		if len(formatted) > 20 {
+			formatted = append(formatted[:19], "... "+formatted[len(formatted)-1])
+			return strings.Join(formatted, ", ")
+		}
+		return joinNames(formatted, "&", true)
+	case "ieee":
+		if len(formatted) > 6 {
+			return formatted[0] + " et al."
+		}
+		return joinNames(formatted, "and", false)
+	}
+	// chicago: 筆頭著者が "Family, Given" なので 2 名でもカンマを入れる
+	return joinNames(formatted, "and", true)
+}
+
+// formatEditors - 章の編者（"Given Family" の順）
+func formatEditors(names []string, style string) string {
+	var formatted []string
+	for _, name := range names {
+		family, given := splitName(name)
+		switch {
+		case given == "":
+			formatted = append(formatted, family)
+		case style == "chicago":
+			formatted = append(formatted, given+" "+family)
+		default:
+			formatted = append(formatted, initials(given)+" "+family)
+		}
+	}
+	if style == "apa" {
+		return joinNames(formatted, "&", true)
+	}
+	return joinNames(formatted, "and", false)
+}
+
+// sentence - 末尾に句読点がなければピリオドを付ける
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
+
+func pageRange(pages string) string {
+	return strings.ReplaceAll(pages, "-", "–")
+}
+
+func yearOrND(year int) string {
+	if year > 0 {
+		return fmt.Sprint(year)
+	}
+	return "n.d."
+}
+
+func doiURL(ref Reference) string {
+	if ref.DOI != "" {
+		return "https://doi.org/" + ref.DOI
+	}
+	if ref.ArXiv != "" {
+		return "https://arxiv.org/abs/" + ref.ArXiv
+	}
+	return ref.URL
+}
+
+// FormatReference - 参考文献リスト用の 1 項目を整形する（apa / chicago / ieee）
+func FormatReference(ref Reference, style string) string {
+	kind := referenceKind(ref)
+	if kind == "misc" && ref.Type == "paper" {
+		kind = "article"
+	}
+	var parts []string
+	add := func(s string) {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	switch style {
+	case "ieee":
+		authors := formatAuthors(ref.Authors, style)
+		year := ""
+		if ref.Year > 0 {
+			year = fmt.Sprint(ref.Year)
+		}
+		pages := ""
+		if ref.Pages != "" {
+			pages = "p. " + pageRange(ref.Pages)
+			if strings.Contains(ref.Pages, "-") {
+				pages = "pp. " + pageRange(ref.Pages)
+			}
+		}
+		s := ""
+		if authors != "" {
+			s = authors + ", "
+		}
+		switch kind {
+		case "book", "misc":
+			s += sentence(ref.Title)
+			if tail := strings.Join(nonEmpty(ref.Publisher, year), ", "); tail != "" {
+				s += " " + tail + "."
+			}
+			return s
+		case "web", "video":
+			s += fmt.Sprintf("\"%s.\"", strings.TrimSuffix(ref.Title, "."))
+			if year != "" {
+				s += " " + year + "."
+			}
+			if ref.URL != "" {
+				s += " [Online]. Available: " + ref.URL
+			}
+			return s
+		}
+		s += fmt.Sprintf("\"%s,\"", ref.Title)
+		var rest []string
+		if kind == "chapter" {
+			container := "in " + ref.Journal
+			if len(ref.Editors) > 0 {
+				label := "Ed."
+				if len(ref.Editors) > 1 {
+					label = "Eds."
+				}
+				container += ", " + formatEditors(ref.Editors, style) + ", " + label
+			}
+			rest = append(rest, container)
+			rest = append(rest, nonEmpty(ref.Publisher, year, pages)...)
+		} else {
+			rest = append(rest, nonEmpty(ref.Journal)...)
+			if ref.Volume != "" {
+				rest = append(rest, "vol. "+ref.Volume)
+			}
+			if ref.Issue != "" {
+				rest = append(rest, "no. "+ref.Issue)
+			}
+			rest = append(rest, nonEmpty(pages, year)...)
+		}
+		if ref.DOI != "" {
+			rest = append(rest, "doi: "+ref.DOI)
+		} else if ref.ArXiv != "" {
+			rest = append(rest, "arXiv:"+ref.ArXiv)
+		}
+		if len(rest) > 0 {
+			s += " " + strings.Join(rest, ", ")
+		}
+		return sentence(s)
+
+	case "chicago":
+		title := fmt.Sprintf("\"%s.\"", strings.TrimSuffix(ref.Title, "."))
+		if kind == "book" || kind == "misc" {
+			title = sentence(ref.Title)
+		}
+		// 著者がなければタイトルを先頭に置く
+		if len(ref.Authors) > 0 {
+			add(sentence(formatAuthors(ref.Authors, style)))
+			add(yearOrND(ref.Year) + ".")
+			add(title)
+		} else {
+			add(title)
+			add(yearOrND(ref.Year) + ".")
+		}
+		switch kind {
+		case "book", "misc":
+			add(sentence(ref.Publisher))
+		case "chapter":
+			container := "In " + ref.Journal
+			if len(ref.Editors) > 0 {
+				container += ", edited by " + formatEditors(ref.Editors, style)
+			}
+			if ref.Pages != "" {
+				container += ", " + pageRange(ref.Pages)
+			}
+			add(sentence(container))
+			add(sentence(ref.Publisher))
+		case "article":
+			journal := ref.Journal
+			if ref.Volume != "" {
+				journal += " " + ref.Volume
+			}
+			if ref.Issue != "" {
+				journal += " (" + ref.Issue + ")"
+			}
+			if ref.Pages != "" {
+				journal += ": " + pageRange(ref.Pages)
+			}
+			add(sentence(strings.TrimLeft(journal, " ,:")))
+		}
+		if link := doiURL(ref); link != "" {
+			add(link + ".")
+		}
+		return strings.Join(parts, " ")
+	}
+
+	// apa
+	if len(ref.Authors) > 0 {
+		add(sentence(formatAuthors(ref.Authors, "apa")))
+		add(fmt.Sprintf("(%s).", yearOrND(ref.Year)))
+		add(sentence(ref.Title))
+	} else {
+		add(sentence(ref.Title))
+		add(fmt.Sprintf("(%s).", yearOrND(ref.Year)))
+	}
+	switch kind {
+	case "article":
+		journal := ref.Journal
+		if ref.Volume != "" {
+			journal += ", " + ref.Volume
+			if ref.Issue != "" {
+				journal += "(" + ref.Issue + ")"
+			}
+		}
+		if ref.Pages != "" {
+			journal += ", " + pageRange(ref.Pages)
+		}
+		add(sentence(strings.TrimLeft(journal, " ,:")))
+	case "chapter":
+		container := "In "
+		if len(ref.Editors) > 0 {
+			label := "(Ed.)"
+			if len(ref.Editors) > 1 {
+				label = "(Eds.)"
+			}
+			container += formatEditors(ref.Editors, "apa") + " " + label + ", "
+		}
+		container += ref.Journal
+		if ref.Pages != "" {
+			container += " (pp. " + pageRange(ref.Pages) + ")"
+		}
+		add(sentence(container))
+		add(sentence(ref.Publisher))
+	case "book", "misc":
+		add(sentence(ref.Publisher))
+	}
+	add(doiURL(ref))
+	return strings.Join(parts, " ")
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// authorLabel - 本文中の引用に使う著者表記（姓のみ、3 名以上は et al.）
+func authorLabel(ref Reference, style string) string {
+	var families []string
+	for _, name := range ref.Authors {
+		family, _ := splitName(name)
+		families = append(families, family)
+	}
+	conjunction := "and"
+	if style == "apa" {
+		conjunction = "&"
+	}
+	switch {
+	case len(families) == 0:
+		return fmt.Sprintf("\"%s\"", ref.Title)
+	case len(families) >= 3:
+		return families[0] + " et al."
+	}
+	return joinNames(families, conjunction, false)
+}
+
+// Citation - 本文中の引用 1 件（[@key, p. 42] の key と locator）
+type Citation struct {
+	Key            string
+	Locator        string
+	SuppressAuthor bool // [-@key]
+}
+
+// FormatInText - 本文中の引用を整形する。IEEE の場合は number を使う
+func FormatInText(ref Reference, c Citation, style string, number int) string {
+	var s string
+	switch style {
+	case "ieee":
+		s = fmt.Sprint(number)
+	case "chicago":
+		s = yearOrND(ref.Year)
+		if !c.SuppressAuthor {
+			s = authorLabel(ref, style) + " " + s
+		}
+	default:
+		s = yearOrND(ref.Year)
+		if !c.SuppressAuthor {
+			s = authorLabel(ref, style) + ", " + s
+		}
+	}
+	if c.Locator != "" {
+		s += ", " + c.Locator
+	}
+	return s
+}
+
+var citationGroupPattern = regexp.MustCompile(`\[([^\[\]]*@[^\[\]]*)\]`)
+var citationItemPattern = regexp.MustCompile(`^\s*(-?)@(\w(?:[\w:.#$%&+?<>~/-]*\w)?)\s*(?:,\s*(.*?))?\s*$`)
+
+// parseCitationGroup - "@a, p. 4; -@b" を Citation の一覧に分解。引用でなければ false
+func parseCitationGroup(inner string) ([]Citation, bool) {
+	var citations []Citation
+	for _, item := range strings.Split(inner, ";") {
+		m := citationItemPattern.FindStringSubmatch(item)
+		if m == nil {
+			return nil, false
+		}
+		citations = append(citations, Citation{Key: m[2], Locator: m[3], SuppressAuthor: m[1] == "-"})
+	}
+	return citations, len(citations) > 0
+}
+
+// ResolveCitations - 本文中の [@key] を指定スタイルの引用に置き換え、末尾に参考文献リストを追加する。
+// 見つからない引用キーを含むグループはそのまま残し、そのキーを missing として返す
+func ResolveCitations(body string, lookup func(key string) (Reference, bool), style string) (string, []string) {
+	var cited []Reference
+	numbers := make(map[string]int)
+	var missing []string
+	seenMissing := make(map[string]bool)
+
+	resolved := citationGroupPattern.ReplaceAllStringFunc(body, func(match string) string {
+		citations, ok := parseCitationGroup(match[1 : len(match)-1])
+		if !ok {
+			return match
+		}
+
+		// グループ内のキーがすべて見つかった場合だけ置き換える（一部だけ番号を振ったり参考文献に載せたりしない）
+		refs := make([]Reference, len(citations))
+		complete := true
+		for i, c := range citations {
+			ref, found := lookup(c.Key)
+			if !found {
+				if !seenMissing[c.Key] {
+					seenMissing[c.Key] = true
+					missing = append(missing, c.Key)
+				}
+				complete = false
+				continue
+			}
+			refs[i] = ref
+		}
+		if !complete {
+			return match
+		}
+
+		var rendered []string
+		for i, c := range citations {
+			if _, ok := numbers[c.Key]; !ok {
+				cited = append(cited, refs[i])
+				numbers[c.Key] = len(cited)
+			}
+			rendered = append(rendered, FormatInText(refs[i], c, style, numbers[c.Key]))
+		}
+
+		if style == "ieee" {
+			var bracketed []string
+			for _, r := range rendered {
+				bracketed = append(bracketed, "["+r+"]")
+			}
+			return strings.Join(bracketed, ", ")
+		}
+		return "(" + strings.Join(rendered, "; ") + ")"
+	})
+
+	if len(cited) == 0 {
+		return body, missing
+	}
+
+	// IEEE は引用順、APA / Chicago は著者名順
+	entries := make([]string, len(cited))
+	for i, ref := range cited {
+		entries[i] = FormatReference(ref, style)
+	}
+	if style == "ieee" {
+		for i := range entries {
+			entries[i] = fmt.Sprintf("\\[%d\\] %s", i+1, entries[i])
+		}
+	} else {
+		sort.Strings(entries)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(resolved, "\n"))
+	b.WriteString("\n\n## References\n\n")
+	b.WriteString(strings.Join(entries, "\n\n"))
+	b.WriteString("\n")
+	return b.String(), missing
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveCitationsSkipsIncompleteGroup(t *testing.T) {
+	refs := map[string]Reference{
+		"doe2019":   {CitationKey: "doe2019", Title: "First", Authors: []string{"Doe, Jane"}, Year: 2019},
+		"smith2020": {CitationKey: "smith2020", Title: "Second", Authors: []string{"Smith, John"}, Year: 2020},
+	}
+	lookup := func(key string) (Reference, bool) {
+		ref, ok := refs[key]
+		return ref, ok
+	}
+
+	body := "See [@doe2019; @missing] and [@smith2020].\n"
+	got, missing := ResolveCitations(body, lookup, "ieee")
+
+	if !slices.Equal(missing, []string{"missing"}) {
+		t.Errorf("missing = %v, want [missing]", missing)
+	}
+	if !strings.Contains(got, "[@doe2019; @missing]") {
+		t.Errorf("incomplete group was rendered:\n%s", got)
+	}
+	if !strings.Contains(got, "and [1].") {
+		t.Errorf("complete group is not numbered from 1:\n%s", got)
+	}
+	if strings.Contains(got, "First") || !strings.Contains(got, "\\[1\\]") {
+		t.Errorf("references list includes keys of the incomplete group:\n%s", got)
+	}
+
+	if got, _ := ResolveCitations("[@missing]", lookup, "apa"); got != "[@missing]" {
+		t.Errorf("ResolveCitations() = %q, want the body unchanged", got)
+	}
+}
@@ -0,0 +1,373 @@
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Highlight は電子書籍・PDF から書き出されたハイライト 1 件
+type Highlight struct {
+	Text     string // ハイライトした本文
+	Note     string // ハイライトに付けたメモ
+	Chapter  string
+	Page     string // "42" / "42-43"
+	Location string // Kindle の位置番号 "1234-1236"
+	AddedAt  string // yyyy-mm-dd hh:mm:ss（不明なら空）
+}
+
+// HighlightBook は 1 冊（1 ファイル）分のハイライト
+type HighlightBook struct {
+	Title      string
+	Author     string // "Family, Given; Family, Given" または書き出し元の表記のまま
+	Highlights []Highlight
+}
+
+var HighlightFormats = []string{"kindle", "csv", "pdf-json"}
+
+// bookIndex は書名・著者ごとに HighlightBook をまとめる（出現順を保つ）
+type bookIndex struct {
+	books []HighlightBook
+	index map[string]int
+}
+
+func (b *bookIndex) add(title, author string, h Highlight) {
+	if b.index == nil {
+		b.index = make(map[string]int)
+	}
+	key := strings.ToLower(title) + "\x00" + strings.ToLower(author)
+	i, ok := b.index[key]
+	if !ok {
+		b.books = append(b.books, HighlightBook{Title: title, Author: author})
+		i = len(b.books) - 1
+		b.index[key] = i
+	}
+	b.books[i].Highlights = append(b.books[i].Highlights, h)
+}
+
+var (
+	kindleSeparator = regexp.MustCompile(`(?m)^=+\s*$`)
+	kindleAuthor    = regexp.MustCompile(`^(.*)\(([^()]*)\)\s*$`)
+	kindleKind      = regexp.MustCompile(`(?i)your\s+(highlight|note|bookmark|clip)`)
+	kindlePage      = regexp.MustCompile(`(?i)\bpage\s+([0-9ivxlc]+(?:-[0-9ivxlc]+)?)`)
+	kindleLocation  = regexp.MustCompile(`(?i)\blocation\s+(\d+(?:-\d+)?)`)
+	kindleAdded     = regexp.MustCompile(`(?i)added on\s+(.+)$`)
+)
+
+var kindleDateLayouts = []string{
+	"Monday, January 2, 2006 3:04:05 PM",
+	"Monday, 2 January 2006 15:04:05",
+	"Monday, January 2, 2006 15:04:05",
+	"Monday, 2 January 2006 3:04:05 PM",
+}
+
+// parseKindleDate は "Added on ..." の日時を yyyy-mm-dd hh:mm:ss に変換する（解釈できなければ空）
+func parseKindleDate(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	for _, layout := range kindleDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02 15:04:05")
+		}
+	}
+	return ""
+}
+
+// locationRange は "1234-1236" を (1234, 1236) に変換する
+func locationRange(location string) (int, int) {
+	start, end, _ := strings.Cut(location, "-")
+	s, _ := strconv.Atoi(start)
+	e, err := strconv.Atoi(end)
+	if err != nil || e < s {
+		e = s
+	}
+	// Kindle は "1234-36" のように上位桁を省略することがある
+	if end != "" && len(end) < len(start) {
+		if full, err := strconv.Atoi(start[:len(start)-len(end)] + end); err == nil && full >= s {
+			e = full
+		}
+	}
+	return s, e
+}
+
+// ParseKindleClippings は Kindle の "My Clippings.txt" を書籍ごとのハイライトに変換する。
+// メモは同じ位置のハイライトにまとめ、ブックマークと重複したハイライトは除く
+func ParseKindleClippings(r io.Reader) ([]HighlightBook, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	type pendingNote struct {
+		title, author string
+		highlight     Highlight
+	}
+	var books bookIndex
+	var notes []pendingNote
+	seen := make(map[string]bool)
+
+	for _, entry := range kindleSeparator.Split(text, -1) {
+		lines := strings.Split(strings.Trim(entry, "\n"), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+
+		title, author := strings.TrimSpace(lines[0]), ""
+		if m := kindleAuthor.FindStringSubmatch(title); m != nil {
+			title, author = strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+		}
+		meta := lines[1]
+		kind := "highlight"
+		if m := kindleKind.FindStringSubmatch(meta); m != nil {
+			kind = strings.ToLower(m[1])
+		}
+
+		h := Highlight{}
+		if m := kindlePage.FindStringSubmatch(meta); m != nil {
+			h.Page = m[1]
+		}
+		if m := kindleLocation.FindStringSubmatch(meta); m != nil {
+			h.Location = m[1]
+			if start, end := locationRange(m[1]); end > start {
+				h.Location = fmt.Sprintf("%d-%d", start, end)
+			}
+		}
+		if m := kindleAdded.FindStringSubmatch(meta); m != nil {
+			h.AddedAt = parseKindleDate(m[1])
+		}
+		body := strings.TrimSpace(strings.Join(lines[2:], "\n"))
+
+		switch kind {
+		case "bookmark":
+			continue
+		case "note":
+			h.Note = body
+			notes = append(notes, pendingNote{title: title, author: author, highlight: h})
+			continue
+		}
+		if body == "" {
+			continue
+		}
+		h.Text = body
+
+		key := title + "\x00" + h.Location + "\x00" + h.Page + "\x00" + body
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		books.add(title, author, h)
+	}
+
+	// メモは位置が重なるハイライトに付ける。見つからなければメモだけのハイライトとして残す
+	for _, n := range notes {
+		attached := false
+		if i, ok := books.index[strings.ToLower(n.title)+"\x00"+strings.ToLower(n.author)]; ok && n.highlight.Location != "" {
+			noteLoc, _ := locationRange(n.highlight.Location)
+			highlights := books.books[i].Highlights
+			for j := len(highlights) - 1; j >= 0; j-- {
+				if highlights[j].Location == "" {
+					continue
+				}
+				start, end := locationRange(highlights[j].Location)
+				if noteLoc >= start && noteLoc <= end {
+					if highlights[j].Note != "" {
+						highlights[j].Note += "\n\n"
+					}
+					highlights[j].Note += n.highlight.Note
+					attached = true
+					break
+				}
+			}
+		}
+		if !attached && n.highlight.Note != "" {
+			books.add(n.title, n.author, n.highlight)
+		}
+	}
+	return books.books, nil
+}
+
+// csvColumns は Readwise / Kobo などの CSV の列名の候補（小文字・空白なし）
+var csvColumns = map[string][]string{
+	"text":          {"highlight", "text", "quote", "highlightedtext"},
+	"title":         {"booktitle", "title", "book"},
+	"author":        {"bookauthor", "author", "authors", "attribution"},
+	"note":          {"note", "notes", "annotation", "comment"},
+	"chapter":       {"chapter", "chaptertitle", "chaptername", "section"},
+	"page":          {"page", "pagenumber"},
+	"location":      {"location", "loc"},
+	"location_type": {"locationtype"},
+	"date":          {"highlightedat", "datecreated", "created", "date", "addedat"},
+}
+
+var csvDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.000",
+	"2006-01-02",
+	"January 2, 2006 3:04 PM",
+	"01/02/2006",
+}
+
+func parseHighlightDate(value string) string {
+	value = strings.TrimSpace(value)
+	for _, layout := range csvDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02 15:04:05")
+		}
+	}
+	return ""
+}
+
+// ParseHighlightCSV は Readwise / Kobo 形式の CSV を書籍ごとのハイライトに変換する。
+// 列は見出し名で判定するため、並び順や余分な列は問わない
+func ParseHighlightCSV(r io.Reader) ([]HighlightBook, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		name := strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(header)))
+		for field, aliases := range csvColumns {
+			for _, alias := range aliases {
+				if _, taken := columns[field]; name == alias && !taken {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	if _, ok := columns["text"]; !ok {
+		return nil, fmt.Errorf("CSV has no highlight column (expected one of: Highlight, Text, Quote)")
+	}
+
+	var books bookIndex
+	for _, record := range records[1:] {
+		get := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		h := Highlight{
+			Text:    get("text"),
+			Note:    get("note"),
+			Chapter: get("chapter"),
+			Page:    get("page"),
+			AddedAt: parseHighlightDate(get("date")),
+		}
+		if h.Text == "" && h.Note == "" {
+			continue
+		}
+		// Readwise は Location Type（page / location / order）で Location の意味が変わる
+		if location := get("location"); location != "" {
+			switch strings.ToLower(get("location_type")) {
+			case "page":
+				if h.Page == "" {
+					h.Page = location
+				}
+			case "order", "offset":
+			default:
+				h.Location = location
+			}
+		}
+
+		// Readwise は複数著者を "A and B" / "A, B" で並べる
+		author := strings.ReplaceAll(get("author"), " and ", "; ")
+		books.add(get("title"), author, h)
+	}
+	return books.books, nil
+}
+
+// ParsePDFAnnotationsJSON は注釈付き PDF の JSON ダンプ（pdfannots などの出力）をハイライトに変換する。
+// 注釈の配列、または {"title", "author", "annotations": [...]} の形式を受け付ける
+func ParsePDFAnnotationsJSON(data []byte) (HighlightBook, error) {
+	var book HighlightBook
+	var annotations []map[string]any
+
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		var wrapper map[string]any
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return book, fmt.Errorf("invalid JSON: %w", err)
+		}
+		book.Title = jsonString(wrapper, "title")
+		book.Author = jsonString(wrapper, "author", "authors")
+		items, _ := firstValue(wrapper, "annotations", "highlights").([]any)
+		for _, item := range items {
+			if m, ok := item.(map[string]any); ok {
+				annotations = append(annotations, m)
+			}
+		}
+	}
+
+	for _, a := range annotations {
+		switch strings.ToLower(jsonString(a, "type", "subtype")) {
+		case "", "highlight", "underline", "squiggly", "strikeout", "text", "note", "freetext":
+		default:
+			continue // ink・link などの注釈は取り込まない
+		}
+
+		h := Highlight{
+			Text:    jsonString(a, "text", "highlighted_text", "quote"),
+			Note:    jsonString(a, "contents", "comment", "note"),
+			Chapter: jsonString(a, "chapter", "prior_outline", "outline"),
+			Page:    jsonString(a, "page", "page_label"),
+			AddedAt: parseHighlightDate(jsonString(a, "created", "date", "modified")),
+		}
+		if h.Text == "" && h.Note == "" {
+			continue
+		}
+		book.Highlights = append(book.Highlights, h)
+	}
+	return book, nil
+}
+
+func firstValue(m map[string]any, keys ...string) any {
+	for _, key := range keys {
+		if v, ok := m[key]; ok && v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// jsonString は最初に見つかったキーの値を文字列で返す（数値・文字列の配列も受け付ける）
+func jsonString(m map[string]any, keys ...string) string {
+	switch v := firstValue(m, keys...).(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		var parts []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				parts = append(parts, strings.TrimSpace(s))
+			}
+		}
+		return strings.Join(parts, "; ")
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func openHighlightFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "highlights", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestParseKindleClippings(t *testing.T) {
+	// BOM 付き・CRLF の My Clippings.txt
+	books, err := ParseKindleClippings(openHighlightFixture(t, "kindle_clippings.txt"))
+	if err != nil {
+		t.Fatalf("ParseKindleClippings() error: %v", err)
+	}
+
+	want := []HighlightBook{
+		{
+			Title:  "The Pragmatic Programmer",
+			Author: "Hunt, Andrew; Thomas, David",
+			Highlights: []Highlight{
+				// 同じハイライトの重複は除き、位置が重なるメモを付ける
+				{Text: "Care about your craft.", Note: "Why else spend a life on it?", Page: "12", Location: "180-182", AddedAt: "2025-01-06 09:15:02"},
+				// 上位桁を省略した位置番号は補う
+				{Text: "Don't live with broken windows.", Location: "1234-1236", AddedAt: "2025-01-07 21:03:10"},
+				// 付け先のないメモはメモだけのハイライトとして残す
+				{Note: "A note without a highlight.", Location: "2000", AddedAt: "2025-01-07 21:05:00"},
+			},
+		},
+		{
+			Title: "Deep Work",
+			Highlights: []Highlight{
+				{Text: "Clarity about what matters\nprovides clarity about what does not.", Page: "xiv", AddedAt: "2025-01-08 19:00:00"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(books, want) {
+		t.Errorf("ParseKindleClippings() =\n%#v\nwant\n%#v", books, want)
+	}
+}
+
+func TestLocationRange(t *testing.T) {
+	tests := []struct {
+		location   string
+		start, end int
+	}{
+		{"1234", 1234, 1234},
+		{"1234-1236", 1234, 1236},
+		{"1234-36", 1234, 1236},
+		{"998-1002", 998, 1002},
+		{"1236-1234", 1236, 1236},
+		{"abc", 0, 0},
+	}
+	for _, tt := range tests {
+		if start, end := locationRange(tt.location); start != tt.start || end != tt.end {
+			t.Errorf("locationRange(%q) = (%d, %d), want (%d, %d)", tt.location, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestParseHighlightCSV(t *testing.T) {
+	// BOM 付き・CRLF の Readwise 形式
+	books, err := ParseHighlightCSV(openHighlightFixture(t, "readwise.csv"))
+	if err != nil {
+		t.Fatalf("ParseHighlightCSV() error: %v", err)
+	}
+
+	want := []HighlightBook{
+		{
+			Title:  "The Pragmatic Programmer",
+			Author: "Andrew Hunt; David Thomas",
+			Highlights: []Highlight{
+				// Location Type が page なら Location はページ番号
+				{Text: "Care about your craft.", Note: "Why else?", Chapter: "Preface", Page: "12", AddedAt: "2025-01-06 09:15:02"},
+				{Text: "Don't live with \"broken\" windows.\nIt spreads.", Chapter: "Software Entropy", Location: "1234", AddedAt: "2025-01-07 21:03:10"},
+				// order は位置として使わない
+				{Text: "Ordered highlight", Chapter: "Software Entropy"},
+			},
+		},
+		{
+			Title:  "Deep Work",
+			Author: "Cal Newport",
+			Highlights: []Highlight{
+				{Note: "Only a note", AddedAt: "2025-01-08 00:00:00"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(books, want) {
+		t.Errorf("ParseHighlightCSV() =\n%#v\nwant\n%#v", books, want)
+	}
+
+	if _, err := ParseHighlightCSV(strings.NewReader("Title,Author\nA,B\n")); err == nil {
+		t.Error("ParseHighlightCSV() accepted a CSV without a highlight column")
+	}
+	if books, err := ParseHighlightCSV(strings.NewReader("")); err != nil || books != nil {
+		t.Errorf("ParseHighlightCSV(empty) = %#v, %v", books, err)
+	}
+}
+
+func TestParsePDFAnnotationsJSON(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "highlights", "pdfannots.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	book, err := ParsePDFAnnotationsJSON(data)
+	if err != nil {
+		t.Fatalf("ParsePDFAnnotationsJSON() error: %v", err)
+	}
+
+	want := HighlightBook{
+		Title:  "Attention Is All You Need",
+		Author: "Vaswani, Ashish; Shazeer, Noam",
+		Highlights: []Highlight{
+			{Text: "The Transformer follows this overall architecture", Note: "Key idea", Chapter: "3 Model Architecture", Page: "3", AddedAt: "2025-01-09 10:00:00"},
+			// ページ番号はラベル（ローマ数字）でもよい。ink の注釈と空のハイライトは除く
+			{Note: "Sticky note only", Page: "iv"},
+		},
+	}
+	if !reflect.DeepEqual(book, want) {
+		t.Errorf("ParsePDFAnnotationsJSON() =\n%#v\nwant\n%#v", book, want)
+	}
+
+	// 注釈の配列だけの形式
+	book, err = ParsePDFAnnotationsJSON([]byte(`[{"page": 7, "text": "Plain array"}]`))
+	if err != nil {
+		t.Fatalf("ParsePDFAnnotationsJSON(array) error: %v", err)
+	}
+	if want := []Highlight{{Text: "Plain array", Page: "7"}}; book.Title != "" || !reflect.DeepEqual(book.Highlights, want) {
+		t.Errorf("ParsePDFAnnotationsJSON(array) = %#v", book)
+	}
+
+	if _, err := ParsePDFAnnotationsJSON([]byte("not json")); err == nil {
+		t.Error("ParsePDFAnnotationsJSON() accepted invalid JSON")
+	}
+}
@@ -0,0 +1,249 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// VTodo - iCalendar (RFC 5545) の VTODO コンポーネント
+type VTodo struct {
+	UID          string
+	Summary      string
+	Description  string
+	Status       string // NEEDS-ACTION, IN-PROCESS, COMPLETED, CANCELLED
+	Due          string // yyyy-mm-dd
+	Start        string // yyyy-mm-dd
+	Priority     int    // 0 (未定義), 1 (最高) … 9 (最低)
+	Categories   []string
+	RRule        string
+	Created      string            // yyyymmddThhmmssZ
+	LastModified string            // yyyymmddThhmmssZ
+	Extra        map[string]string // X-* プロパティ
+}
+
+// icalEscape - TEXT 値のエスケープ
+func icalEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// icalUnescape - TEXT 値のエスケープ解除
+func icalUnescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitEscaped - エスケープされていない区切り文字で分割
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == sep {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// foldLine - 75 オクテットごとに折り返す（UTF-8 の文字境界は保つ）
+func foldLine(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String() + "\r\n"
+}
+
+func icalDate(date string) string {
+	return strings.ReplaceAll(date, "-", "")
+}
+
+// WriteICalendar - VTODO の一覧を VCALENDAR として書き出す
+func WriteICalendar(w io.Writer, todos []VTodo, stamp string) error {
+	var b strings.Builder
+	write := func(line string) { b.WriteString(foldLine(line)) }
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:-//ztl-cli//ztl//EN")
+	write("CALSCALE:GREGORIAN")
+	for _, todo := range todos {
+		write("BEGIN:VTODO")
+		write("UID:" + todo.UID)
+		write("DTSTAMP:" + stamp)
+		if todo.Created != "" {
+			write("CREATED:" + todo.Created)
+		}
+		if todo.LastModified != "" {
+			write("LAST-MODIFIED:" + todo.LastModified)
+		}
+		write("SUMMARY:" + icalEscape(todo.Summary))
+		if todo.Description != "" {
+			write("DESCRIPTION:" + icalEscape(todo.Description))
+		}
+		if todo.Status != "" {
+			write("STATUS:" + todo.Status)
+		}
+		if todo.Start != "" {
+			write("DTSTART;VALUE=DATE:" + icalDate(todo.Start))
+		}
+		if todo.Due != "" {
+			write("DUE;VALUE=DATE:" + icalDate(todo.Due))
+		}
+		if todo.Priority > 0 {
+			write(fmt.Sprintf("PRIORITY:%d", todo.Priority))
+		}
+		if len(todo.Categories) > 0 {
+			var escaped []string
+			for _, c := range todo.Categories {
+				escaped = append(escaped, icalEscape(c))
+			}
+			write("CATEGORIES:" + strings.Join(escaped, ","))
+		}
+		if todo.RRule != "" {
+			write("RRULE:" + todo.RRule)
+		}
+		var extraKeys []string
+		for key := range todo.Extra {
+			extraKeys = append(extraKeys, key)
+		}
+		sort.Strings(extraKeys)
+		for _, key := range extraKeys {
+			write(key + ":" + icalEscape(todo.Extra[key]))
+		}
+		write("END:VTODO")
+	}
+	write("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// parseICalDate - DATE / DATE-TIME 値を yyyy-mm-dd に変換
+func parseICalDate(value string) string {
+	if len(value) < 8 {
+		return ""
+	}
+	return value[0:4] + "-" + value[4:6] + "-" + value[6:8]
+}
+
+// ParseICalendar - iCalendar から VTODO を読み取る（VEVENT などは無視）
+func ParseICalendar(r io.Reader) ([]VTodo, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+
+	// 折り返された行を連結
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read iCalendar: %w", err)
+	}
+
+	var todos []VTodo
+	var current *VTodo
+	depth := 0 // VTODO 内の VALARM などを無視するためのネスト
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: invalid content line %q", n+1, line)
+		}
+		nameAndParams, value := line[:colon], line[colon+1:]
+		name := strings.ToUpper(strings.SplitN(nameAndParams, ";", 2)[0])
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VTODO"):
+			current = &VTodo{Extra: map[string]string{}}
+			depth = 0
+			continue
+		case name == "END" && strings.EqualFold(value, "VTODO"):
+			if current != nil {
+				todos = append(todos, *current)
+			}
+			current = nil
+			continue
+		case current == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END":
+			depth--
+			continue
+		case depth > 0:
+			continue
+		}
+
+		switch name {
+		case "UID":
+			current.UID = value
+		case "SUMMARY":
+			current.Summary = icalUnescape(value)
+		case "DESCRIPTION":
+			current.Description = icalUnescape(value)
+		case "STATUS":
+			current.Status = strings.ToUpper(value)
+		case "DUE":
+			current.Due = parseICalDate(value)
+		case "DTSTART":
+			current.Start = parseICalDate(value)
+		case "PRIORITY":
+			fmt.Sscanf(value, "%d", &current.Priority)
+		case "CATEGORIES":
+			for _, c := range splitEscaped(value, ',') {
+				if c = strings.TrimSpace(icalUnescape(c)); c != "" {
+					current.Categories = append(current.Categories, c)
+				}
+			}
+		case "RRULE":
+			current.RRule = value
+		case "CREATED":
+			current.Created = value
+		case "LAST-MODIFIED":
+			current.LastModified = value
+		default:
+			if strings.HasPrefix(name, "X-") {
+				current.Extra[name] = icalUnescape(value)
+			}
+		}
+	}
+
+	return todos, nil
+}
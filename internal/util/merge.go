@@ -0,0 +1,120 @@
+package util
+
+import (
+	"strings"
+)
+
+// 行数の積がこれを超える場合はマージを諦めて競合として扱う（LCS の表が大きくなりすぎるため）
+const maxMergeCells = 16_000_000
+
+// splitLines は改行を保ったまま行に分割する
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.SplitAfter(s, "\n")
+}
+
+// matchLines は LCS で base の各行が other の何行目に対応するかを返す（対応しない行は -1）
+func matchLines(base, other []string) []int {
+	n, m := len(base), len(other)
+	match := make([]int, n)
+	for i := range match {
+		match[i] = -1
+	}
+
+	// lcs[i][j] = base[i:] と other[j:] の LCS の長さ
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if base[i] == other[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case base[i] == other[j]:
+			match[i] = j
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge3 は base から ours / theirs への変更を行単位でまとめる（diff3）。
+// 両方が同じ箇所を別々に変更していた場合は false を返す
+func Merge3(base, ours, theirs string) (string, bool) {
+	switch {
+	case ours == theirs || theirs == base:
+		return ours, true
+	case ours == base:
+		return theirs, true
+	}
+
+	o, a, b := splitLines(base), splitLines(ours), splitLines(theirs)
+	if len(o)*len(a) > maxMergeCells || len(o)*len(b) > maxMergeCells {
+		return "", false
+	}
+	matchA, matchB := matchLines(o, a), matchLines(o, b)
+
+	var merged strings.Builder
+	i, j, k := 0, 0, 0
+	for {
+		// 3 つとも一致している区間はそのまま残す
+		for i < len(o) && matchA[i] == j && matchB[i] == k {
+			merged.WriteString(o[i])
+			i, j, k = i+1, j+1, k+1
+		}
+		if i == len(o) && j == len(a) && k == len(b) {
+			break
+		}
+
+		// 次に 3 つとも一致する base の行までを 1 つの変更箇所とみなす
+		next := i
+		for next < len(o) && (matchA[next] < 0 || matchB[next] < 0) {
+			next++
+		}
+		aEnd, bEnd := len(a), len(b)
+		if next < len(o) {
+			aEnd, bEnd = matchA[next], matchB[next]
+		}
+
+		chunkO, chunkA, chunkB := o[i:next], a[j:aEnd], b[k:bEnd]
+		switch {
+		case equalLines(chunkA, chunkO):
+			merged.WriteString(strings.Join(chunkB, ""))
+		case equalLines(chunkB, chunkO), equalLines(chunkA, chunkB):
+			merged.WriteString(strings.Join(chunkA, ""))
+		default:
+			return "", false
+		}
+		i, j, k = next, aEnd, bEnd
+	}
+	return merged.String(), true
+}
@@ -0,0 +1,85 @@
+package util
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	base := "title\n\nalpha\nbeta\ngamma\n"
+	tests := []struct {
+		name   string
+		ours   string
+		theirs string
+		want   string
+		ok     bool
+	}{
+		{
+			name:   "unchanged",
+			ours:   base,
+			theirs: base,
+			want:   base,
+			ok:     true,
+		},
+		{
+			name:   "only ours changed",
+			ours:   "title\n\nALPHA\nbeta\ngamma\n",
+			theirs: base,
+			want:   "title\n\nALPHA\nbeta\ngamma\n",
+			ok:     true,
+		},
+		{
+			name:   "only theirs changed",
+			ours:   base,
+			theirs: "title\n\nalpha\nbeta\ngamma\ndelta\n",
+			want:   "title\n\nalpha\nbeta\ngamma\ndelta\n",
+			ok:     true,
+		},
+		{
+			name:   "different lines changed",
+			ours:   "title\n\nALPHA\nbeta\ngamma\n",
+			theirs: "title\n\nalpha\nbeta\nGAMMA\n",
+			want:   "title\n\nALPHA\nbeta\nGAMMA\n",
+			ok:     true,
+		},
+		{
+			name:   "insert and delete",
+			ours:   "title\n\nalpha\nbeta\nbeta2\ngamma\n",
+			theirs: "title\n\nbeta\ngamma\n",
+			want:   "title\n\nbeta\nbeta2\ngamma\n",
+			ok:     true,
+		},
+		{
+			name:   "same change on both sides",
+			ours:   "title\n\nalpha\nBETA\ngamma\n",
+			theirs: "title\n\nalpha\nBETA\ngamma\n",
+			want:   "title\n\nalpha\nBETA\ngamma\n",
+			ok:     true,
+		},
+		{
+			name:   "same line changed differently",
+			ours:   "title\n\nalpha\nours\ngamma\n",
+			theirs: "title\n\nalpha\ntheirs\ngamma\n",
+			ok:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Merge3(base, tt.ours, tt.theirs)
+			if ok != tt.ok {
+				t.Fatalf("Merge3() ok = %v, want %v (got %q)", ok, tt.ok, got)
+			}
+			if ok && got != tt.want {
+				t.Errorf("Merge3() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge3EmptyBase(t *testing.T) {
+	// 前回同期時の内容がなければ、両方で追加した内容は競合になる
+	if _, ok := Merge3("", "ours\n", "theirs\n"); ok {
+		t.Error("Merge3() with empty base merged different contents")
+	}
+	if got, ok := Merge3("", "same\n", "same\n"); !ok || got != "same\n" {
+		t.Errorf("Merge3() = %q, %v, want %q, true", got, ok, "same\n")
+	}
+}
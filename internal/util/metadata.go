@@ -2,244 +2,224 @@ package util
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"log"
-	"os"
+	"io/fs"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/nakachan-ing/ztl-cli/internal/model"
 )
 
-// GenerateMetadata - 指定ディレクトリのファイル一覧と更新日時を取得
-func GenerateMetadata(dir string) (map[string]string, error) {
-	metadata := make(map[string]string)
-
-	files, err := filepath.Glob(filepath.Join(dir, "*"))
-	if err != nil {
-		return nil, fmt.Errorf("❌ Failed to scan directory: %w", err)
-	}
-
-	for _, filePath := range files {
-		info, err := os.Stat(filePath)
-		if err != nil {
-			log.Printf("⚠️ Failed to get file info: %s (%v)", filePath, err)
-			continue
-		}
-
-		metadata[filepath.Base(filePath)] = info.ModTime().Format(time.RFC3339)
-	}
+// 同期先のディレクトリごとの manifest（ファイル名 → ManifestEntry）
+const ManifestName = "metadata.json"
 
-	return metadata, nil
+// ManifestEntry は manifest の 1 ファイル分。削除したファイルも tombstone として残し、他の端末に削除を伝える
+type ManifestEntry struct {
+	Hash      string `json:"hash,omitempty"` // 内容の SHA-256（削除済みなら空）
+	Version   string `json:"version"`        // アップロード日時
+	Deleted   bool   `json:"deleted,omitempty"`
+	DeletedAt string `json:"deleted_at,omitempty"` // yyyy-mm-dd hh:mm:ss
 }
 
-// SaveMetadata - metadata.json をローカルに保存
-func SaveMetadata(metadataPath string, metadata map[string]string) error {
-	data, err := json.MarshalIndent(metadata, "", "  ")
-	if err != nil {
-		return fmt.Errorf("❌ Failed to marshal metadata.json: %w", err)
+// UnmarshalJSON は旧形式（バージョンの文字列のみ）も受け付ける
+func (m *ManifestEntry) UnmarshalJSON(data []byte) error {
+	var version string
+	if err := json.Unmarshal(data, &version); err == nil {
+		*m = ManifestEntry{Version: version}
+		return nil
 	}
-
-	err = os.WriteFile(metadataPath, data, 0644)
-	if err != nil {
-		return fmt.Errorf("❌ Failed to write metadata.json: %w", err)
-	}
-
-	log.Println("✅ metadata.json updated!")
-	return nil
+	type entry ManifestEntry
+	return json.Unmarshal(data, (*entry)(m))
 }
 
-// LoadMetadata - metadata.json をロード
-func LoadMetadata(metadataPath string) (map[string]string, error) {
-	data, err := os.ReadFile(metadataPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return make(map[string]string), nil
-		}
-		return nil, fmt.Errorf("❌ Failed to read metadata.json: %w", err)
-	}
+// 競合コピーのファイル名に付ける印（20250101120000.conflict-20250102-150405.md）
+const conflictMarker = ".conflict-"
 
-	var metadata map[string]string
-	err = json.Unmarshal(data, &metadata)
-	if err != nil {
-		return nil, fmt.Errorf("❌ Failed to parse metadata.json: %w", err)
-	}
+// HashBytes - 内容の SHA-256 を 16 進数で返す
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
 
-	return metadata, nil
+// IsConflictCopy - 同期で作った競合コピーか判定
+func IsConflictCopy(name string) bool {
+	return strings.Contains(name, conflictMarker)
 }
 
-func UploadMetadataToS3(s3Client *s3.Client, config model.Config, dirType string) error {
-	var metadataPath string
-	var s3Key string
-
-	// ディレクトリタイプに応じてパスを設定
-	switch dirType {
-	case "notes":
-		metadataPath = filepath.Join(config.ZettelDir, "metadata.json")
-		s3Key = "notes/metadata.json"
-	case "json":
-		metadataPath = filepath.Join(config.JsonDataDir, "metadata.json")
-		s3Key = "json/metadata.json"
-	default:
-		return fmt.Errorf("❌ Invalid directory type: %s", dirType)
-	}
+// ConflictCopyPath - 競合コピーの保存先（元のファイルと同じディレクトリ）
+func ConflictCopyPath(path string, t time.Time) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + conflictMarker + t.Format("20060102-150405") + ext
+}
 
-	// ファイルを開く
-	file, err := os.Open(metadataPath)
-	if err != nil {
-		return fmt.Errorf("❌ Failed to open %s: %w", metadataPath, err)
+// ListSyncFiles - 指定ディレクトリ以下の同期対象のファイルを "/" 区切りの相対パスで返す。
+// manifest・競合コピー・隠しファイル（隠しディレクトリ以下を含む）と skipDirs 以下は除く
+func ListSyncFiles(dir string, skipDirs []string) ([]string, error) {
+	skip := make(map[string]bool)
+	for _, d := range skipDirs {
+		if d != "" {
+			skip[filepath.Clean(d)] = true
+		}
 	}
-	defer file.Close()
 
-	// S3 にアップロード
-	_, err = s3Client.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(config.Sync.Bucket),
-		Key:    aws.String(s3Key),
-		Body:   file,
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if strings.HasPrefix(name, ".") || skip[filepath.Clean(p)] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(name, ".") || IsConflictCopy(name) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); rel != ManifestName {
+			files = append(files, rel)
+		}
+		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("❌ Failed to upload %s to S3: %w", s3Key, err)
+		return nil, fmt.Errorf("❌ Failed to scan directory: %w", err)
 	}
-
-	log.Printf("✅ %s uploaded to S3!", s3Key)
-	return nil
+	return files, nil
 }
 
-func DownloadMetadataFromS3(s3Client *s3.Client, config model.Config, dirType string) (map[string]string, error) {
-	var metadataPath string
-	var s3Key string
-
-	// ディレクトリタイプに応じてパスを設定
-	switch dirType {
-	case "notes":
-		metadataPath = filepath.Join(config.ZettelDir, "metadata.json")
-		s3Key = "notes/metadata.json"
-	case "json":
-		metadataPath = filepath.Join(config.JsonDataDir, "metadata.json")
-		s3Key = "json/metadata.json"
-	default:
-		return nil, fmt.Errorf("❌ Invalid directory type: %s", dirType)
-	}
-
-	// S3 から `metadata.json` を取得
-	resp, err := s3Client.GetObject(context.TODO(), &s3.GetObjectInput{
-		Bucket: aws.String(config.Sync.Bucket),
-		Key:    aws.String(s3Key),
-	})
-	if err != nil {
-		if isNotFoundErr(err) {
-			log.Printf("⚠️ No %s found on S3, returning empty metadata.", s3Key)
-			return make(map[string]string), nil // S3 にない場合は空の `map` を返す
+// globToRegexp - glob を正規表現に変換する（** は任意の階層、* と ? は "/" を含まない）
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid pattern %q: missing ]", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
 		}
-		return nil, fmt.Errorf("❌ Failed to download %s from S3: %w", s3Key, err)
 	}
-	defer resp.Body.Close()
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
 
-	// `metadata.json` をローカルに保存
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("❌ Failed to read %s from S3: %w", s3Key, err)
-	}
+// SyncFilter は sync.include / sync.exclude のパターン
+type SyncFilter struct {
+	include, exclude []*regexp.Regexp
+	includeBase      []bool
+	excludeBase      []bool
+}
 
-	err = os.WriteFile(metadataPath, data, 0644)
-	if err != nil {
-		return nil, fmt.Errorf("❌ Failed to save %s: %w", metadataPath, err)
+// NewSyncFilter - パターンを検証して SyncFilter を作る。
+// "/" を含むパターンは同期先のキー（notes/xxx.md など）全体に、含まないパターンはファイル名に一致させる
+func NewSyncFilter(include, exclude []string) (*SyncFilter, error) {
+	f := &SyncFilter{}
+	compile := func(patterns []string) ([]*regexp.Regexp, []bool, error) {
+		var res []*regexp.Regexp
+		var base []bool
+		for _, pattern := range patterns {
+			if pattern = strings.TrimSpace(pattern); pattern == "" {
+				continue
+			}
+			re, err := globToRegexp(strings.TrimPrefix(pattern, "/"))
+			if err != nil {
+				return nil, nil, err
+			}
+			res = append(res, re)
+			base = append(base, !strings.Contains(pattern, "/"))
+		}
+		return res, base, nil
 	}
 
-	log.Printf("✅ %s downloaded from S3!", s3Key)
-
-	// `metadata.json` をロード
-	metadata, err := LoadMetadata(metadataPath)
-	if err != nil {
-		return nil, fmt.Errorf("❌ Failed to load downloaded metadata: %w", err)
+	var err error
+	if f.include, f.includeBase, err = compile(include); err != nil {
+		return nil, fmt.Errorf("❌ Invalid sync.include: %w", err)
 	}
-
-	return metadata, nil
-}
-
-func SyncFilesToS3(config model.Config, direction string, fileList []string) error {
-	s3Client, err := NewS3Client(config)
-	if err != nil {
-		return fmt.Errorf("❌ Failed to initialize S3 client: %w", err)
+	if f.exclude, f.excludeBase, err = compile(exclude); err != nil {
+		return nil, fmt.Errorf("❌ Invalid sync.exclude: %w", err)
 	}
+	return f, nil
+}
 
-	bucket := config.Sync.Bucket
-
-	for _, file := range fileList {
-		var s3Key string
-		var localPath string
-
-		// `notes/` の場合は `ZettelDir` を基準にする
-		// cleanFile := filepath.Clean(file)
-
-		if strings.HasPrefix(file, "notes/") || strings.HasSuffix(file, ".md") {
-			localPath = filepath.Join(config.ZettelDir, file)
-			s3Key = "notes/" + filepath.Base(file)
-		} else if strings.HasPrefix(file, "json/") || strings.HasSuffix(file, ".json") {
-			localPath = filepath.Join(config.JsonDataDir, file)
-			s3Key = "json/" + filepath.Base(file)
-		} else {
-			log.Printf("⚠️ Unknown file category: %s", file)
-			continue
-		}
-
-		if direction == "push" {
-			err = UploadToS3(s3Client, bucket, localPath, s3Key)
-			if err != nil {
-				log.Printf("❌ Failed to upload %s: %v", file, err)
-			} else {
-				log.Printf("✅ Uploaded: %s", file)
-			}
+func matchAny(patterns []*regexp.Regexp, base []bool, key string) bool {
+	for i, re := range patterns {
+		target := key
+		if base[i] {
+			target = path.Base(key)
 		}
-
-		if direction == "pull" {
-			err = DownloadFromS3(s3Client, bucket, s3Key, localPath)
-			if err != nil {
-				log.Printf("❌ Failed to download %s: %v", file, err)
-			} else {
-				log.Printf("✅ Downloaded: %s", file)
-			}
+		if re.MatchString(target) {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
-func DetectChanges(localMeta, remoteMeta map[string]string, source string) []string {
-	var filesToSync []string
+// Match - キーが同期対象か判定（include が空なら全て対象。exclude が優先）
+func (f *SyncFilter) Match(key string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, f.includeBase, key) {
+		return false
+	}
+	return !matchAny(f.exclude, f.excludeBase, key)
+}
 
-	// nil チェックを追加（panic 回避）
-	if localMeta == nil {
-		localMeta = make(map[string]string)
+// LoadManifest - 同期先の manifest を取得（存在しなければ空）
+func LoadManifest(ctx context.Context, backend SyncBackend, key string) (map[string]ManifestEntry, error) {
+	data, err := backend.Get(ctx, key)
+	if errors.Is(err, ErrObjectNotFound) {
+		return make(map[string]ManifestEntry), nil
 	}
-	if remoteMeta == nil {
-		remoteMeta = make(map[string]string)
+	if err != nil {
+		return nil, err
 	}
 
-	if source == "s3" {
-		// S3 の方が新しい場合、ダウンロード対象
-		for file, remoteTime := range remoteMeta {
-			localTime, exists := localMeta[file]
-			if !exists || localTime < remoteTime {
-				filesToSync = append(filesToSync, file)
-			}
-		}
-	} else if source == "local" {
-		// ローカルの方が新しい場合、アップロード対象
-		for file, localTime := range localMeta {
-			remoteTime, exists := remoteMeta[file]
-			if !exists || remoteTime < localTime {
-				filesToSync = append(filesToSync, file)
-			}
-		}
-	} else {
-		log.Printf("⚠️ Unknown source type: %s", source)
+	manifest := make(map[string]ManifestEntry)
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", key, err)
 	}
+	return manifest, nil
+}
 
-	return filesToSync
+// SaveManifest - manifest を同期先にアップロード
+func SaveManifest(ctx context.Context, backend SyncBackend, key string, manifest map[string]ManifestEntry) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to marshal %s: %w", key, err)
+	}
+	return backend.Put(ctx, key, data)
 }
@@ -0,0 +1,77 @@
+package util
+
+import "testing"
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"*.md", []string{"a.md", ".md"}, []string{"dir/a.md", "a.json"}},
+		{"notes/*.md", []string{"notes/a.md"}, []string{"notes/sub/a.md", "json/a.md"}},
+		{"notes/**/*.md", []string{"notes/a.md", "notes/sub/a.md", "notes/x/y/a.md"}, []string{"json/a.md"}},
+		{"notes/**", []string{"notes/a.md", "notes/sub/a.md"}, []string{"archive/a.md"}},
+		{"n?.md", []string{"n1.md"}, []string{"n12.md", "n/.md"}},
+		{"[ab].md", []string{"a.md", "b.md"}, []string{"c.md"}},
+		{"[!ab].md", []string{"c.md"}, []string{"a.md"}},
+		{"a+b.md", []string{"a+b.md"}, []string{"aab.md"}},
+	}
+
+	for _, tt := range tests {
+		re, err := globToRegexp(tt.pattern)
+		if err != nil {
+			t.Fatalf("globToRegexp(%q) error: %v", tt.pattern, err)
+		}
+		for _, s := range tt.match {
+			if !re.MatchString(s) {
+				t.Errorf("globToRegexp(%q) does not match %q", tt.pattern, s)
+			}
+		}
+		for _, s := range tt.noMatch {
+			if re.MatchString(s) {
+				t.Errorf("globToRegexp(%q) matches %q", tt.pattern, s)
+			}
+		}
+	}
+
+	if _, err := globToRegexp("[ab.md"); err == nil {
+		t.Error("globToRegexp(\"[ab.md\") returned no error for a missing ]")
+	}
+}
+
+func TestSyncFilter(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		key              string
+		want             bool
+	}{
+		{"no patterns", nil, nil, "notes/a.md", true},
+		{"include by file name", []string{"*.md"}, nil, "notes/sub/a.md", true},
+		{"not included", []string{"*.md"}, nil, "json/notes.json", false},
+		{"include by key", []string{"notes/**"}, nil, "notes/sub/a.md", true},
+		{"include by key in other area", []string{"notes/**"}, nil, "archive/a.md", false},
+		{"leading slash", []string{"/json/*.json"}, nil, "json/notes.json", true},
+		{"exclude by file name", nil, []string{"*.tmp"}, "notes/a.tmp", false},
+		{"exclude wins over include", []string{"notes/**"}, []string{"notes/private/**"}, "notes/private/a.md", false},
+		{"exclude other key", []string{"notes/**"}, []string{"notes/private/**"}, "notes/a.md", true},
+		{"blank patterns ignored", []string{" "}, []string{""}, "notes/a.md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewSyncFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewSyncFilter() error: %v", err)
+			}
+			if got := f.Match(tt.key); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewSyncFilter([]string{"[a"}, nil); err == nil {
+		t.Error("NewSyncFilter() accepted an invalid include pattern")
+	}
+}
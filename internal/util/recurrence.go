@@ -0,0 +1,262 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDate - "2025-03-01" / "today" / "tomorrow" / "+3d" / "+2w" 形式の日付を yyyy-mm-dd に変換
+func ParseDate(value string) (string, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" {
+		return "", nil
+	}
+
+	today := time.Now()
+	switch value {
+	case "today":
+		return today.Format("2006-01-02"), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	}
+
+	if strings.HasPrefix(value, "+") && len(value) > 2 {
+		n, err := strconv.Atoi(value[1 : len(value)-1])
+		if err == nil {
+			switch value[len(value)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n).Format("2006-01-02"), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n).Format("2006-01-02"), nil
+			case 'm':
+				return today.AddDate(0, n, 0).Format("2006-01-02"), nil
+			}
+		}
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, tomorrow or +Nd/+Nw/+Nm)", value)
+	}
+	return t.Format("2006-01-02"), nil
+}
+
+// RecurrenceRule - RRULE 形式（FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=5;UNTIL=20251231）の繰り返し規則
+type RecurrenceRule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int // MONTHLY / YEARLY の日（0 は前回と同じ日）。月末を超える場合はその月の末日
+	Count      int // 0 は無制限
+	Until      time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// ParseRecurrence - RRULE 文字列を解析する。"daily" などの略記も受け付ける
+func ParseRecurrence(rule string) (RecurrenceRule, error) {
+	r, _, err := parseRecurrence(rule, false)
+	return r, err
+}
+
+// ParseRecurrenceLenient - 他のアプリから取り込んだ RRULE 用。対応していない部分（WKST など）は
+// エラーにせず読み飛ばし、ignored に返す。FREQ が解釈できない場合だけエラーにする
+func ParseRecurrenceLenient(rule string) (r RecurrenceRule, ignored []string, err error) {
+	return parseRecurrence(rule, true)
+}
+
+func parseRecurrence(rule string, lenient bool) (RecurrenceRule, []string, error) {
+	r := RecurrenceRule{Interval: 1}
+
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	switch strings.ToLower(rule) {
+	case "daily", "weekly", "monthly", "yearly":
+		r.Freq = strings.ToUpper(rule)
+		return r, nil, nil
+	}
+
+	var ignored []string
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		err := r.applyPart(part)
+		if err == nil {
+			continue
+		}
+		if !lenient || strings.HasPrefix(strings.ToUpper(part), "FREQ=") {
+			return r, nil, err
+		}
+		ignored = append(ignored, part)
+	}
+
+	if r.Freq == "" {
+		return r, nil, fmt.Errorf("recurrence rule %q has no FREQ", rule)
+	}
+	return r, ignored, nil
+}
+
+// applyPart - "KEY=VALUE" を 1 つ解析して r に反映する
+func (r *RecurrenceRule) applyPart(part string) error {
+	kv := strings.SplitN(part, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("invalid recurrence rule part %q", part)
+	}
+	key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+	switch key {
+	case "FREQ":
+		if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" && value != "YEARLY" {
+			return fmt.Errorf("unsupported FREQ %q", value)
+		}
+		r.Freq = value
+	case "INTERVAL":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid INTERVAL %q", value)
+		}
+		r.Interval = n
+	case "BYDAY":
+		var days []time.Weekday
+		for _, code := range strings.Split(value, ",") {
+			day, ok := weekdayCodes[code]
+			if !ok {
+				return fmt.Errorf("invalid BYDAY %q", code)
+			}
+			days = append(days, day)
+		}
+		r.ByDay = append(r.ByDay, days...)
+	case "BYMONTHDAY":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 31 {
+			return fmt.Errorf("invalid BYMONTHDAY %q", value)
+		}
+		r.ByMonthDay = n
+	case "COUNT":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid COUNT %q", value)
+		}
+		r.Count = n
+	case "UNTIL":
+		until, err := time.Parse("20060102", value[:min(len(value), 8)])
+		if err != nil {
+			return fmt.Errorf("invalid UNTIL %q", value)
+		}
+		r.Until = until
+	default:
+		return fmt.Errorf("unsupported recurrence key %q", key)
+	}
+	return nil
+}
+
+// String - RRULE 文字列に戻す
+func (r RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, day := range r.ByDay {
+			for code, d := range weekdayCodes {
+				if d == day {
+					days = append(days, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next - from の次の発生日を返す。繰り返しが終了している場合は ok=false
+func (r RecurrenceRule) Next(from time.Time) (time.Time, bool) {
+	if r.Count == 1 {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch r.Freq {
+	case "DAILY":
+		next = from.AddDate(0, 0, r.Interval)
+	case "WEEKLY":
+		if len(r.ByDay) == 0 {
+			next = from.AddDate(0, 0, 7*r.Interval)
+			break
+		}
+		// 同じ週の残りの曜日 → なければ INTERVAL 週後の最初の曜日
+		weekStart := from.AddDate(0, 0, -int(from.Weekday()))
+		for d := from.AddDate(0, 0, 1); ; d = d.AddDate(0, 0, 1) {
+			weeks := DaysBetween(weekStart, d) / 7
+			if weeks%r.Interval == 0 && r.hasDay(d.Weekday()) {
+				next = d
+				break
+			}
+		}
+	case "MONTHLY":
+		next = addMonths(from, r.Interval, r.monthDay(from))
+	case "YEARLY":
+		next = addMonths(from, 12*r.Interval, r.monthDay(from))
+	default:
+		return time.Time{}, false
+	}
+
+	if !r.Until.IsZero() && next.After(r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Advance - 1回分消化した後の規則（COUNT を 1 減らす）
+func (r RecurrenceRule) Advance() RecurrenceRule {
+	if r.Count > 1 {
+		r.Count--
+	}
+	return r
+}
+
+// monthDay - MONTHLY / YEARLY で次に使う日
+func (r RecurrenceRule) monthDay(from time.Time) int {
+	if r.ByMonthDay > 0 {
+		return r.ByMonthDay
+	}
+	return from.Day()
+}
+
+// addMonths - months か月後の day 日。その月に day 日がなければ末日にする（1/31 の翌月は 2/28）
+func addMonths(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// DaysBetween - from から to までの暦日の差。夏時間の切り替えで 1 日が 23・25 時間になっても変わらない
+func DaysBetween(from, to time.Time) int {
+	f := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	t := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(t.Sub(f).Hours() / 24)
+}
+
+func (r RecurrenceRule) hasDay(day time.Weekday) bool {
+	for _, d := range r.ByDay {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
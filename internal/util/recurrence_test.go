@@ -0,0 +1,185 @@
+package util
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func testDate(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		from string
+		want string // 空は繰り返し終了
+	}{
+		{name: "daily", rule: "daily", from: "2025-01-06", want: "2025-01-07"},
+		{name: "daily interval", rule: "FREQ=DAILY;INTERVAL=3", from: "2025-01-30", want: "2025-02-02"},
+		{name: "weekly", rule: "weekly", from: "2025-01-06", want: "2025-01-13"},
+		{name: "weekly byday same week", rule: "FREQ=WEEKLY;BYDAY=MO,TH", from: "2025-01-06", want: "2025-01-09"},
+		{name: "weekly byday next week", rule: "FREQ=WEEKLY;BYDAY=MO,TH", from: "2025-01-09", want: "2025-01-13"},
+		{name: "weekly interval same week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", from: "2025-01-06", want: "2025-01-09"},
+		{name: "weekly interval skips a week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", from: "2025-01-09", want: "2025-01-20"},
+		{name: "weekly interval counts from sunday", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", from: "2025-01-05", want: "2025-01-19"},
+		{name: "weekly interval 3 from saturday", rule: "FREQ=WEEKLY;INTERVAL=3;BYDAY=MO", from: "2025-01-11", want: "2025-01-27"},
+		{name: "monthly", rule: "monthly", from: "2025-01-15", want: "2025-02-15"},
+		{name: "monthly clamps to month end", rule: "monthly", from: "2025-01-31", want: "2025-02-28"},
+		{name: "monthly clamps in leap year", rule: "monthly", from: "2024-01-31", want: "2024-02-29"},
+		{name: "monthly bymonthday restores after clamp", rule: "FREQ=MONTHLY;BYMONTHDAY=31", from: "2025-02-28", want: "2025-03-31"},
+		{name: "monthly bymonthday clamps", rule: "FREQ=MONTHLY;BYMONTHDAY=31", from: "2025-03-31", want: "2025-04-30"},
+		{name: "monthly interval across year", rule: "FREQ=MONTHLY;INTERVAL=3", from: "2025-11-30", want: "2026-02-28"},
+		{name: "yearly leap day", rule: "yearly", from: "2024-02-29", want: "2025-02-28"},
+		{name: "yearly bymonthday restores", rule: "FREQ=YEARLY;BYMONTHDAY=29", from: "2025-02-28", want: "2026-02-28"},
+		{name: "count remaining", rule: "FREQ=DAILY;COUNT=2", from: "2025-01-06", want: "2025-01-07"},
+		{name: "count exhausted", rule: "FREQ=DAILY;COUNT=1", from: "2025-01-06", want: ""},
+		{name: "until inclusive", rule: "FREQ=DAILY;UNTIL=20250107", from: "2025-01-06", want: "2025-01-07"},
+		{name: "until passed", rule: "FREQ=DAILY;UNTIL=20250107", from: "2025-01-07", want: ""},
+		{name: "until with time", rule: "FREQ=WEEKLY;UNTIL=20250110T235959Z", from: "2025-01-06", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error: %v", tt.rule, err)
+			}
+			next, ok := rule.Next(testDate(tt.from))
+			if tt.want == "" {
+				if ok {
+					t.Errorf("Next(%s) = %s, want the recurrence to end", tt.from, next.Format("2006-01-02"))
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Next(%s) ended, want %s", tt.from, tt.want)
+			}
+			if got := next.Format("2006-01-02"); got != tt.want {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceAdvance(t *testing.T) {
+	rule, err := ParseRecurrence("FREQ=WEEKLY;BYDAY=MO;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// COUNT=3 なら元のタスクを含めて 3 回で終わる
+	from := testDate("2025-01-06")
+	var got []string
+	for {
+		next, ok := rule.Next(from)
+		if !ok {
+			break
+		}
+		got = append(got, next.Format("2006-01-02"))
+		from, rule = next, rule.Advance()
+		if len(got) > 3 {
+			t.Fatalf("recurrence did not end: %v", got)
+		}
+	}
+	if want := []string{"2025-01-13", "2025-01-20"}; !slices.Equal(got, want) {
+		t.Errorf("occurrences = %v, want %v", got, want)
+	}
+	if rule.String() != "FREQ=WEEKLY;BYDAY=MO;COUNT=1" {
+		t.Errorf("rule after the last occurrence = %s", rule)
+	}
+
+	// COUNT がなければ変わらない
+	unlimited := RecurrenceRule{Freq: "DAILY", Interval: 1}
+	if got := unlimited.Advance(); got.Count != 0 {
+		t.Errorf("Advance() without COUNT = %+v", got)
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "daily", want: "FREQ=DAILY"},
+		{rule: "Weekly", want: "FREQ=WEEKLY"},
+		{rule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"},
+		{rule: "freq=monthly;bymonthday=31", want: "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{rule: "FREQ=DAILY;INTERVAL=1;COUNT=5;", want: "FREQ=DAILY;COUNT=5"},
+		{rule: "FREQ=YEARLY;UNTIL=20301231T000000Z", want: "FREQ=YEARLY;UNTIL=20301231"},
+		{rule: "", wantErr: true},
+		{rule: "INTERVAL=2", wantErr: true},
+		{rule: "FREQ=HOURLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{rule: "FREQ=DAILY;UNTIL=tomorrow", wantErr: true},
+		{rule: "FREQ=WEEKLY;WKST=MO", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRecurrence(tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRecurrence(%q) = %s, want an error", tt.rule, rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error: %v", tt.rule, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("ParseRecurrence(%q) = %s, want %s", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceLenient(t *testing.T) {
+	tests := []struct {
+		rule        string
+		want        string
+		wantIgnored []string
+		wantErr     bool
+	}{
+		{rule: "FREQ=WEEKLY;BYDAY=MO", want: "FREQ=WEEKLY;BYDAY=MO"},
+		{rule: "FREQ=WEEKLY;WKST=SU;BYDAY=MO,WE", want: "FREQ=WEEKLY;BYDAY=MO,WE", wantIgnored: []string{"WKST=SU"}},
+		{rule: "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1", want: "FREQ=MONTHLY;BYDAY=MO", wantIgnored: []string{"BYSETPOS=1"}},
+		// BYDAY は途中まで反映せず、まとめて読み飛ばす
+		{rule: "FREQ=MONTHLY;BYDAY=MO,-1FR", want: "FREQ=MONTHLY", wantIgnored: []string{"BYDAY=MO,-1FR"}},
+		{rule: "FREQ=DAILY;INTERVAL=0;COUNT=3", want: "FREQ=DAILY;COUNT=3", wantIgnored: []string{"INTERVAL=0"}},
+		{rule: "FREQ=HOURLY;INTERVAL=2", wantErr: true},
+		{rule: "WKST=MO", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, ignored, err := ParseRecurrenceLenient(tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRecurrenceLenient(%q) = %s, want an error", tt.rule, rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRecurrenceLenient(%q) error: %v", tt.rule, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("ParseRecurrenceLenient(%q) = %s, want %s", tt.rule, got, tt.want)
+			}
+			if !slices.Equal(ignored, tt.wantIgnored) {
+				t.Errorf("ignored = %v, want %v", ignored, tt.wantIgnored)
+			}
+		})
+	}
+}
@@ -1,88 +1,138 @@
 package util
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
+	"io"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/nakachan-ing/ztl-cli/internal/model"
 )
 
-// UploadToS3 - ローカルファイルを S3 にアップロード
-func UploadToS3(s3Client *s3.Client, bucket, filePath string, s3Key string) error {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return fmt.Errorf("❌ Failed to open file %s: %w", filePath, err)
+// S3Backend は S3（および MinIO などの S3 互換ストレージ）を同期先にする
+type S3Backend struct {
+	client   *s3.Client
+	bucket   string
+	endpoint string
+}
+
+// Name - エンドポイントが違えば同じバケット名でも別の同期先として扱う
+func (b *S3Backend) Name() string {
+	if b.endpoint != "" {
+		return strings.TrimSuffix(b.endpoint, "/") + "/" + b.bucket
 	}
-	defer file.Close()
+	return "s3://" + b.bucket
+}
 
-	_, err = s3Client.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(s3Key),
-		Body:   file,
+// Put - オブジェクトを S3 にアップロード
+func (b *S3Backend) Put(ctx context.Context, key string, data []byte) error {
+	_, err := b.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
 	})
 	if err != nil {
-		return fmt.Errorf("❌ Failed to upload %s to S3: %w", s3Key, err)
+		return fmt.Errorf("❌ Failed to upload %s to S3: %w", key, err)
 	}
-
-	log.Printf("✅ Uploaded %s to S3", s3Key)
 	return nil
 }
 
-// DownloadFromS3 - S3 からローカルにファイルをダウンロード
-func DownloadFromS3(s3Client *s3.Client, bucket, s3Key string, localPath string) error {
-	resp, err := s3Client.GetObject(context.TODO(), &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(s3Key),
+// Get - S3 からオブジェクトをダウンロード
+func (b *S3Backend) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
 	})
 	if err != nil {
-		return fmt.Errorf("❌ Failed to download %s from S3: %w", s3Key, err)
+		if isNotFoundErr(err) {
+			return nil, fmt.Errorf("%s: %w", key, ErrObjectNotFound)
+		}
+		return nil, fmt.Errorf("❌ Failed to download %s from S3: %w", key, err)
 	}
 	defer resp.Body.Close()
 
-	// 保存先ディレクトリが存在しない場合は作成
-	localDir := filepath.Dir(localPath)
-	if err := os.MkdirAll(localDir, os.ModePerm); err != nil {
-		return fmt.Errorf("❌ Failed to create directory %s: %w", localDir, err)
-	}
-
-	file, err := os.Create(localPath)
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("❌ Failed to create file %s: %w", localPath, err)
+		return nil, fmt.Errorf("❌ Failed to read %s from S3: %w", key, err)
 	}
-	defer file.Close()
+	return data, nil
+}
 
-	_, err = file.ReadFrom(resp.Body)
+// Delete - S3 のオブジェクトを削除（存在しなくても成功する）
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	_, err := b.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
 	if err != nil {
-		return fmt.Errorf("❌ Failed to write file %s: %w", localPath, err)
+		return fmt.Errorf("❌ Failed to delete %s from S3: %w", key, err)
 	}
-
-	log.Printf("✅ Downloaded %s from S3", s3Key)
 	return nil
 }
 
+// List - prefix で始まるキーを列挙
+func (b *S3Backend) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(b.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to list %s on S3: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+	return keys, nil
+}
+
 func isNotFoundErr(err error) bool {
 	var s3Err *types.NoSuchKey
-	return errors.As(err, &s3Err)
+	var notFound *types.NotFound
+	return errors.As(err, &s3Err) || errors.As(err, &notFound)
 }
 
-func NewS3Client(ztlConfig model.Config) (*s3.Client, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
+// NewS3Backend - config の aws_profile / aws_region / endpoint / path_style から S3 クライアントを作る。
+// スロットリング・5xx・通信エラーは SDK の再試行（回数と待ち時間の上限は RetryBackend と同じ）に任せる
+func NewS3Backend(ctx context.Context, ztlConfig model.Config) (*S3Backend, error) {
+	if ztlConfig.Sync.Bucket == "" {
+		return nil, fmt.Errorf("❌ sync.bucket is not set")
+	}
+
+	region := ztlConfig.Sync.AWSRegion
+	if region == "" && ztlConfig.Sync.Endpoint != "" {
+		region = "us-east-1" // MinIO はリージョンを使わないが SDK は署名に必要とする
+	}
+	cfg, err := config.LoadDefaultConfig(ctx,
 		config.WithSharedConfigProfile(ztlConfig.Sync.AWSProfile),
-		config.WithRegion(ztlConfig.Sync.AWSRegion),
+		config.WithRegion(region),
+		config.WithRetryer(func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = syncRetryAttempts
+				o.MaxBackoff = syncRetryMaxDelay
+			})
+		}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load SDK config, %v", err)
 	}
 
-	s3Client := s3.NewFromConfig(cfg)
+	s3Client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if ztlConfig.Sync.Endpoint != "" {
+			o.BaseEndpoint = aws.String(ztlConfig.Sync.Endpoint)
+		}
+		o.UsePathStyle = ztlConfig.Sync.PathStyle
+	})
 
-	return s3Client, nil
+	return &S3Backend{client: s3Client, bucket: ztlConfig.Sync.Bucket, endpoint: ztlConfig.Sync.Endpoint}, nil
 }
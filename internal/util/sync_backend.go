@@ -0,0 +1,234 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nakachan-ing/ztl-cli/internal/model"
+)
+
+// ErrObjectNotFound は同期先にオブジェクトが存在しない場合のエラー
+var ErrObjectNotFound = errors.New("object not found")
+
+// SyncBackend は同期先のストレージ。キーは "notes/20250101120000.md" のような "/" 区切りのパス
+type SyncBackend interface {
+	// Name はログ表示用の同期先（s3://bucket, file:///mnt/ztl など）
+	Name() string
+	Put(ctx context.Context, key string, data []byte) error
+	// Get はオブジェクトを取得する。存在しない場合は ErrObjectNotFound を返す
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete はオブジェクトを削除する。存在しなくてもエラーにしない
+	Delete(ctx context.Context, key string) error
+	// List は prefix で始まるキーを昇順で返す
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// NewSyncBackend - config の sync.platform に応じて同期先を作る。一時的なエラーは再試行する
+// （S3 は SDK の再試行を使い、ローカルディレクトリは RetryBackend で再試行する）
+func NewSyncBackend(ctx context.Context, config model.Config) (SyncBackend, error) {
+	switch strings.ToLower(config.Sync.Platform) {
+	case "", "s3", "aws":
+		return NewS3Backend(ctx, config)
+	case "minio":
+		if config.Sync.Endpoint == "" {
+			return nil, fmt.Errorf("❌ sync.endpoint is required for platform minio")
+		}
+		config.Sync.PathStyle = true // MinIO はパス形式でしかバケットを指定できない構成が多い
+		return NewS3Backend(ctx, config)
+	case "local", "nfs", "dir":
+		if config.Sync.Path == "" {
+			return nil, fmt.Errorf("❌ sync.path is required for platform %s", config.Sync.Platform)
+		}
+		return NewRetryBackend(NewLocalBackend(config.Sync.Path), syncRetryAttempts, syncRetryDelay), nil
+	}
+	return nil, fmt.Errorf("❌ Unknown sync platform: %s. Must be s3, minio or local", config.Sync.Platform)
+}
+
+// LocalBackend はローカルディレクトリ（NFS などのマウント先を含む）を同期先にする
+type LocalBackend struct {
+	Root string
+}
+
+func NewLocalBackend(root string) *LocalBackend {
+	return &LocalBackend{Root: root}
+}
+
+func (b *LocalBackend) Name() string {
+	return "file://" + filepath.ToSlash(b.Root)
+}
+
+// objectPath はキーをディレクトリ外に出ないパスに変換する
+func (b *LocalBackend) objectPath(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid key: %q", key)
+	}
+	return filepath.Join(b.Root, filepath.FromSlash(clean)), nil
+}
+
+func (b *LocalBackend) Put(ctx context.Context, key string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	dest, err := b.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("❌ Failed to create directory for %s: %w", key, err)
+	}
+
+	// 途中で中断しても壊れたファイルを残さないよう、一時ファイルに書いてから置き換える
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".ztl-sync-*")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to write %s: %w", key, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("❌ Failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("❌ Failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("❌ Failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+func (b *LocalBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	src, err := b.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", key, ErrObjectNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (b *LocalBackend) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	target, err := b.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("❌ Failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (b *LocalBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(b.Root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == b.Root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".ztl-sync-") {
+			return nil
+		}
+		rel, err := filepath.Rel(b.Root, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to list %s: %w", b.Name(), err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// MemoryBackend はメモリ上の同期先（テスト用。sync.platform では選べない）
+type MemoryBackend struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{objects: make(map[string][]byte)}
+}
+
+func (b *MemoryBackend) Name() string {
+	return "memory://"
+}
+
+func (b *MemoryBackend) Put(ctx context.Context, key string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (b *MemoryBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", key, ErrObjectNotFound)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (b *MemoryBackend) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+func (b *MemoryBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var keys []string
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
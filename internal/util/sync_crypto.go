@@ -0,0 +1,296 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// 同期先の直下に置く暗号化の設定（鍵そのものは含まない）
+const EncryptionParamsName = "encryption.json"
+
+const (
+	CipherNone      = "none" // 暗号化なし（rekey で既存のファイルを暗号化するときの移行元）
+	CipherAESGCM    = "aes-256-gcm"
+	KDFPassphrase   = "pbkdf2-sha256"
+	KDFKeyFile      = "keyfile"
+	encryptionMagic = "ZTLENC1\n" // 暗号化したオブジェクトの先頭に付ける印
+	keyIDSize       = 8
+	keySize         = 32
+	saltSize        = 16
+	kdfIterations   = 600_000
+	keyCheckText    = "ztl-key-check"
+)
+
+// EncryptionParams は鍵の導出方法と、鍵が正しいか確かめるための値
+type EncryptionParams struct {
+	Cipher     string            `json:"cipher"`
+	KDF        string            `json:"kdf,omitempty"`
+	Iterations int               `json:"iterations,omitempty"`
+	Salt       string            `json:"salt,omitempty"` // base64
+	KeyID      string            `json:"key_id,omitempty"`
+	Check      string            `json:"check,omitempty"`    // keyCheckText を暗号化したもの（base64）
+	Rekeying   *EncryptionParams `json:"rekeying,omitempty"` // rekey の途中なら移行元の鍵
+	CreatedAt  string            `json:"created_at,omitempty"`
+}
+
+// KeySource は鍵の元になるパスフレーズか鍵ファイル
+type KeySource struct {
+	Passphrase string
+	KeyFile    string
+}
+
+// deriveKey - params の方法で鍵を導出する
+func deriveKey(p EncryptionParams, src KeySource) ([]byte, error) {
+	switch p.KDF {
+	case KDFPassphrase:
+		if src.Passphrase == "" {
+			return nil, fmt.Errorf("❌ The sync target is encrypted with a passphrase, but no passphrase was given")
+		}
+		salt, err := base64.StdEncoding.DecodeString(p.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Invalid salt in %s: %w", EncryptionParamsName, err)
+		}
+		return pbkdf2.Key([]byte(src.Passphrase), salt, p.Iterations, keySize, sha256.New), nil
+	case KDFKeyFile:
+		if src.KeyFile == "" {
+			return nil, fmt.Errorf("❌ The sync target is encrypted with a key file, but no key file was given")
+		}
+		data, err := os.ReadFile(src.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to read key file: %w", err)
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("❌ Key file %s is empty", src.KeyFile)
+		}
+		sum := sha256.Sum256(data)
+		return sum[:], nil
+	}
+	return nil, fmt.Errorf("❌ Unknown key derivation in %s: %s", EncryptionParamsName, p.KDF)
+}
+
+// SyncCipher はオブジェクトごとの暗号化・復号。オブジェクトのキーを追加データにし、別のキーへの差し替えも検出する
+type SyncCipher struct {
+	keyID []byte
+	aead  cipher.AEAD // nil なら暗号化しない
+}
+
+func newSyncCipher(p EncryptionParams, key []byte) (*SyncCipher, error) {
+	if p.Cipher == CipherNone {
+		return &SyncCipher{}, nil
+	}
+	if p.Cipher != CipherAESGCM {
+		return nil, fmt.Errorf("❌ Unknown cipher in %s: %s", EncryptionParamsName, p.Cipher)
+	}
+	keyID, err := hex.DecodeString(p.KeyID)
+	if err != nil || len(keyID) != keyIDSize {
+		return nil, fmt.Errorf("❌ Invalid key_id in %s", EncryptionParamsName)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SyncCipher{keyID: keyID, aead: aead}, nil
+}
+
+// Seal - 印・鍵 ID・nonce・暗号文を連結して返す
+func (c *SyncCipher) Seal(objectKey string, plain []byte) ([]byte, error) {
+	if c.aead == nil {
+		return plain, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("❌ Failed to generate nonce: %w", err)
+	}
+	out := make([]byte, 0, len(encryptionMagic)+keyIDSize+len(nonce)+len(plain)+c.aead.Overhead())
+	out = append(out, encryptionMagic...)
+	out = append(out, c.keyID...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plain, []byte(objectKey)), nil
+}
+
+// Open - Seal した内容を復号する
+func (c *SyncCipher) Open(objectKey string, data []byte) ([]byte, error) {
+	keyID, encrypted := ObjectKeyID(data)
+	if c.aead == nil {
+		if encrypted {
+			return nil, fmt.Errorf("❌ %s is encrypted", objectKey)
+		}
+		return data, nil
+	}
+	if !encrypted {
+		return nil, fmt.Errorf("❌ %s is not encrypted. Run `ztl sync rekey` to encrypt existing files", objectKey)
+	}
+	if !bytes.Equal(keyID, c.keyID) {
+		return nil, fmt.Errorf("❌ %s is encrypted with another key. Run `ztl sync rekey` to finish re-encryption", objectKey)
+	}
+
+	rest := data[len(encryptionMagic)+keyIDSize:]
+	if len(rest) < c.aead.NonceSize() {
+		return nil, fmt.Errorf("❌ %s is truncated", objectKey)
+	}
+	nonce, sealed := rest[:c.aead.NonceSize()], rest[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, []byte(objectKey))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to decrypt %s (wrong key or corrupted): %w", objectKey, err)
+	}
+	return plain, nil
+}
+
+// HasKeyID - 同じ鍵で暗号化されているか
+func (c *SyncCipher) HasKeyID(data []byte) bool {
+	keyID, encrypted := ObjectKeyID(data)
+	if c.aead == nil {
+		return !encrypted
+	}
+	return encrypted && bytes.Equal(keyID, c.keyID)
+}
+
+// ObjectKeyID - 暗号化されたオブジェクトの鍵 ID を返す
+func ObjectKeyID(data []byte) ([]byte, bool) {
+	if len(data) < len(encryptionMagic)+keyIDSize || !bytes.HasPrefix(data, []byte(encryptionMagic)) {
+		return nil, false
+	}
+	return data[len(encryptionMagic) : len(encryptionMagic)+keyIDSize], true
+}
+
+// NewEncryptionParams - 新しい鍵の設定を作る（パスフレーズならソルトを、鍵ファイルなら鍵 ID だけを新しくする）
+func NewEncryptionParams(src KeySource) (EncryptionParams, *SyncCipher, error) {
+	p := EncryptionParams{Cipher: CipherAESGCM, CreatedAt: time.Now().Format("2006-01-02 15:04:05")}
+	if src.KeyFile != "" {
+		p.KDF = KDFKeyFile
+	} else {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return p, nil, fmt.Errorf("❌ Failed to generate salt: %w", err)
+		}
+		p.KDF = KDFPassphrase
+		p.Iterations = kdfIterations
+		p.Salt = base64.StdEncoding.EncodeToString(salt)
+	}
+	keyID := make([]byte, keyIDSize)
+	if _, err := rand.Read(keyID); err != nil {
+		return p, nil, fmt.Errorf("❌ Failed to generate key ID: %w", err)
+	}
+	p.KeyID = hex.EncodeToString(keyID)
+
+	key, err := deriveKey(p, src)
+	if err != nil {
+		return p, nil, err
+	}
+	c, err := newSyncCipher(p, key)
+	if err != nil {
+		return p, nil, err
+	}
+	check, err := c.Seal(EncryptionParamsName, []byte(keyCheckText))
+	if err != nil {
+		return p, nil, err
+	}
+	p.Check = base64.StdEncoding.EncodeToString(check)
+	return p, c, nil
+}
+
+// OpenEncryptionParams - 鍵を導出し、params の確認用の値で正しい鍵か確かめる
+func OpenEncryptionParams(p EncryptionParams, src KeySource) (*SyncCipher, error) {
+	if p.Cipher == CipherNone {
+		return &SyncCipher{}, nil
+	}
+	key, err := deriveKey(p, src)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newSyncCipher(p, key)
+	if err != nil {
+		return nil, err
+	}
+	check, err := base64.StdEncoding.DecodeString(p.Check)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Invalid check value in %s: %w", EncryptionParamsName, err)
+	}
+	if plain, err := c.Open(EncryptionParamsName, check); err != nil || string(plain) != keyCheckText {
+		if p.KDF == KDFKeyFile {
+			return nil, fmt.Errorf("❌ Wrong key file for the sync target")
+		}
+		return nil, fmt.Errorf("❌ Wrong passphrase for the sync target")
+	}
+	return c, nil
+}
+
+// LoadEncryptionParams - 同期先の暗号化の設定を取得（暗号化していなければ nil）
+func LoadEncryptionParams(ctx context.Context, backend SyncBackend) (*EncryptionParams, error) {
+	data, err := backend.Get(ctx, EncryptionParamsName)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p EncryptionParams
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", EncryptionParamsName, err)
+	}
+	return &p, nil
+}
+
+// SaveEncryptionParams - 暗号化の設定を同期先にアップロード
+func SaveEncryptionParams(ctx context.Context, backend SyncBackend, p EncryptionParams) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to marshal %s: %w", EncryptionParamsName, err)
+	}
+	return backend.Put(ctx, EncryptionParamsName, data)
+}
+
+// EncryptedBackend はアップロード前に暗号化し、ダウンロード後に復号する。キー（ファイル名）は暗号化しない
+type EncryptedBackend struct {
+	inner  SyncBackend
+	cipher *SyncCipher
+}
+
+func NewEncryptedBackend(inner SyncBackend, c *SyncCipher) *EncryptedBackend {
+	return &EncryptedBackend{inner: inner, cipher: c}
+}
+
+func (b *EncryptedBackend) Name() string {
+	return b.inner.Name()
+}
+
+func (b *EncryptedBackend) Put(ctx context.Context, key string, data []byte) error {
+	sealed, err := b.cipher.Seal(key, data)
+	if err != nil {
+		return err
+	}
+	return b.inner.Put(ctx, key, sealed)
+}
+
+func (b *EncryptedBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := b.inner.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return b.cipher.Open(key, data)
+}
+
+func (b *EncryptedBackend) Delete(ctx context.Context, key string) error {
+	return b.inner.Delete(ctx, key)
+}
+
+func (b *EncryptedBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	return b.inner.List(ctx, prefix)
+}
@@ -0,0 +1,108 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sync.key")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDeriveKeyPassphrase(t *testing.T) {
+	// RFC 7914 の PBKDF2-HMAC-SHA256 のテストベクタ（先頭 32 バイト）
+	p := EncryptionParams{KDF: KDFPassphrase, Iterations: 1, Salt: base64.StdEncoding.EncodeToString([]byte("salt"))}
+	key, err := deriveKey(p, KeySource{Passphrase: "passwd"})
+	if err != nil {
+		t.Fatalf("deriveKey() error: %v", err)
+	}
+	want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"
+	if got := hex.EncodeToString(key); got != want {
+		t.Errorf("deriveKey() = %s, want %s", got, want)
+	}
+}
+
+func TestSyncCipherSealOpen(t *testing.T) {
+	_, c, err := NewEncryptionParams(KeySource{KeyFile: writeKeyFile(t, "secret")})
+	if err != nil {
+		t.Fatalf("NewEncryptionParams() error: %v", err)
+	}
+
+	plain := []byte("---\ntitle: note\n---\n\nbody\n")
+	sealed, err := c.Seal("notes/a.md", plain)
+	if err != nil {
+		t.Fatalf("Seal() error: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("body")) {
+		t.Error("Seal() output contains the plain text")
+	}
+	if !c.HasKeyID(sealed) {
+		t.Error("HasKeyID() = false for data sealed with the same key")
+	}
+
+	got, err := c.Open("notes/a.md", sealed)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Open() = %q, want %q", got, plain)
+	}
+
+	// 別のキーに差し替えたもの・改ざんしたものは復号できない
+	if _, err := c.Open("notes/b.md", sealed); err == nil {
+		t.Error("Open() accepted data sealed for another object key")
+	}
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := c.Open("notes/a.md", tampered); err == nil {
+		t.Error("Open() accepted tampered data")
+	}
+	if _, err := c.Open("notes/a.md", plain); err == nil {
+		t.Error("Open() accepted unencrypted data")
+	}
+}
+
+func TestSyncCipherWrongKey(t *testing.T) {
+	params, c, err := NewEncryptionParams(KeySource{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("NewEncryptionParams() error: %v", err)
+	}
+
+	if _, err := OpenEncryptionParams(params, KeySource{Passphrase: "wrong horse"}); err == nil ||
+		!strings.Contains(err.Error(), "Wrong passphrase") {
+		t.Errorf("OpenEncryptionParams() with a wrong passphrase error = %v", err)
+	}
+	opened, err := OpenEncryptionParams(params, KeySource{Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("OpenEncryptionParams() error: %v", err)
+	}
+
+	sealed, err := c.Seal("json/notes.json", []byte("[]"))
+	if err != nil {
+		t.Fatalf("Seal() error: %v", err)
+	}
+	if got, err := opened.Open("json/notes.json", sealed); err != nil || string(got) != "[]" {
+		t.Errorf("Open() = %q, %v, want \"[]\"", got, err)
+	}
+
+	_, other, err := NewEncryptionParams(KeySource{KeyFile: writeKeyFile(t, "other")})
+	if err != nil {
+		t.Fatalf("NewEncryptionParams() error: %v", err)
+	}
+	if other.HasKeyID(sealed) {
+		t.Error("HasKeyID() = true for data sealed with another key")
+	}
+	if _, err := other.Open("json/notes.json", sealed); err == nil {
+		t.Error("Open() with another key succeeded")
+	}
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// 一時的なエラー（通信の切断・スロットリングなど）の再試行
+const (
+	syncRetryAttempts = 4
+	syncRetryDelay    = 500 * time.Millisecond
+	syncRetryMaxDelay = 8 * time.Second
+)
+
+// RetryBackend は一時的なエラーで失敗した操作を指数バックオフで再試行する
+type RetryBackend struct {
+	inner     SyncBackend
+	attempts  int
+	baseDelay time.Duration
+}
+
+func NewRetryBackend(inner SyncBackend, attempts int, baseDelay time.Duration) *RetryBackend {
+	return &RetryBackend{inner: inner, attempts: attempts, baseDelay: baseDelay}
+}
+
+// transientErrnos - ファイルシステム（NFS など）の一時的なエラー
+var transientErrnos = []error{
+	syscall.EAGAIN, syscall.EINTR, syscall.EIO, syscall.ESTALE, syscall.ETIMEDOUT,
+	syscall.ECONNRESET, syscall.ECONNREFUSED, syscall.ECONNABORTED, syscall.EPIPE,
+}
+
+// retryable - 一時的なエラー（タイムアウト・接続の切断・スロットリング・5xx）だけを再試行する。
+// 存在しない・権限がない・中断されたなど、再試行しても結果が変わらないエラーはそのまま返す
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrObjectNotFound) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		code := httpErr.HTTPStatusCode()
+		return code == http.StatusTooManyRequests || code >= 500
+	}
+	for _, errno := range transientErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *RetryBackend) do(ctx context.Context, fn func() error) error {
+	delay := b.baseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= b.attempts || !retryable(ctx, err) {
+			return err
+		}
+
+		// 同時に失敗した転送が一斉に再試行しないよう揺らぎを入れる
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		delay = min(delay*2, syncRetryMaxDelay)
+	}
+}
+
+func (b *RetryBackend) Name() string {
+	return b.inner.Name()
+}
+
+func (b *RetryBackend) Put(ctx context.Context, key string, data []byte) error {
+	return b.do(ctx, func() error { return b.inner.Put(ctx, key, data) })
+}
+
+func (b *RetryBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := b.do(ctx, func() error {
+		var err error
+		data, err = b.inner.Get(ctx, key)
+		return err
+	})
+	return data, err
+}
+
+func (b *RetryBackend) Delete(ctx context.Context, key string) error {
+	return b.do(ctx, func() error { return b.inner.Delete(ctx, key) })
+}
+
+func (b *RetryBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := b.do(ctx, func() error {
+		var err error
+		keys, err = b.inner.List(ctx, prefix)
+		return err
+	})
+	return keys, err
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type statusError int
+
+func (e statusError) Error() string       { return fmt.Sprintf("status %d", int(e)) }
+func (e statusError) HTTPStatusCode() int { return int(e) }
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"not found", fmt.Errorf("get: %w", ErrObjectNotFound), false},
+		{"permission", &fs.PathError{Op: "open", Path: "x", Err: fs.ErrPermission}, false},
+		{"no space", &fs.PathError{Op: "write", Path: "x", Err: syscall.ENOSPC}, false},
+		{"canceled", context.Canceled, false},
+		{"stale nfs handle", &fs.PathError{Op: "open", Path: "x", Err: syscall.ESTALE}, true},
+		{"deadline", os.ErrDeadlineExceeded, true},
+		{"throttled", statusError(429), true},
+		{"server error", statusError(503), true},
+		{"forbidden", statusError(403), false},
+	}
+
+	for _, tt := range tests {
+		if got := retryable(context.Background(), tt.err); got != tt.want {
+			t.Errorf("retryable(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if retryable(ctx, statusError(503)) {
+		t.Error("retryable() retried after the context was canceled")
+	}
+}